@@ -12,9 +12,14 @@ import (
 	"github.com/whitekid/go-todo/config"
 	_ "github.com/whitekid/go-todo/docs" // swagger docs
 	"github.com/whitekid/go-todo/handlers/auth"
+	"github.com/whitekid/go-todo/handlers/filter"
+	"github.com/whitekid/go-todo/handlers/list"
 	"github.com/whitekid/go-todo/handlers/oauth"
 	"github.com/whitekid/go-todo/handlers/todo"
+	"github.com/whitekid/go-todo/handlers/user"
+	"github.com/whitekid/go-todo/httphandler"
 	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/storage/blob"
 	. "github.com/whitekid/go-todo/types"
 	"github.com/whitekid/go-utils/service"
 )
@@ -26,8 +31,14 @@ func New() service.Interface {
 		panic(err)
 	}
 
+	blobs, err := blob.NewLocal(config.AttachmentDir())
+	if err != nil {
+		panic(err)
+	}
+
 	return &todoService{
 		storage: storage,
+		blobs:   blobs,
 	}
 }
 
@@ -36,8 +47,12 @@ type HTTPError = echo.HTTPError
 
 type todoService struct {
 	storage storage.Interface
+	blobs   blob.BlobStore
 }
 
+// sweepInterval is interval to remove contents of attachments which are deleted with their items or users
+const sweepInterval = 10 * time.Minute
+
 // @title TODO API
 // @version 1.0
 // @description This is a simple todo API service.
@@ -51,6 +66,22 @@ func (s *todoService) Serve(ctx context.Context, args ...string) (err error) {
 
 	defer s.storage.Close()
 
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := todo.SweepBlobs(s.storage, s.blobs); err != nil {
+					log.Printf("sweep failed: %s", err)
+				}
+			}
+		}
+	}()
+
 	go func() {
 		<-ctx.Done()
 
@@ -71,17 +102,22 @@ func (s *todoService) setupRoute() *echo.Echo {
 	e.Use(middleware.LoggerWithConfig(loggerConfig))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization,
+			todo.HeaderIfMatch},
+		ExposeHeaders: []string{echo.HeaderAuthorization, todo.HeaderETag, httphandler.HeaderLink},
 	}))
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			cc := &Context{c}
+			cc := &Context{Context: c}
 			return next(cc)
 		}
 	})
 
-	todo.New(s.storage).Route(e.Group(""))
+	todo.New(s.storage, s.blobs).Route(e.Group(""))
+	list.New(s.storage).Route(e.Group("/lists"))
 	auth.New(s.storage).Route(e.Group("/auth"))
+	user.New(s.storage).Route(e.Group("/users"))
+	filter.New(s.storage).Route(e.Group("/filters"))
 	oauth.New(s.storage, oauth.Options{
 		ClientID:     config.ClientID(),
 		ClientSecret: config.ClientSecret(),
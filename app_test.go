@@ -2,30 +2,41 @@ package todo
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/whitekid/go-todo/client"
 	"github.com/whitekid/go-todo/config"
 	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/storage/blob"
+	"github.com/whitekid/go-todo/storage/memory"
 	"github.com/whitekid/go-todo/tokens"
 	"github.com/whitekid/go-utils"
 )
 
 func newTestServer(t *testing.T) (*httptest.Server, string, func()) {
-	s := New().(*todoService)
+	stg, err := memory.New("")
+	require.NoError(t, err)
+
+	blobs, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	s := &todoService{storage: stg, blobs: blobs}
 	e := s.setupRoute()
 
 	email := utils.RandomString(5) + "@domain.com"
-	refreshToken, err := tokens.New(email, config.RefreshTokenDuration())
-	require.NoError(t, err)
-	require.NoError(t, s.storage.TokenService().Create(email, refreshToken))
+	require.NoError(t, s.storage.UserService().Create(&models.User{Email: email}))
 	accessToken, err := tokens.New(email, config.AccessTokenDuration())
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(e)
 	return ts, accessToken, func() {
 		s.storage.UserService().Delete(email)
+		s.storage.Close()
 		ts.Close()
 	}
 }
@@ -40,7 +51,7 @@ func TestTodo(t *testing.T) {
 		Rank:    1,
 	}
 
-	api := client.New(ts.URL, token)
+	api := client.NewWithAccessToken(ts.URL, token)
 
 	// create
 	var created *models.Item
@@ -50,7 +61,9 @@ func TestTodo(t *testing.T) {
 
 		created = item
 		require.NotEqual(t, "", created.ID)
+		require.False(t, created.CreatedAt.IsZero())
 		item.ID = created.ID
+		item.CreatedAt = created.CreatedAt
 		require.Equal(t, item, created)
 	}
 
@@ -73,17 +86,88 @@ func TestTodo(t *testing.T) {
 
 	// update
 	{
-		item := created
+		item := *created
 		item.Title = "updated title"
+		item.CreatedAt = time.Time{} // ignored
 
-		updated, err := api.TodoService().Update(item)
+		updated, err := api.TodoService().Update(&item)
 		require.NoError(t, err)
-		require.Equal(t, updated, item)
+		require.Equal(t, "updated title", updated.Title)
+		require.Equal(t, created.Revision+1, updated.Revision)
+		require.Equal(t, created.CreatedAt, updated.CreatedAt)
+		require.False(t, updated.UpdatedAt.Before(created.UpdatedAt))
 
 		reterived, err := api.TodoService().Get(updated.ID)
 		require.NoError(t, err)
 
 		require.Equal(t, updated, reterived)
+
+		// update with stale revision
+		stale := *created
+		stale.Title = "stale title"
+		_, err = api.TodoService().Update(&stale)
+		require.True(t, client.IsConflict(err), "%+v", err)
+	}
+
+	// patch
+	{
+		before, err := api.TodoService().Get(created.ID)
+		require.NoError(t, err)
+
+		patched, err := api.TodoService().Patch(created.ID, map[string]interface{}{"title": "patched title", "revision": 100})
+		require.NoError(t, err)
+		require.Equal(t, "patched title", patched.Title)
+		require.Equal(t, before.DueDate, patched.DueDate)
+		require.Equal(t, before.Rank, patched.Rank)
+		require.Equal(t, before.Revision+1, patched.Revision, "revision is managed by server")
+
+		got, err := api.TodoService().Get(created.ID)
+		require.NoError(t, err)
+		require.Equal(t, patched, got)
+
+		_, err = api.TodoService().Patch(created.ID, map[string]interface{}{"title": nil})
+		require.Error(t, err, "title is required")
+
+		_, err = api.TodoService().Patch(created.ID, map[string]interface{}{"id": "c9be58c3-e164-42de-a301-8ad3fdbf553b"})
+		require.Error(t, err, "id can not be changed")
+
+		_, err = api.TodoService().Patch("not-exists", map[string]interface{}{"title": "title"})
+		require.Error(t, err)
+	}
+
+	// complete & reopen
+	{
+		completed, err := api.TodoService().Complete(created.ID)
+		require.NoError(t, err)
+		require.True(t, completed.Completed)
+		require.NotNil(t, completed.CompletedAt)
+
+		// complete again keeps completed time
+		again, err := api.TodoService().Complete(created.ID)
+		require.NoError(t, err)
+		require.Equal(t, completed, again)
+
+		items, _, err := api.TodoService().ListWithOptions(&models.ListOptions{Status: "open"})
+		require.NoError(t, err)
+		require.Equal(t, 0, len(items))
+
+		items, _, err = api.TodoService().ListWithOptions(&models.ListOptions{Status: "completed"})
+		require.NoError(t, err)
+		require.Equal(t, []models.Item{*completed}, items)
+
+		// update does not change completion state
+		completed.Completed = false
+		updated, err := api.TodoService().Update(completed)
+		require.NoError(t, err)
+		require.True(t, updated.Completed)
+
+		reopened, err := api.TodoService().Reopen(created.ID)
+		require.NoError(t, err)
+		require.False(t, reopened.Completed)
+		require.Nil(t, reopened.CompletedAt)
+
+		_, err = api.TodoService().Complete("not-exists")
+		require.Error(t, err)
 	}
 
 	// delete
@@ -111,12 +195,13 @@ func TestCreate(t *testing.T) {
 		wantFail bool
 	}{
 		{"", args{models.Item{Title: "title", DueDate: models.Today(), Rank: 1}}, false, false},
+		{"metadata ignored", args{models.Item{Title: "title", DueDate: models.Today(), Revision: 10, Completed: true}}, false, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ts, token, teardown := newTestServer(t)
 			defer teardown()
-			api := client.New(ts.URL, token)
+			api := client.NewWithAccessToken(ts.URL, token)
 
 			created, err := api.TodoService().Create(&tt.args.item)
 			if (err != nil) != tt.wantErr {
@@ -124,8 +209,19 @@ func TestCreate(t *testing.T) {
 			}
 
 			require.NotEqual(t, "", created.ID)
-			tt.args.item.ID = created.ID
-			require.Equal(t, &tt.args.item, created)
+			require.Equal(t, 1, created.Revision)
+			require.False(t, created.Completed)
+			require.Nil(t, created.CompletedAt)
+			require.WithinDuration(t, time.Now(), created.CreatedAt, time.Minute)
+			require.Equal(t, created.CreatedAt, created.UpdatedAt)
+
+			want := tt.args.item
+			want.ID = created.ID
+			want.CreatedAt = created.CreatedAt
+			want.UpdatedAt = created.UpdatedAt
+			want.Revision = created.Revision
+			want.Completed = created.Completed
+			require.Equal(t, &want, created)
 
 			got, err := api.TodoService().Get(created.ID)
 			require.NoError(t, err)
@@ -141,30 +237,72 @@ func TestCreate(t *testing.T) {
 }
 
 func TestList(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+
+	titles := []string{"c", "a", "e", "b", "d"}
+	for i, title := range titles {
+		_, err := api.TodoService().Create(&models.Item{Title: title, DueDate: models.Today(), Rank: len(titles) - i})
+		require.NoError(t, err)
+	}
+
+	today := models.Today()
 	type args struct {
+		opts models.ListOptions
 	}
 	tests := [...]struct {
 		name    string
 		args    args
+		want    []string
 		wantErr bool
 	}{
-		{"", args{}, false},
+		{"default", args{models.ListOptions{}}, []string{"d", "b", "e", "a", "c"}, false},
+		{"sort by created", args{models.ListOptions{Sort: "created"}}, titles, false},
+		{"sort by title", args{models.ListOptions{Sort: "title"}}, []string{"a", "b", "c", "d", "e"}, false},
+		{"sort by rank", args{models.ListOptions{Sort: "rank"}}, []string{"d", "b", "e", "a", "c"}, false},
+		{"due after", args{models.ListOptions{DueAfter: &today}}, []string{}, false},
+		{"invalid sort", args{models.ListOptions{Sort: "unknown"}}, nil, true},
+		{"invalid cursor", args{models.ListOptions{Cursor: "invalid"}}, nil, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ts, token, teardown := newTestServer(t)
-			defer teardown()
-			api := client.New(ts.URL, token)
-
-			got, err := api.TodoService().List()
+			got, next, err := api.TodoService().ListWithOptions(&tt.args.opts)
 			if (err != nil) != tt.wantErr {
-				require.Failf(t, `List() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+				require.Failf(t, `ListWithOptions() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
 			}
-			_ = got
+			require.Equal(t, "", next)
+			require.Equal(t, tt.want, itemTitles(got))
+
+			// walk pages
+			opts := tt.args.opts
+			opts.Limit = 2
+			paged := []models.Item{}
+			for {
+				page, next, err := api.TodoService().ListWithOptions(&opts)
+				require.NoError(t, err)
+				paged = append(paged, page...)
+				if next == "" {
+					break
+				}
+				opts.Cursor = next
+			}
+			require.Equal(t, got, paged)
 		})
 	}
 }
 
+func itemTitles(items []models.Item) []string {
+	titles := []string{}
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
 func TestUpdate(t *testing.T) {
 	type args struct {
 		item models.Item
@@ -180,7 +318,7 @@ func TestUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ts, token, teardown := newTestServer(t)
 			defer teardown()
-			api := client.New(ts.URL, token)
+			api := client.NewWithAccessToken(ts.URL, token)
 
 			created, err := api.TodoService().Create(&tt.args.item)
 			require.NoError(t, err)
@@ -190,7 +328,1028 @@ func TestUpdate(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				require.Failf(t, `update() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
 			}
-			require.Equal(t, created, got)
+			want := *created
+			want.Revision++
+			want.UpdatedAt = got.UpdatedAt
+			require.Equal(t, &want, got)
 		})
 	}
 }
+
+func TestBatch(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+
+	existing, err := api.TodoService().Create(&models.Item{Title: "existing", DueDate: models.Today()})
+	require.NoError(t, err)
+
+	type args struct {
+		req models.BatchRequest
+	}
+	tests := [...]struct {
+		name       string
+		args       args
+		wantStatus []int
+		wantTitles []string
+	}{
+		{"not atomic", args{models.BatchRequest{Operations: []models.BatchOperation{
+			{Op: models.OpCreate, Item: &models.Item{Title: "created", DueDate: models.Today()}},
+			{Op: models.OpCreate, Item: &models.Item{DueDate: models.Today()}},
+			{Op: models.OpDelete, ID: "not-exists"},
+			{Op: models.OpUpdate, Item: &models.Item{ID: existing.ID, Title: "stale", DueDate: models.Today()}, Revision: 10},
+		}}}, []int{201, 400, 404, 412}, []string{"created", "existing"}},
+		{"atomic failed", args{models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{
+			{Op: models.OpCreate, Item: &models.Item{Title: "created", DueDate: models.Today()}},
+			{Op: models.OpDelete, ID: existing.ID},
+			{Op: models.OpDelete, ID: "not-exists"},
+		}}}, []int{424, 424, 404}, []string{"existing"}},
+		{"atomic", args{models.BatchRequest{Atomic: true, Operations: []models.BatchOperation{
+			{Op: models.OpCreate, Item: &models.Item{Title: "created", DueDate: models.Today()}},
+			{Op: models.OpUpdate, Item: &models.Item{ID: existing.ID, Title: "updated", DueDate: models.Today()}, Revision: 1},
+		}}}, []int{201, 200}, []string{"created", "updated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// restore items
+			items, err := api.TodoService().List()
+			require.NoError(t, err)
+			for _, item := range items {
+				if item.ID != existing.ID {
+					require.NoError(t, api.TodoService().Delete(item.ID))
+				}
+			}
+
+			got, err := api.TodoService().Batch(&tt.args.req)
+			require.NoError(t, err)
+			require.Equal(t, len(tt.wantStatus), len(got.Results))
+			for i, result := range got.Results {
+				require.Equal(t, tt.wantStatus[i], result.Status, "operation %d: %s", i, result.Error)
+			}
+
+			items, _, err = api.TodoService().ListWithOptions(&models.ListOptions{Sort: "title"})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.wantTitles, itemTitles(items))
+		})
+	}
+}
+
+func TestMove(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+
+	// all items has same rank, so first move needs rebalance
+	ids := map[string]string{}
+	for _, title := range []string{"a", "b", "c", "d"} {
+		item, err := api.TodoService().Create(&models.Item{Title: title, DueDate: models.Today()})
+		require.NoError(t, err)
+		ids[title] = item.ID
+	}
+
+	items, err := api.TodoService().List()
+	require.NoError(t, err)
+	order := itemTitles(items)
+
+	titleOf := func(id string) string {
+		for title, itemID := range ids {
+			if itemID == id {
+				return title
+			}
+		}
+		return ""
+	}
+
+	// move repeatedly to same place to exhaust gaps
+	for i := 0; i < 40; i++ {
+		title := order[len(order)-1]
+		moved, err := api.TodoService().Move(ids[title], &models.MoveRequest{After: ids[order[0]]})
+		require.NoError(t, err)
+		require.Equal(t, title, moved.Title)
+
+		order = append([]string{order[0], title}, order[1:len(order)-1]...)
+
+		items, err := api.TodoService().List()
+		require.NoError(t, err)
+		require.Equal(t, order, itemTitles(items))
+	}
+
+	// move to top
+	_, err = api.TodoService().Move(ids[order[3]], &models.MoveRequest{Before: ids[order[0]]})
+	require.NoError(t, err)
+	order = append([]string{order[3]}, order[:3]...)
+	items, err = api.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, order, itemTitles(items))
+
+	// move after
+	moved, err := api.TodoService().Move(ids[order[0]], &models.MoveRequest{After: ids[order[2]]})
+	require.NoError(t, err)
+	order = []string{order[1], order[2], order[0], order[3]}
+	items, err = api.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, order, itemTitles(items))
+	require.Equal(t, order[2], titleOf(moved.ID))
+
+	// move to bottom
+	_, err = api.TodoService().Move(ids[order[0]], &models.MoveRequest{After: ids[order[3]]})
+	require.NoError(t, err)
+	order = append(order[1:], order[0])
+	items, err = api.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, order, itemTitles(items))
+
+	_, err = api.TodoService().Move(ids["a"], &models.MoveRequest{})
+	require.Error(t, err)
+	_, err = api.TodoService().Move(ids["a"], &models.MoveRequest{Before: ids["a"]})
+	require.Error(t, err)
+	_, err = api.TodoService().Move(ids["a"], &models.MoveRequest{Before: "not-exists"})
+	require.Error(t, err)
+}
+
+func TestLists(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+
+	work, err := api.ListService().Create(&models.List{Name: "work"})
+	require.NoError(t, err)
+	require.NotEqual(t, "", work.ID)
+	require.False(t, work.CreatedAt.IsZero())
+
+	home, err := api.ListService().Create(&models.List{Name: "home"})
+	require.NoError(t, err)
+
+	lists, err := api.ListService().List()
+	require.NoError(t, err)
+	require.Equal(t, []models.List{*home, *work}, lists)
+
+	work.Name = "office"
+	updated, err := api.ListService().Update(work)
+	require.NoError(t, err)
+	require.Equal(t, "office", updated.Name)
+	require.Equal(t, work.CreatedAt, updated.CreatedAt)
+
+	got, err := api.ListService().Get(work.ID)
+	require.NoError(t, err)
+	require.Equal(t, updated, got)
+
+	// list of item should exists
+	_, err = api.TodoService().Create(&models.Item{Title: "title", DueDate: models.Today(), ListID: uuid.New().String()})
+	require.Error(t, err)
+
+	item, err := api.TodoService().Create(&models.Item{Title: "report", DueDate: models.Today(), ListID: work.ID})
+	require.NoError(t, err)
+	_, err = api.TodoService().Create(&models.Item{Title: "no list", DueDate: models.Today()})
+	require.NoError(t, err)
+
+	items, _, err := api.ListService().Todos(work.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"report"}, itemTitles(items))
+
+	// move item to other list
+	item, err = api.TodoService().Patch(item.ID, map[string]interface{}{"list_id": home.ID})
+	require.NoError(t, err)
+	require.Equal(t, home.ID, item.ListID)
+
+	items, _, err = api.ListService().Todos(work.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+
+	items, _, err = api.TodoService().ListWithOptions(&models.ListOptions{ListID: home.ID})
+	require.NoError(t, err)
+	require.Equal(t, []string{"report"}, itemTitles(items))
+
+	// list with items is deleted only with cascade
+	require.Error(t, api.ListService().Delete(home.ID, false))
+	require.NoError(t, api.ListService().Delete(home.ID, true))
+	_, err = api.ListService().Get(home.ID)
+	require.Error(t, err)
+	_, err = api.TodoService().Get(item.ID)
+	require.Error(t, err)
+
+	require.NoError(t, api.ListService().Delete(work.ID, false))
+	_, _, err = api.ListService().Todos(work.ID, nil)
+	require.Error(t, err)
+
+	items, err = api.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"no list"}, itemTitles(items))
+}
+
+func TestSharedList(t *testing.T) {
+	stg, err := memory.New("")
+	require.NoError(t, err)
+	defer stg.Close()
+
+	blobs, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	s := &todoService{storage: stg, blobs: blobs}
+	ts := httptest.NewServer(s.setupRoute())
+	defer ts.Close()
+
+	newClient := func() (string, client.Interface) {
+		email := utils.RandomString(5) + "@domain.com"
+		require.NoError(t, stg.UserService().Create(&models.User{Email: email}))
+		accessToken, err := tokens.New(email, config.AccessTokenDuration())
+		require.NoError(t, err)
+		return email, client.NewWithAccessToken(ts.URL, accessToken)
+	}
+
+	ownerEmail, owner := newClient()
+	editorEmail, editor := newClient()
+	viewerEmail, viewer := newClient()
+	_, stranger := newClient()
+
+	list, err := owner.ListService().Create(&models.List{Name: "shared"})
+	require.NoError(t, err)
+	item, err := owner.TodoService().Create(&models.Item{Title: "shared", DueDate: models.Today(), ListID: list.ID})
+	require.NoError(t, err)
+	private, err := owner.TodoService().Create(&models.Item{Title: "private", DueDate: models.Today()})
+	require.NoError(t, err)
+
+	_, err = owner.ListService().Invite(list.ID, editorEmail, models.RoleEditor)
+	require.NoError(t, err)
+	_, err = owner.ListService().Invite(list.ID, viewerEmail, models.RoleViewer)
+	require.NoError(t, err)
+	_, err = owner.ListService().Invite(list.ID, ownerEmail, models.RoleEditor)
+	require.Error(t, err, "owner can not be member")
+	_, err = editor.ListService().Invite(list.ID, "friend@domain.com", models.RoleViewer)
+	require.Error(t, err, "only owner can invite")
+
+	members, err := viewer.ListService().Members(list.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(members))
+	require.Equal(t, models.RoleOwner, members[0].Role)
+	require.Equal(t, ownerEmail, members[0].Email)
+
+	// shared items are listed with member's items
+	lists, err := viewer.ListService().List()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(lists))
+	require.Equal(t, ownerEmail, lists[0].Owner)
+
+	items, err := viewer.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"shared"}, itemTitles(items))
+
+	items, _, err = viewer.ListService().Todos(list.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"shared"}, itemTitles(items))
+
+	results, err := viewer.TodoService().Search("shar", 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, item.ID, results[0].Item.ID)
+
+	results, err = viewer.TodoService().Search("private", 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	// viewer can read but can not write
+	_, err = viewer.TodoService().Get(item.ID)
+	require.NoError(t, err)
+	_, err = viewer.TodoService().Complete(item.ID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "permission denied")
+	_, err = viewer.TodoService().Create(&models.Item{Title: "by viewer", DueDate: models.Today(), ListID: list.ID})
+	require.Error(t, err)
+	require.Error(t, viewer.TodoService().Delete(item.ID))
+
+	// editor can write items of the list
+	completed, err := editor.TodoService().Complete(item.ID)
+	require.NoError(t, err)
+	require.True(t, completed.Completed)
+
+	created, err := editor.TodoService().Create(&models.Item{Title: "by editor", DueDate: models.Today(), ListID: list.ID})
+	require.NoError(t, err)
+
+	items, _, err = owner.ListService().Todos(list.ID, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"shared", "by editor"}, itemTitles(items))
+
+	_, err = editor.TodoService().Patch(created.ID, map[string]interface{}{"list_id": nil})
+	require.Error(t, err, "item can not be moved out of shared list")
+
+	// but can not manage the list
+	require.Error(t, editor.ListService().Delete(list.ID, true))
+
+	// others can not access
+	for _, api := range []client.Interface{editor, viewer, stranger} {
+		_, err = api.TodoService().Get(private.ID)
+		require.Error(t, err)
+	}
+	_, err = stranger.TodoService().Get(item.ID)
+	require.Error(t, err)
+	_, err = stranger.ListService().Get(list.ID)
+	require.Error(t, err)
+
+	// member can leave the list
+	require.NoError(t, viewer.ListService().DeleteMember(list.ID, viewerEmail))
+	items, err = viewer.TodoService().List()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+	require.Error(t, editor.ListService().DeleteMember(list.ID, ownerEmail))
+
+	require.NoError(t, editor.TodoService().Delete(created.ID))
+}
+
+func TestTags(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+
+	for title, tags := range map[string][]string{
+		"report": {"Work", "urgent"},
+		"mail":   {"work"},
+		"clean":  {"home"},
+	} {
+		item, err := api.TodoService().Create(&models.Item{Title: title, DueDate: models.Today(), Tags: tags})
+		require.NoError(t, err)
+		require.Equal(t, storage.NormalizeTags(tags), item.Tags)
+	}
+
+	_, err := api.TodoService().Create(&models.Item{Title: "invalid", DueDate: models.Today(), Tags: []string{"a,b"}})
+	require.Error(t, err)
+
+	tags, err := api.TodoService().Tags()
+	require.NoError(t, err)
+	require.Equal(t, []models.Tag{{Name: "home", Count: 1}, {Name: "urgent", Count: 1}, {Name: "work", Count: 2}}, tags)
+
+	items, _, err := api.TodoService().ListWithOptions(&models.ListOptions{Sort: "title", Tags: [][]string{{"urgent"}, {"home"}}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"clean", "report"}, itemTitles(items))
+
+	// merge
+	tag, err := api.TodoService().RenameTag("urgent", "Work")
+	require.NoError(t, err)
+	require.Equal(t, &models.Tag{Name: "work", Count: 2}, tag)
+
+	// rename
+	tag, err = api.TodoService().RenameTag("work", "office")
+	require.NoError(t, err)
+	require.Equal(t, &models.Tag{Name: "office", Count: 2}, tag)
+
+	tags, err = api.TodoService().Tags()
+	require.NoError(t, err)
+	require.Equal(t, []models.Tag{{Name: "home", Count: 1}, {Name: "office", Count: 2}}, tags)
+
+	_, err = api.TodoService().RenameTag("unknown", "office")
+	require.Error(t, err)
+}
+
+func TestSubtasks(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	create := func(title, parentID string) *models.Item {
+		item, err := todos.Create(&models.Item{Title: title, DueDate: models.Today(), ParentID: parentID})
+		require.NoError(t, err)
+		return item
+	}
+
+	parent := create("parent", "")
+	child := create("child", parent.ID)
+	grandchild := create("grandchild", child.ID)
+
+	_, err := todos.Create(&models.Item{Title: "orphan", DueDate: models.Today(), ParentID: uuid.New().String()})
+	require.Error(t, err)
+
+	children, _, err := todos.Children(parent.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"child"}, itemTitles(children))
+
+	tree, err := todos.Tree(nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(tree))
+	require.Equal(t, "grandchild", tree[0].Children[0].Children[0].Title)
+
+	// cycle
+	parent.ParentID = grandchild.ID
+	_, err = todos.Update(parent)
+	require.Error(t, err)
+
+	// depth
+	last := grandchild
+	for i := 3; i < storage.MaxDepth; i++ {
+		last = create("deep", last.ID)
+	}
+	_, err = todos.Create(&models.Item{Title: "too deep", DueDate: models.Today(), ParentID: last.ID})
+	require.Error(t, err)
+
+	// block
+	_, err = todos.CompleteWithChildren(parent.ID, storage.ChildBlock)
+	require.Error(t, err)
+	require.Error(t, todos.DeleteWithChildren(parent.ID, storage.ChildBlock))
+
+	// cascade
+	_, err = todos.CompleteWithChildren(parent.ID, storage.ChildCascade)
+	require.NoError(t, err)
+
+	got, err := todos.Get(grandchild.ID)
+	require.NoError(t, err)
+	require.True(t, got.Completed)
+
+	// orphan
+	require.NoError(t, todos.DeleteWithChildren(child.ID, storage.ChildOrphan))
+
+	got, err = todos.Get(grandchild.ID)
+	require.NoError(t, err)
+	require.Equal(t, "", got.ParentID)
+
+	require.NoError(t, todos.DeleteWithChildren(grandchild.ID, storage.ChildCascade))
+	items, err := todos.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"parent"}, itemTitles(items))
+}
+
+func TestDependencies(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	items := map[string]*models.Item{}
+	for _, title := range []string{"design", "build", "ship"} {
+		item, err := todos.Create(&models.Item{Title: title, DueDate: models.Today()})
+		require.NoError(t, err)
+		items[title] = item
+	}
+
+	// ship is blocked by build, build is blocked by design
+	dep, err := todos.AddDependency(items["ship"].ID, items["build"].ID)
+	require.NoError(t, err)
+	require.Equal(t, &models.Dependency{ItemID: items["ship"].ID, DependsOn: items["build"].ID}, dep)
+	_, err = todos.AddDependency(items["build"].ID, items["design"].ID)
+	require.NoError(t, err)
+
+	// cycle
+	_, err = todos.AddDependency(items["design"].ID, items["ship"].ID)
+	require.Error(t, err)
+
+	_, err = todos.AddDependency(items["design"].ID, uuid.New().String())
+	require.Error(t, err)
+
+	deps, err := todos.Dependencies(items["ship"].ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Dependency{*dep}, deps)
+
+	actionable := func() []string {
+		items, _, err := todos.ListWithOptions(&models.ListOptions{Sort: "title", Actionable: true})
+		require.NoError(t, err)
+		return itemTitles(items)
+	}
+
+	require.Equal(t, []string{"design"}, actionable())
+
+	_, err = todos.Complete(items["design"].ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"build", "design"}, actionable())
+
+	require.NoError(t, todos.DeleteDependency(items["ship"].ID, items["build"].ID))
+	require.Error(t, todos.DeleteDependency(items["ship"].ID, items["build"].ID))
+	require.Equal(t, []string{"build", "design", "ship"}, actionable())
+}
+
+func TestRecurrence(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	due := models.Date{Time: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)}
+	item, err := todos.Create(&models.Item{Title: "pay rent", DueDate: due, Recurrence: &models.Recurrence{RRule: "FREQ=MONTHLY"}})
+	require.NoError(t, err)
+	require.Equal(t, &models.Recurrence{Freq: storage.FreqMonthly, Interval: 1, MonthDay: 31}, item.Recurrence)
+
+	_, err = todos.Create(&models.Item{Title: "invalid", DueDate: due, Recurrence: &models.Recurrence{RRule: "FREQ=YEARLY"}})
+	require.Error(t, err)
+
+	completed, err := todos.Complete(item.ID)
+	require.NoError(t, err)
+	require.Nil(t, completed.Recurrence)
+
+	items, _, err := todos.ListWithOptions(&models.ListOptions{Status: "open"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, "pay rent", items[0].Title)
+	require.Equal(t, "2024-02-29", items[0].DueDate.String())
+	require.Equal(t, item.Recurrence, items[0].Recurrence)
+
+	// reopened item does not recur again
+	_, err = todos.Reopen(item.ID)
+	require.NoError(t, err)
+	_, err = todos.Complete(item.ID)
+	require.NoError(t, err)
+
+	items, _, err = todos.ListWithOptions(&models.ListOptions{Status: "open"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(items))
+}
+
+func TestUserTimeZone(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	me, err := api.UserService().Me()
+	require.NoError(t, err)
+	require.Equal(t, "", me.TimeZone)
+
+	_, err = api.UserService().Update(&models.User{TimeZone: "Mars/Olympus_Mons"})
+	require.Error(t, err)
+
+	updated, err := api.UserService().Update(&models.User{Email: "other@domain.com", TimeZone: "Pacific/Kiritimati"})
+	require.NoError(t, err)
+	require.Equal(t, me.Email, updated.Email, "email can not be changed")
+	require.Equal(t, "Pacific/Kiritimati", updated.TimeZone)
+
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	today := models.TodayIn(loc)
+
+	_, err = todos.Create(&models.Item{Title: "no date", DueTime: "15:00"})
+	require.Error(t, err)
+	_, err = todos.Create(&models.Item{Title: "invalid time", DueDate: today, DueTime: "25:00"})
+	require.Error(t, err)
+
+	item, err := todos.Create(&models.Item{Title: "meeting", DueDate: today, DueTime: "00:00"})
+	require.NoError(t, err)
+	require.Equal(t, "00:00", item.DueTime)
+	_, err = todos.Create(&models.Item{Title: "tomorrow", DueDate: today.AddDays(1)})
+	require.NoError(t, err)
+
+	for _, due := range []string{"today", "overdue"} {
+		items, _, err := todos.ListWithOptions(&models.ListOptions{Due: due})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(items), due)
+		require.Equal(t, item.ID, items[0].ID, due)
+	}
+
+	_, _, err = todos.ListWithOptions(&models.ListOptions{Due: "someday"})
+	require.Error(t, err)
+}
+
+func TestQuickAdd(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	_, err := api.UserService().Update(&models.User{TimeZone: "Asia/Seoul"})
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("Asia/Seoul")
+	require.NoError(t, err)
+	tomorrow := models.TodayIn(loc).AddDays(1)
+
+	text := "Pay rent every month on the 1st #home !high tomorrow 9am"
+	preview, err := todos.QuickAdd(&models.QuickAddRequest{Text: text, DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, "Pay rent", preview.Item.Title)
+	require.Equal(t, tomorrow, preview.Item.DueDate)
+	require.Equal(t, "09:00", preview.Item.DueTime)
+	require.Equal(t, []string{"home"}, preview.Item.Tags)
+	require.Equal(t, storage.PriorityHigh, preview.Item.Priority)
+	require.Equal(t, 5, len(preview.Tokens))
+
+	items, err := todos.List()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items), "dry run does not create item")
+
+	created, err := todos.QuickAdd(&models.QuickAddRequest{Text: text})
+	require.NoError(t, err)
+	require.Equal(t, preview.Tokens, created.Tokens)
+
+	got, err := todos.Get(created.Item.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Pay rent", got.Title)
+	require.Equal(t, &models.Recurrence{Freq: storage.FreqMonthly, Interval: 1, MonthDay: 1}, got.Recurrence)
+
+	_, err = todos.QuickAdd(&models.QuickAddRequest{Text: "#home tomorrow"})
+	require.Error(t, err)
+
+	_, err = todos.QuickAdd(&models.QuickAddRequest{Text: "in unknown list", ListID: uuid.New().String()})
+	require.Error(t, err)
+}
+
+func TestViews(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	_, err := api.UserService().Update(&models.User{TimeZone: "Pacific/Kiritimati"})
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	today := models.TodayIn(loc)
+
+	create := func(title string, days int, priority string) *models.Item {
+		item, err := todos.Create(&models.Item{Title: title, DueDate: today.AddDays(days), Priority: priority})
+		require.NoError(t, err)
+		return item
+	}
+
+	overdue := create("overdue", -2, "")
+	today1 := create("today", 0, "")
+	today2 := create("today urgent", 0, storage.PriorityUrgent)
+	next := create("in 3 days", 3, storage.PriorityLow)
+	later := create("in 10 days", 10, "")
+	completed := create("completed", 0, "")
+	_, err = todos.Complete(completed.ID)
+	require.NoError(t, err)
+
+	_, err = todos.Create(&models.Item{Title: "invalid", Priority: "someday"})
+	require.Error(t, err)
+
+	ids := func(items []models.Item) []string {
+		ids := []string{}
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	tests := [...]struct {
+		name    string
+		view    string
+		days    int
+		want    []*models.Item
+		wantErr bool
+	}{
+		{"today", models.ViewToday, 0, []*models.Item{overdue, today2, today1}, false},
+		{"upcoming", models.ViewUpcoming, 0, []*models.Item{next}, false},
+		{"upcoming days", models.ViewUpcoming, 30, []*models.Item{next, later}, false},
+		{"overdue", models.ViewOverdue, 0, []*models.Item{overdue}, false},
+		{"unknown", "someday", 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := todos.View(tt.view, tt.days)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			want := []string{}
+			for _, item := range tt.want {
+				want = append(want, item.ID)
+			}
+			require.Equal(t, want, ids(got))
+		})
+	}
+}
+
+func TestFilters(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+	filters := api.FilterService()
+
+	create := func(title string, days int, tags ...string) {
+		_, err := todos.Create(&models.Item{Title: title, DueDate: models.Today().AddDays(days), Tags: tags})
+		require.NoError(t, err)
+	}
+	create("report", 1, "work")
+	create("meeting", 10, "work")
+	create("milk", -1, "home")
+
+	// query
+	tests := [...]struct {
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		{"tag:work", []string{"report", "meeting"}, false},
+		{"tag:work due<+3d", []string{"report"}, false},
+		{"#home or meeting", []string{"milk", "meeting"}, false},
+		{"overdue", []string{"milk"}, false},
+		{"not tag:work and not #home", []string{}, false},
+		{"tag:work and", nil, true},
+		{"color:red", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			items, _, err := todos.ListWithOptions(&models.ListOptions{Sort: "due_date", Query: tt.query})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, itemTitles(items))
+		})
+	}
+
+	// saved filters
+	_, err := filters.Create(&models.Filter{Name: "invalid", Query: "due<someday"})
+	require.Error(t, err)
+
+	work, err := filters.Create(&models.Filter{Name: "work", Query: "tag:work"})
+	require.NoError(t, err)
+	require.NotEqual(t, "", work.ID)
+	require.False(t, work.CreatedAt.IsZero())
+
+	soon, err := filters.Create(&models.Filter{Name: "Soon", Query: "due<+3d"})
+	require.NoError(t, err)
+
+	got, err := filters.List()
+	require.NoError(t, err)
+	require.Equal(t, []models.Filter{*soon, *work}, got)
+
+	items, _, err := filters.Todos(work.ID, &models.ListOptions{Sort: "due_date"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"report", "meeting"}, itemTitles(items))
+
+	items, _, err = filters.Todos(work.ID, &models.ListOptions{Query: "due<+3d"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"report"}, itemTitles(items))
+
+	work.Query = "tag:home"
+	updated, err := filters.Update(work)
+	require.NoError(t, err)
+	require.Equal(t, work.CreatedAt, updated.CreatedAt)
+
+	filter, err := filters.Get(work.ID)
+	require.NoError(t, err)
+	require.Equal(t, updated, filter)
+
+	items, _, err = filters.Todos(work.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"milk"}, itemTitles(items))
+
+	require.NoError(t, filters.Delete(work.ID))
+	_, err = filters.Get(work.ID)
+	require.Error(t, err)
+	require.Error(t, filters.Delete(work.ID))
+}
+
+func TestSearch(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	for i, title := range []string{"Write quarterly report", "Report expenses", "Reply to reporters", "Buy milk"} {
+		_, err := todos.Create(&models.Item{Title: title, DueDate: models.Today(), Rank: i})
+		require.NoError(t, err)
+	}
+
+	titles := func(results []models.SearchResult) []string {
+		titles := []string{}
+		for _, result := range results {
+			titles = append(titles, result.Item.Title)
+		}
+		return titles
+	}
+
+	tests := [...]struct {
+		query   string
+		limit   int
+		want    []string
+		wantErr bool
+	}{
+		{"report", 0, []string{"Write quarterly report", "Report expenses", "Reply to reporters"}, false},
+		{"quart rep", 0, []string{"Write quarterly report"}, false},
+		{"report", 1, []string{"Write quarterly report"}, false},
+		{"milk!", 0, []string{"Buy milk"}, false},
+		{"coffee", 0, []string{}, false},
+		{"", 0, nil, true},
+		{"report", 1000, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := todos.Search(tt.query, tt.limit)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, titles(got))
+		})
+	}
+}
+
+func TestNotes(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	created, err := todos.Create(&models.Item{
+		Title:   "read spec",
+		DueDate: models.Today(),
+		Notes:   "see [the spec](https://example.com/spec) <script>alert(1)</script>",
+		Links:   []models.Link{{URL: "https://ignored.example.com"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.Link{{URL: "https://example.com/spec", Text: "the spec"}}, created.Links)
+	require.Equal(t, "", created.NotesHTML)
+
+	got, err := todos.GetHTML(created.ID)
+	require.NoError(t, err)
+	require.Equal(t, `<p>see <a href="https://example.com/spec" rel="nofollow noopener noreferrer">the spec</a> &lt;script&gt;alert(1)&lt;/script&gt;</p>`+"\n", got.NotesHTML)
+
+	// rendered HTML is not stored
+	got.Notes = "no links"
+	updated, err := todos.Update(got)
+	require.NoError(t, err)
+	require.Nil(t, updated.Links)
+	require.Equal(t, "", updated.NotesHTML)
+
+	results, err := todos.Search("links", 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+
+	created.Title = "too long"
+	created.Notes = strings.Repeat("a", storage.MaxNotesSize+1)
+	_, err = todos.Create(created)
+	require.Error(t, err)
+}
+
+func TestAttachments(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	item, err := todos.Create(&models.Item{Title: "report", DueDate: models.Today()})
+	require.NoError(t, err)
+	other, err := todos.Create(&models.Item{Title: "review", DueDate: models.Today()})
+	require.NoError(t, err)
+
+	content := []byte("quarterly numbers")
+	attachment, err := todos.Attach(item.ID, "../numbers.txt", content)
+	require.NoError(t, err)
+	require.Equal(t, item.ID, attachment.ItemID)
+	require.Equal(t, "numbers.txt", attachment.Name)
+	require.Equal(t, int64(len(content)), attachment.Size)
+	require.True(t, strings.HasPrefix(attachment.ContentType, "text/plain"), attachment.ContentType)
+
+	attachments, err := todos.Attachments(item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Attachment{*attachment}, attachments)
+
+	got, err := todos.Download(item.ID, attachment.ID)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+
+	// same content is stored once
+	copied, err := todos.Attach(other.ID, "copy.txt", content)
+	require.NoError(t, err)
+	require.Equal(t, attachment.Hash, copied.Hash)
+
+	_, err = todos.Download(other.ID, attachment.ID)
+	require.Error(t, err)
+	_, err = todos.Attach(uuid.New().String(), "none.txt", content)
+	require.Error(t, err)
+
+	// content is kept while referenced by other attachment
+	require.NoError(t, todos.Delete(item.ID))
+	_, err = todos.Attachments(item.ID)
+	require.Error(t, err)
+
+	got, err = todos.Download(other.ID, copied.ID)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+
+	require.NoError(t, todos.DeleteAttachment(other.ID, copied.ID))
+	require.Error(t, todos.DeleteAttachment(other.ID, copied.ID))
+	attachments, err = todos.Attachments(other.ID)
+	require.NoError(t, err)
+	require.Empty(t, attachments)
+}
+
+func TestComments(t *testing.T) {
+	stg, err := memory.New("")
+	require.NoError(t, err)
+	defer stg.Close()
+
+	blobs, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	s := &todoService{storage: stg, blobs: blobs}
+	ts := httptest.NewServer(s.setupRoute())
+	defer ts.Close()
+
+	newClient := func() (string, client.Interface) {
+		email := utils.RandomString(5) + "@domain.com"
+		require.NoError(t, stg.UserService().Create(&models.User{Email: email}))
+		accessToken, err := tokens.New(email, config.AccessTokenDuration())
+		require.NoError(t, err)
+		return email, client.NewWithAccessToken(ts.URL, accessToken)
+	}
+
+	ownerEmail, owner := newClient()
+	viewerEmail, viewer := newClient()
+	_, stranger := newClient()
+
+	list, err := owner.ListService().Create(&models.List{Name: "shared"})
+	require.NoError(t, err)
+	item, err := owner.TodoService().Create(&models.Item{Title: "shared", DueDate: models.Today(), ListID: list.ID})
+	require.NoError(t, err)
+	_, err = owner.ListService().Invite(list.ID, viewerEmail, models.RoleViewer)
+	require.NoError(t, err)
+
+	// users who can see the item can comment
+	question, err := viewer.TodoService().AddComment(item.ID, "when is it due?")
+	require.NoError(t, err)
+	require.Equal(t, viewerEmail, question.Author)
+	require.Equal(t, item.ID, question.ItemID)
+	answer, err := owner.TodoService().AddComment(item.ID, "today")
+	require.NoError(t, err)
+	require.Equal(t, ownerEmail, answer.Author)
+
+	_, err = stranger.TodoService().AddComment(item.ID, "hi")
+	require.Error(t, err)
+	_, err = owner.TodoService().AddComment(item.ID, "")
+	require.Error(t, err)
+
+	comments, err := viewer.TodoService().Comments(item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []models.Comment{*question, *answer}, comments)
+
+	// only the author can edit
+	_, err = owner.TodoService().UpdateComment(item.ID, question.ID, "edited")
+	require.Error(t, err)
+	edited, err := viewer.TodoService().UpdateComment(item.ID, question.ID, "when is it due, exactly?")
+	require.NoError(t, err)
+	require.Equal(t, "when is it due, exactly?", edited.Body)
+	require.Equal(t, question.CreatedAt, edited.CreatedAt)
+	require.False(t, edited.UpdatedAt.Before(question.UpdatedAt))
+
+	// the author or owner of the item can delete
+	require.Error(t, viewer.TodoService().DeleteComment(item.ID, answer.ID))
+	require.NoError(t, owner.TodoService().DeleteComment(item.ID, question.ID))
+	require.NoError(t, owner.TodoService().DeleteComment(item.ID, answer.ID))
+	require.Error(t, owner.TodoService().DeleteComment(item.ID, answer.ID))
+
+	comments, err = owner.TodoService().Comments(item.ID)
+	require.NoError(t, err)
+	require.Empty(t, comments)
+}
+
+func TestActivity(t *testing.T) {
+	ts, token, teardown := newTestServer(t)
+	defer teardown()
+	api := client.NewWithAccessToken(ts.URL, token)
+	todos := api.TodoService()
+
+	item, err := todos.Create(&models.Item{Title: "draft", DueDate: models.Today()})
+	require.NoError(t, err)
+	other, err := todos.Create(&models.Item{Title: "other", DueDate: models.Today()})
+	require.NoError(t, err)
+
+	item.Title = "report"
+	item.Priority = storage.PriorityHigh
+	item, err = todos.Update(item)
+	require.NoError(t, err)
+
+	// nothing changed is not recorded
+	item, err = todos.Patch(item.ID, map[string]interface{}{"title": "report"})
+	require.NoError(t, err)
+
+	item, err = todos.Move(item.ID, &models.MoveRequest{After: other.ID})
+	require.NoError(t, err)
+	item, err = todos.Complete(item.ID)
+	require.NoError(t, err)
+	_, err = todos.Reopen(item.ID)
+	require.NoError(t, err)
+
+	_, err = todos.Batch(&models.BatchRequest{Operations: []models.BatchOperation{
+		{Op: storage.OpUpdate, Item: &models.Item{ID: item.ID, Title: "final report", DueDate: item.DueDate, Rank: item.Rank, Priority: item.Priority}},
+	}})
+	require.NoError(t, err)
+
+	activities, err := todos.Activity(item.ID)
+	require.NoError(t, err)
+
+	type change struct {
+		action string
+		fields []string
+	}
+	got := []change{}
+	for _, activity := range activities {
+		require.Equal(t, item.ID, activity.ItemID)
+		require.NotEmpty(t, activity.Actor)
+		got = append(got, change{activity.Action, activity.Fields})
+	}
+	require.Equal(t, []change{
+		{storage.ActionCreated, nil},
+		{storage.ActionUpdated, []string{"priority", "title"}},
+		{storage.ActionMoved, []string{"rank"}},
+		{storage.ActionCompleted, []string{"completed", "completed_at"}},
+		{storage.ActionReopened, []string{"completed", "completed_at"}},
+		{storage.ActionUpdated, []string{"title"}},
+	}, got)
+
+	_, err = todos.Activity(uuid.New().String())
+	require.Error(t, err)
+}
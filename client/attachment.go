@@ -0,0 +1,168 @@
+package client
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+// Attachments list files attached to todo item
+func (t *todoImpl) Attachments(itemID string) ([]models.Attachment, error) {
+	return t.doAttachments(itemID, true)
+}
+
+func (t *todoImpl) doAttachments(itemID string, refresh bool) ([]models.Attachment, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/%s/attachments", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "attachments")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doAttachments(itemID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	attachments := make([]models.Attachment, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&attachments); err != nil {
+		return nil, errors.Wrapf(err, "attachments")
+	}
+
+	return attachments, nil
+}
+
+// Attach upload file to todo item
+func (t *todoImpl) Attach(itemID, name string, content []byte) (*models.Attachment, error) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "attach")
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, errors.Wrapf(err, "attach")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrapf(err, "attach")
+	}
+
+	return t.doAttach(itemID, w.FormDataContentType(), body.Bytes(), true)
+}
+
+func (t *todoImpl) doAttach(itemID, contentType string, body []byte, refresh bool) (*models.Attachment, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Post("%s/%s/attachments", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		ContentType(contentType).
+		Body(bytes.NewReader(body)).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "attach")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doAttach(itemID, contentType, body, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var attachment models.Attachment
+	defer resp.Body.Close()
+	if err := resp.JSON(&attachment); err != nil {
+		return nil, errors.Wrapf(err, "attach")
+	}
+
+	return &attachment, nil
+}
+
+// Download returns content of attachment
+func (t *todoImpl) Download(itemID, attachmentID string) ([]byte, error) {
+	return t.doDownload(itemID, attachmentID, true)
+}
+
+func (t *todoImpl) doDownload(itemID, attachmentID string, refresh bool) ([]byte, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/%s/attachments/%s", t.client.endpoint, itemID, attachmentID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "download")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doDownload(itemID, attachmentID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	defer resp.Body.Close()
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "download")
+	}
+
+	return content, nil
+}
+
+// DeleteAttachment delete attachment of todo item
+func (t *todoImpl) DeleteAttachment(itemID, attachmentID string) error {
+	return t.doDeleteAttachment(itemID, attachmentID, true)
+}
+
+func (t *todoImpl) doDeleteAttachment(itemID, attachmentID string, refresh bool) error {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := t.client.sess.Delete("%s/%s/attachments/%s", t.client.endpoint, itemID, attachmentID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete attachment")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return t.doDeleteAttachment(itemID, attachmentID, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
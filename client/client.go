@@ -1,34 +1,127 @@
 package client
 
 import (
+	"fmt"
+
 	"github.com/whitekid/go-todo/models"
 	"github.com/whitekid/go-utils/request"
 )
 
 // Interface represents client interface
+//
 //go:generate mockgen -destination=mocks/mocks.go -package mocks . Interface
 type Interface interface {
 	TodoService() TodoService
+	ListService() ListService
+	UserService() UserService
+	FilterService() FilterService
 }
 
 // TodoService ...
 type TodoService interface {
 	Create(item *models.Item) (*models.Item, error)
 	List() ([]models.Item, error)
+	ListWithOptions(opts *models.ListOptions) ([]models.Item, string, error)
+	Children(itemID string, opts *models.ListOptions) ([]models.Item, string, error)
+	Tree(opts *models.ListOptions) ([]models.TreeItem, error)
 	Get(itemID string) (*models.Item, error)
+	GetHTML(itemID string) (*models.Item, error)
 	Update(item *models.Item) (*models.Item, error)
+	Patch(itemID string, patch map[string]interface{}) (*models.Item, error)
 	Delete(itemID string) error
+	DeleteWithChildren(itemID, policy string) error
+	Complete(itemID string) (*models.Item, error)
+	CompleteWithChildren(itemID, policy string) (*models.Item, error)
+	Reopen(itemID string) (*models.Item, error)
+	Move(itemID string, req *models.MoveRequest) (*models.Item, error)
+	Batch(req *models.BatchRequest) (*models.BatchResponse, error)
+	QuickAdd(req *models.QuickAddRequest) (*models.QuickAddResponse, error)
+	View(view string, days int) ([]models.Item, error)
+	Search(query string, limit int) ([]models.SearchResult, error)
+	Tags() ([]models.Tag, error)
+	RenameTag(tag, name string) (*models.Tag, error)
+	Dependencies(itemID string) ([]models.Dependency, error)
+	AddDependency(itemID, dependsOn string) (*models.Dependency, error)
+	DeleteDependency(itemID, dependsOn string) error
+	Attachments(itemID string) ([]models.Attachment, error)
+	Attach(itemID, name string, content []byte) (*models.Attachment, error)
+	Download(itemID, attachmentID string) ([]byte, error)
+	DeleteAttachment(itemID, attachmentID string) error
+	Comments(itemID string) ([]models.Comment, error)
+	AddComment(itemID, body string) (*models.Comment, error)
+	UpdateComment(itemID, commentID, body string) (*models.Comment, error)
+	DeleteComment(itemID, commentID string) error
+	Activity(itemID string) ([]models.Activity, error)
+}
+
+// ListService ...
+type ListService interface {
+	Create(list *models.List) (*models.List, error)
+	List() ([]models.List, error)
+	Get(listID string) (*models.List, error)
+	Update(list *models.List) (*models.List, error)
+	Delete(listID string, cascade bool) error
+	Todos(listID string, opts *models.ListOptions) ([]models.Item, string, error)
+	Members(listID string) ([]models.Member, error)
+	Invite(listID, email string, role models.Role) (*models.Member, error)
+	DeleteMember(listID, email string) error
+}
+
+// UserService ...
+type UserService interface {
+	Me() (*models.User, error)
+	Update(user *models.User) (*models.User, error)
+}
+
+// FilterService ...
+type FilterService interface {
+	Create(filter *models.Filter) (*models.Filter, error)
+	List() ([]models.Filter, error)
+	Get(filterID string) (*models.Filter, error)
+	Update(filter *models.Filter) (*models.Filter, error)
+	Delete(filterID string) error
+	Todos(filterID string, opts *models.ListOptions) ([]models.Item, string, error)
+}
+
+// ConflictError is returned when item is modified by others since it was read
+type ConflictError struct {
+	ItemID     string
+	StatusCode int    // 409 or 412
+	Message    string // response from server
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("item %s is modified: %d %s", e.ItemID, e.StatusCode, e.Message)
+}
+
+// IsConflict returns true if err is conflict error
+func IsConflict(err error) bool {
+	_, ok := err.(*ConflictError)
+	return ok
 }
 
 // New create new client
 func New(endpoint string, refreshToken string) Interface {
+	return newClient(endpoint, refreshToken, "")
+}
+
+// NewWithAccessToken create new client with access token, which is used without refresh
+func NewWithAccessToken(endpoint string, accessToken string) Interface {
+	return newClient(endpoint, "", accessToken)
+}
+
+func newClient(endpoint, refreshToken, accessToken string) *clientImpl {
 	client := &clientImpl{
 		endpoint:     endpoint,
 		sess:         request.NewSession(nil),
 		refreshToken: refreshToken,
+		accessToken:  accessToken,
 	}
 
 	client.todos = &todoImpl{client: client}
+	client.lists = &listImpl{client: client}
+	client.users = &userImpl{client: client}
+	client.filters = &filterImpl{client: client}
 	client.auth = &authImpl{client: client}
 
 	return client
@@ -42,13 +135,29 @@ type clientImpl struct {
 	accessToken  string
 
 	todos *todoImpl
+	lists *listImpl
+	users *userImpl
 	auth  *authImpl
+
+	filters *filterImpl
 }
 
 func (c *clientImpl) TodoService() TodoService {
 	return c.todos
 }
 
+func (c *clientImpl) ListService() ListService {
+	return c.lists
+}
+
+func (c *clientImpl) UserService() UserService {
+	return c.users
+}
+
+func (c *clientImpl) FilterService() FilterService {
+	return c.filters
+}
+
 func (c *clientImpl) ensureAccessToken() error {
 	if c.accessToken == "" {
 		if err := c.auth.refreshAccessToken(); err != nil {
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+// Comments list comments on todo item
+func (t *todoImpl) Comments(itemID string) ([]models.Comment, error) {
+	return t.doComments(itemID, true)
+}
+
+func (t *todoImpl) doComments(itemID string, refresh bool) ([]models.Comment, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/%s/comments", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "comments")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doComments(itemID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	comments := make([]models.Comment, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&comments); err != nil {
+		return nil, errors.Wrapf(err, "comments")
+	}
+
+	return comments, nil
+}
+
+// AddComment comment on todo item
+func (t *todoImpl) AddComment(itemID, body string) (*models.Comment, error) {
+	return t.doAddComment(itemID, body, true)
+}
+
+func (t *todoImpl) doAddComment(itemID, body string, refresh bool) (*models.Comment, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Post("%s/%s/comments", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(&models.Comment{Body: body}).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "add comment")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doAddComment(itemID, body, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var comment models.Comment
+	defer resp.Body.Close()
+	if err := resp.JSON(&comment); err != nil {
+		return nil, errors.Wrapf(err, "add comment")
+	}
+
+	return &comment, nil
+}
+
+// UpdateComment edit body of comment
+func (t *todoImpl) UpdateComment(itemID, commentID, body string) (*models.Comment, error) {
+	return t.doUpdateComment(itemID, commentID, body, true)
+}
+
+func (t *todoImpl) doUpdateComment(itemID, commentID, body string, refresh bool) (*models.Comment, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Put("%s/%s/comments/%s", t.client.endpoint, itemID, commentID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(&models.Comment{Body: body}).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "update comment")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doUpdateComment(itemID, commentID, body, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var comment models.Comment
+	defer resp.Body.Close()
+	if err := resp.JSON(&comment); err != nil {
+		return nil, errors.Wrapf(err, "update comment")
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment delete comment on todo item
+func (t *todoImpl) DeleteComment(itemID, commentID string) error {
+	return t.doDeleteComment(itemID, commentID, true)
+}
+
+func (t *todoImpl) doDeleteComment(itemID, commentID string, refresh bool) error {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := t.client.sess.Delete("%s/%s/comments/%s", t.client.endpoint, itemID, commentID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete comment")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return t.doDeleteComment(itemID, commentID, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
+
+// Activity list changes of todo item
+func (t *todoImpl) Activity(itemID string) ([]models.Activity, error) {
+	return t.doActivity(itemID, true)
+}
+
+func (t *todoImpl) doActivity(itemID string, refresh bool) ([]models.Activity, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/%s/activity", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "activity")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doActivity(itemID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	activities := make([]models.Activity, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&activities); err != nil {
+		return nil, errors.Wrapf(err, "activity")
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+// Dependencies list dependencies of todo item
+func (t *todoImpl) Dependencies(itemID string) ([]models.Dependency, error) {
+	return t.doDependencies(itemID, true)
+}
+
+func (t *todoImpl) doDependencies(itemID string, refresh bool) ([]models.Dependency, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/%s/dependencies", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "dependencies")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doDependencies(itemID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	deps := make([]models.Dependency, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&deps); err != nil {
+		return nil, errors.Wrapf(err, "dependencies")
+	}
+
+	return deps, nil
+}
+
+// AddDependency make todo item blocked by the item it depends on
+func (t *todoImpl) AddDependency(itemID, dependsOn string) (*models.Dependency, error) {
+	return t.doAddDependency(itemID, dependsOn, true)
+}
+
+func (t *todoImpl) doAddDependency(itemID, dependsOn string, refresh bool) (*models.Dependency, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Post("%s/%s/dependencies", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(&models.Dependency{DependsOn: dependsOn}).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "add dependency")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doAddDependency(itemID, dependsOn, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var dep models.Dependency
+	defer resp.Body.Close()
+	if err := resp.JSON(&dep); err != nil {
+		return nil, errors.Wrapf(err, "add dependency")
+	}
+
+	return &dep, nil
+}
+
+// DeleteDependency delete dependency of todo item
+func (t *todoImpl) DeleteDependency(itemID, dependsOn string) error {
+	return t.doDeleteDependency(itemID, dependsOn, true)
+}
+
+func (t *todoImpl) doDeleteDependency(itemID, dependsOn string, refresh bool) error {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := t.client.sess.Delete("%s/%s/dependencies/%s", t.client.endpoint, itemID, dependsOn).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete dependency")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return t.doDeleteDependency(itemID, dependsOn, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+type filterImpl struct {
+	client *clientImpl
+}
+
+func (f *filterImpl) Create(filter *models.Filter) (*models.Filter, error) {
+	return f.doCreate(filter, true)
+}
+func (f *filterImpl) doCreate(filter *models.Filter, refresh bool) (*models.Filter, error) {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.sess.Post("%s/filters/", f.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken).
+		JSON(filter).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "create")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return f.doCreate(filter, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var created models.Filter
+
+	defer resp.Body.Close()
+	if err := resp.JSON(&created); err != nil {
+		return nil, errors.Wrapf(err, "create")
+	}
+
+	return &created, nil
+}
+
+// List list saved filters, ordered by name
+func (f *filterImpl) List() ([]models.Filter, error) { return f.doList(true) }
+func (f *filterImpl) doList(refresh bool) ([]models.Filter, error) {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.sess.Get("%s/filters/", f.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "list")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return f.doList(false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	filters := make([]models.Filter, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&filters); err != nil {
+		return nil, errors.Wrapf(err, "list")
+	}
+
+	return filters, nil
+}
+
+// Get get saved filter
+func (f *filterImpl) Get(filterID string) (*models.Filter, error) { return f.doGet(filterID, true) }
+func (f *filterImpl) doGet(filterID string, refresh bool) (*models.Filter, error) {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.sess.Get("%s/filters/%s", f.client.endpoint, filterID).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return f.doGet(filterID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var filter models.Filter
+	defer resp.Body.Close()
+	if err := resp.JSON(&filter); err != nil {
+		return nil, errors.Wrapf(err, "get")
+	}
+
+	return &filter, nil
+}
+
+// Update update saved filter
+func (f *filterImpl) Update(filter *models.Filter) (*models.Filter, error) {
+	return f.doUpdate(filter, true)
+}
+func (f *filterImpl) doUpdate(filter *models.Filter, refresh bool) (*models.Filter, error) {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := f.client.sess.Put("%s/filters/%s", f.client.endpoint, filter.ID).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken).
+		JSON(filter).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return f.doUpdate(filter, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var updated models.Filter
+	defer resp.Body.Close()
+	if err := resp.JSON(&updated); err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	return &updated, nil
+}
+
+// Delete delete saved filter
+func (f *filterImpl) Delete(filterID string) error { return f.doDelete(filterID, true) }
+func (f *filterImpl) doDelete(filterID string, refresh bool) error {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := f.client.sess.Delete("%s/filters/%s", f.client.endpoint, filterID).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return f.doDelete(filterID, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
+
+// Todos list todo items matched to saved filter, returns items and cursor of next page
+func (f *filterImpl) Todos(filterID string, opts *models.ListOptions) ([]models.Item, string, error) {
+	return f.doTodos(filterID, opts, true)
+}
+
+func (f *filterImpl) doTodos(filterID string, opts *models.ListOptions, refresh bool) ([]models.Item, string, error) {
+	if err := f.client.ensureAccessToken(); err != nil {
+		return nil, "", err
+	}
+
+	req := f.client.sess.Get("%s/filters/%s/todos", f.client.endpoint, filterID).
+		Header(echo.HeaderAuthorization, "Bearer "+f.client.accessToken)
+	values := opts.Values()
+	for key := range values {
+		req.Param(key, values.Get(key))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "todos")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := f.client.auth.refreshAccessToken(); err != nil {
+				return nil, "", err
+			}
+			return f.doTodos(filterID, opts, false)
+		}
+
+		return nil, "", errors.New(resp.String())
+	}
+
+	items := make([]models.Item, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&items); err != nil {
+		return nil, "", errors.Wrapf(err, "todos")
+	}
+
+	return items, nextCursor(resp.Header.Get("Link")), nil
+}
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+type listImpl struct {
+	client *clientImpl
+}
+
+func (l *listImpl) Create(list *models.List) (*models.List, error) { return l.doCreate(list, true) }
+func (l *listImpl) doCreate(list *models.List, refresh bool) (*models.List, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Post("%s/lists/", l.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		JSON(list).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "create")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doCreate(list, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var created models.List
+
+	defer resp.Body.Close()
+	if err := resp.JSON(&created); err != nil {
+		return nil, errors.Wrapf(err, "create")
+	}
+
+	return &created, nil
+}
+
+// List list all lists
+func (l *listImpl) List() ([]models.List, error) { return l.doList(true) }
+func (l *listImpl) doList(refresh bool) ([]models.List, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Get("%s/lists/", l.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "list")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doList(false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	lists := make([]models.List, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&lists); err != nil {
+		return nil, errors.Wrapf(err, "list")
+	}
+
+	return lists, nil
+}
+
+// Get get list
+func (l *listImpl) Get(listID string) (*models.List, error) { return l.doGet(listID, true) }
+func (l *listImpl) doGet(listID string, refresh bool) (*models.List, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Get("%s/lists/%s", l.client.endpoint, listID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doGet(listID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var list models.List
+	defer resp.Body.Close()
+	if err := resp.JSON(&list); err != nil {
+		return nil, errors.Wrapf(err, "get")
+	}
+
+	return &list, nil
+}
+
+// Update update list
+func (l *listImpl) Update(list *models.List) (*models.List, error) { return l.doUpdate(list, true) }
+func (l *listImpl) doUpdate(list *models.List, refresh bool) (*models.List, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Put("%s/lists/%s", l.client.endpoint, list.ID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		JSON(list).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doUpdate(list, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var updated models.List
+	defer resp.Body.Close()
+	if err := resp.JSON(&updated); err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	return &updated, nil
+}
+
+// Delete delete list, items of the list are deleted if cascade; otherwise list should be empty
+func (l *listImpl) Delete(listID string, cascade bool) error {
+	return l.doDelete(listID, cascade, true)
+}
+func (l *listImpl) doDelete(listID string, cascade bool, refresh bool) error {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := l.client.sess.Delete("%s/lists/%s", l.client.endpoint, listID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		Param("cascade", strconv.FormatBool(cascade)).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return l.doDelete(listID, cascade, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
+
+// Todos list todo items of list, returns items and cursor of next page
+func (l *listImpl) Todos(listID string, opts *models.ListOptions) ([]models.Item, string, error) {
+	return l.doTodos(listID, opts, true)
+}
+
+func (l *listImpl) doTodos(listID string, opts *models.ListOptions, refresh bool) ([]models.Item, string, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, "", err
+	}
+
+	req := l.client.sess.Get("%s/lists/%s/todos", l.client.endpoint, listID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken)
+	values := opts.Values()
+	for key := range values {
+		req.Param(key, values.Get(key))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "todos")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, "", err
+			}
+			return l.doTodos(listID, opts, false)
+		}
+
+		return nil, "", errors.New(resp.String())
+	}
+
+	items := make([]models.Item, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&items); err != nil {
+		return nil, "", errors.Wrapf(err, "todos")
+	}
+
+	return items, nextCursor(resp.Header.Get("Link")), nil
+}
+
+// Members list members of list, owner comes first
+func (l *listImpl) Members(listID string) ([]models.Member, error) { return l.doMembers(listID, true) }
+func (l *listImpl) doMembers(listID string, refresh bool) ([]models.Member, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Get("%s/lists/%s/members", l.client.endpoint, listID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "members")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doMembers(listID, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	members := make([]models.Member, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&members); err != nil {
+		return nil, errors.Wrapf(err, "members")
+	}
+
+	return members, nil
+}
+
+// Invite share list with the user of email by role, or change role of the member
+func (l *listImpl) Invite(listID, email string, role models.Role) (*models.Member, error) {
+	return l.doInvite(listID, &models.Member{Email: email, Role: role}, true)
+}
+
+func (l *listImpl) doInvite(listID string, member *models.Member, refresh bool) (*models.Member, error) {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.client.sess.Post("%s/lists/%s/members", l.client.endpoint, listID).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		JSON(member).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invite")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return l.doInvite(listID, member, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var invited models.Member
+	defer resp.Body.Close()
+	if err := resp.JSON(&invited); err != nil {
+		return nil, errors.Wrapf(err, "invite")
+	}
+
+	return &invited, nil
+}
+
+// DeleteMember stop sharing list with the member, or leave the list if email is of the user
+func (l *listImpl) DeleteMember(listID, email string) error {
+	return l.doDeleteMember(listID, email, true)
+}
+func (l *listImpl) doDeleteMember(listID, email string, refresh bool) error {
+	if err := l.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	resp, err := l.client.sess.Delete("%s/lists/%s/members/%s", l.client.endpoint, listID, url.PathEscape(email)).
+		Header(echo.HeaderAuthorization, "Bearer "+l.client.accessToken).
+		Do()
+	if err != nil {
+		return errors.Wrapf(err, "delete member")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := l.client.auth.refreshAccessToken(); err != nil {
+				return err
+			}
+			return l.doDeleteMember(listID, email, false)
+		}
+
+		return errors.New(resp.String())
+	}
+
+	return nil
+}
@@ -33,6 +33,34 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// FilterService mocks base method
+func (m *MockInterface) FilterService() client.FilterService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterService")
+	ret0, _ := ret[0].(client.FilterService)
+	return ret0
+}
+
+// FilterService indicates an expected call of FilterService
+func (mr *MockInterfaceMockRecorder) FilterService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterService", reflect.TypeOf((*MockInterface)(nil).FilterService))
+}
+
+// ListService mocks base method
+func (m *MockInterface) ListService() client.ListService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListService")
+	ret0, _ := ret[0].(client.ListService)
+	return ret0
+}
+
+// ListService indicates an expected call of ListService
+func (mr *MockInterfaceMockRecorder) ListService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListService", reflect.TypeOf((*MockInterface)(nil).ListService))
+}
+
 // TodoService mocks base method
 func (m *MockInterface) TodoService() client.TodoService {
 	m.ctrl.T.Helper()
@@ -46,3 +74,17 @@ func (mr *MockInterfaceMockRecorder) TodoService() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TodoService", reflect.TypeOf((*MockInterface)(nil).TodoService))
 }
+
+// UserService mocks base method
+func (m *MockInterface) UserService() client.UserService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserService")
+	ret0, _ := ret[0].(client.UserService)
+	return ret0
+}
+
+// UserService indicates an expected call of UserService
+func (mr *MockInterfaceMockRecorder) UserService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserService", reflect.TypeOf((*MockInterface)(nil).UserService))
+}
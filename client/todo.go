@@ -1,11 +1,18 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/mergepatch"
 	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-utils/request"
 )
 
 type todoImpl struct {
@@ -46,18 +53,117 @@ func (t *todoImpl) doCreate(item *models.Item, refresh bool) (*models.Item, erro
 	return &created, nil
 }
 
-// List list todo item
-func (t *todoImpl) List() ([]models.Item, error) { return t.doList(true) }
-func (t *todoImpl) doList(refresh bool) ([]models.Item, error) {
+// List list all todo item
+func (t *todoImpl) List() ([]models.Item, error) {
+	items, _, err := t.doList(nil, true)
+	return items, err
+}
+
+// ListWithOptions list todo item with options, returns items and cursor of next page.
+// cursor is empty if there is no more page
+func (t *todoImpl) ListWithOptions(opts *models.ListOptions) ([]models.Item, string, error) {
+	return t.doList(opts, true)
+}
+
+func (t *todoImpl) doList(opts *models.ListOptions, refresh bool) ([]models.Item, string, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, "", err
+	}
+
+	req := t.client.sess.Get("%s", t.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	values := opts.Values()
+	for key := range values {
+		req.Param(key, values.Get(key))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "list")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, "", err
+			}
+			return t.doList(opts, false)
+		}
+
+		return nil, "", errors.New(resp.String())
+	}
+
+	items := make([]models.Item, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&items); err != nil {
+		return nil, "", errors.Wrapf(err, "list")
+	}
+
+	return items, nextCursor(resp.Header.Get("Link")), nil
+}
+
+// Children list subtasks of todo item, returns items and cursor of next page
+func (t *todoImpl) Children(itemID string, opts *models.ListOptions) ([]models.Item, string, error) {
+	return t.doChildren(itemID, opts, true)
+}
+
+func (t *todoImpl) doChildren(itemID string, opts *models.ListOptions, refresh bool) ([]models.Item, string, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, "", err
+	}
+
+	req := t.client.sess.Get("%s/%s/children", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	values := opts.Values()
+	for key := range values {
+		req.Param(key, values.Get(key))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "children")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, "", err
+			}
+			return t.doChildren(itemID, opts, false)
+		}
+
+		return nil, "", errors.New(resp.String())
+	}
+
+	items := make([]models.Item, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&items); err != nil {
+		return nil, "", errors.Wrapf(err, "children")
+	}
+
+	return items, nextCursor(resp.Header.Get("Link")), nil
+}
+
+// Tree list todo items matched to options as tree of subtasks, limit and cursor are ignored
+func (t *todoImpl) Tree(opts *models.ListOptions) ([]models.TreeItem, error) {
+	return t.doTree(opts, true)
+}
+func (t *todoImpl) doTree(opts *models.ListOptions, refresh bool) ([]models.TreeItem, error) {
 	if err := t.client.ensureAccessToken(); err != nil {
 		return nil, err
 	}
 
-	resp, err := t.client.sess.Get("%s", t.client.endpoint).
+	req := t.client.sess.Get("%s", t.client.endpoint).
 		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
-		Do()
+		Param("tree", "true")
+	values := opts.Values()
+	for key := range values {
+		req.Param(key, values.Get(key))
+	}
+
+	resp, err := req.Do()
 	if err != nil {
-		return nil, errors.Wrapf(err, "list")
+		return nil, errors.Wrapf(err, "tree")
 	}
 
 	if !resp.Success() {
@@ -65,31 +171,57 @@ func (t *todoImpl) doList(refresh bool) ([]models.Item, error) {
 			if err := t.client.auth.refreshAccessToken(); err != nil {
 				return nil, err
 			}
-			return t.doList(false)
+			return t.doTree(opts, false)
 		}
 
 		return nil, errors.New(resp.String())
 	}
 
-	items := make([]models.Item, 0)
+	items := make([]models.TreeItem, 0)
 	defer resp.Body.Close()
 	if err := resp.JSON(&items); err != nil {
-		return nil, errors.Wrapf(err, "list")
+		return nil, errors.Wrapf(err, "tree")
 	}
 
 	return items, nil
 }
 
+// nextCursor returns cursor from link header: <url>; rel="next"
+func nextCursor(link string) string {
+	for _, l := range strings.Split(link, ",") {
+		parts := strings.Split(l, ";")
+		if len(parts) < 2 || strings.TrimSpace(parts[1]) != `rel="next"` {
+			continue
+		}
+
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+		if err != nil {
+			return ""
+		}
+
+		return u.Query().Get("cursor")
+	}
+
+	return ""
+}
+
 // Get get todo item
-func (t *todoImpl) Get(itemID string) (*models.Item, error) { return t.doGet(itemID, true) }
-func (t *todoImpl) doGet(itemID string, refresh bool) (*models.Item, error) {
+func (t *todoImpl) Get(itemID string) (*models.Item, error) { return t.doGet(itemID, "", true) }
+
+// GetHTML get todo item with notes rendered to sanitized HTML
+func (t *todoImpl) GetHTML(itemID string) (*models.Item, error) { return t.doGet(itemID, "html", true) }
+func (t *todoImpl) doGet(itemID string, render string, refresh bool) (*models.Item, error) {
 	if err := t.client.ensureAccessToken(); err != nil {
 		return nil, err
 	}
 
-	resp, err := t.client.sess.Get("%s/%s", t.client.endpoint, itemID).
-		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
-		Do()
+	req := t.client.sess.Get("%s/%s", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	if render != "" {
+		req.Param("render", render)
+	}
+
+	resp, err := req.Do()
 	if err != nil {
 		return nil, errors.Wrapf(err, "get")
 	}
@@ -99,7 +231,7 @@ func (t *todoImpl) doGet(itemID string, refresh bool) (*models.Item, error) {
 			if err := t.client.auth.refreshAccessToken(); err != nil {
 				return nil, err
 			}
-			return t.doGet(itemID, false)
+			return t.doGet(itemID, render, false)
 		}
 
 		return nil, errors.New(resp.String())
@@ -114,27 +246,37 @@ func (t *todoImpl) doGet(itemID string, refresh bool) (*models.Item, error) {
 	return &item, nil
 }
 
-// Update update todo item
+// Update update todo item.
+// if item has revision, item is updated only if it is not modified by others; ConflictError is returned if modified
 func (t *todoImpl) Update(item *models.Item) (*models.Item, error) { return t.doUpdate(item, true) }
 func (t *todoImpl) doUpdate(item *models.Item, refresh bool) (*models.Item, error) {
 	if err := t.client.ensureAccessToken(); err != nil {
 		return nil, err
 	}
 
-	resp, err := t.client.sess.Put("%s/%s", t.client.endpoint, item.ID).
+	req := t.client.sess.Put("%s/%s", t.client.endpoint, item.ID).
 		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
-		JSON(item).
-		Do()
+		JSON(item)
+	if item.Revision > 0 {
+		req.Header("If-Match", strconv.Quote(strconv.Itoa(item.Revision)))
+	}
+
+	resp, err := req.Do()
 	if err != nil {
 		return nil, errors.Wrapf(err, "update")
 	}
 
 	if !resp.Success() {
-		if refresh && resp.StatusCode == http.StatusUnauthorized {
-			if err := t.client.auth.refreshAccessToken(); err != nil {
-				return nil, err
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			if refresh {
+				if err := t.client.auth.refreshAccessToken(); err != nil {
+					return nil, err
+				}
+				return t.doUpdate(item, false)
 			}
-			return t.doUpdate(item, false)
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return nil, &ConflictError{ItemID: item.ID, StatusCode: resp.StatusCode, Message: resp.String()}
 		}
 
 		return nil, errors.New(resp.String())
@@ -149,16 +291,156 @@ func (t *todoImpl) doUpdate(item *models.Item, refresh bool) (*models.Item, erro
 	return &updated, nil
 }
 
-// Delete delete todo item
-func (t *todoImpl) Delete(itemID string) error { return t.doDelete(itemID, true) }
-func (t *todoImpl) doDelete(itemID string, refresh bool) error {
+// Patch update fields of todo item with JSON merge patch; null value removes the field
+func (t *todoImpl) Patch(itemID string, patch map[string]interface{}) (*models.Item, error) {
+	return t.doPatch(itemID, patch, true)
+}
+
+func (t *todoImpl) doPatch(itemID string, patch map[string]interface{}, refresh bool) (*models.Item, error) {
 	if err := t.client.ensureAccessToken(); err != nil {
-		return err
+		return nil, err
+	}
+
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "patch")
+	}
+
+	// request sends body only with POST and PUT, so PATCH is sent by net/http
+	req, err := http.NewRequest(http.MethodPatch, t.client.endpoint+"/"+itemID, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "patch")
+	}
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	req.Header.Set(echo.HeaderContentType, mergepatch.ContentType)
+
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "patch")
+	}
+	resp := &request.Response{Response: r}
+
+	if !resp.Success() {
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			if refresh {
+				if err := t.client.auth.refreshAccessToken(); err != nil {
+					return nil, err
+				}
+				return t.doPatch(itemID, patch, false)
+			}
+		case http.StatusConflict, http.StatusPreconditionFailed:
+			return nil, &ConflictError{ItemID: itemID, StatusCode: resp.StatusCode, Message: resp.String()}
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var patched models.Item
+	defer resp.Body.Close()
+	if err := resp.JSON(&patched); err != nil {
+		return nil, errors.Wrapf(err, "patch")
 	}
 
-	resp, err := t.client.sess.Delete("%s/%s", t.client.endpoint, itemID).
+	return &patched, nil
+}
+
+// Batch apply create, update, delete operations in one request
+func (t *todoImpl) Batch(req *models.BatchRequest) (*models.BatchResponse, error) {
+	return t.doBatch(req, true)
+}
+
+func (t *todoImpl) doBatch(req *models.BatchRequest, refresh bool) (*models.BatchResponse, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Post("%s/batch", t.client.endpoint).
 		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(req).
 		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "batch")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doBatch(req, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var result models.BatchResponse
+	defer resp.Body.Close()
+	if err := resp.JSON(&result); err != nil {
+		return nil, errors.Wrapf(err, "batch")
+	}
+
+	return &result, nil
+}
+
+// QuickAdd create todo item from natural language text, or parse only if req.DryRun
+func (t *todoImpl) QuickAdd(req *models.QuickAddRequest) (*models.QuickAddResponse, error) {
+	return t.doQuickAdd(req, true)
+}
+
+func (t *todoImpl) doQuickAdd(req *models.QuickAddRequest, refresh bool) (*models.QuickAddResponse, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Post("%s/quick", t.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(req).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "quick add")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doQuickAdd(req, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var result models.QuickAddResponse
+	defer resp.Body.Close()
+	if err := resp.JSON(&result); err != nil {
+		return nil, errors.Wrapf(err, "quick add")
+	}
+
+	return &result, nil
+}
+
+// Delete delete todo item, subtasks are handled by server configured policy
+func (t *todoImpl) Delete(itemID string) error { return t.doDelete(itemID, "", true) }
+
+// DeleteWithChildren delete todo item, subtasks are handled by the policy: cascade, block, orphan
+func (t *todoImpl) DeleteWithChildren(itemID, policy string) error {
+	return t.doDelete(itemID, policy, true)
+}
+
+func (t *todoImpl) doDelete(itemID, policy string, refresh bool) error {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return err
+	}
+
+	req := t.client.sess.Delete("%s/%s", t.client.endpoint, itemID).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	if policy != "" {
+		req.Param("children", policy)
+	}
+
+	resp, err := req.Do()
 	if err != nil {
 		return errors.Wrapf(err, "delete")
 	}
@@ -168,7 +450,7 @@ func (t *todoImpl) doDelete(itemID string, refresh bool) error {
 			if err := t.client.auth.refreshAccessToken(); err != nil {
 				return err
 			}
-			return t.doDelete(itemID, false)
+			return t.doDelete(itemID, policy, false)
 		}
 
 		return errors.New(resp.String())
@@ -176,3 +458,214 @@ func (t *todoImpl) doDelete(itemID string, refresh bool) error {
 
 	return nil
 }
+
+// Complete mark todo item as completed, subtasks are handled by server configured policy
+func (t *todoImpl) Complete(itemID string) (*models.Item, error) {
+	return t.doAction(itemID, "complete", nil, nil, true)
+}
+
+// CompleteWithChildren mark todo item as completed, subtasks are handled by the policy: cascade, block, orphan
+func (t *todoImpl) CompleteWithChildren(itemID, policy string) (*models.Item, error) {
+	return t.doAction(itemID, "complete", map[string]string{"children": policy}, nil, true)
+}
+
+// Reopen mark todo item as not completed
+func (t *todoImpl) Reopen(itemID string) (*models.Item, error) {
+	return t.doAction(itemID, "reopen", nil, nil, true)
+}
+
+// Move place todo item right before or after the anchor item
+func (t *todoImpl) Move(itemID string, req *models.MoveRequest) (*models.Item, error) {
+	return t.doAction(itemID, "move", nil, req, true)
+}
+
+// doAction post to item action endpoint with optional query parameters, body and returns updated item
+func (t *todoImpl) doAction(itemID, action string, params map[string]string, body interface{}, refresh bool) (*models.Item, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	req := t.client.sess.Post("%s/%s/%s", t.client.endpoint, itemID, action).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Params(params)
+	if body != nil {
+		req.JSON(body)
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, action)
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doAction(itemID, action, params, body, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var item models.Item
+	defer resp.Body.Close()
+	if err := resp.JSON(&item); err != nil {
+		return nil, errors.Wrapf(err, action)
+	}
+
+	return &item, nil
+}
+
+// Tags list tags of user's items with number of items
+func (t *todoImpl) Tags() ([]models.Tag, error) { return t.doTags(true) }
+func (t *todoImpl) doTags(refresh bool) ([]models.Tag, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Get("%s/tags", t.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "tags")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doTags(false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	tags := make([]models.Tag, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&tags); err != nil {
+		return nil, errors.Wrapf(err, "tags")
+	}
+
+	return tags, nil
+}
+
+// View list open items of the view: today, upcoming or overdue; days is for upcoming view, 0 for default
+func (t *todoImpl) View(view string, days int) ([]models.Item, error) {
+	return t.doView(view, days, true)
+}
+func (t *todoImpl) doView(view string, days int, refresh bool) ([]models.Item, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	req := t.client.sess.Get("%s/views/%s", t.client.endpoint, view).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken)
+	if days > 0 {
+		req.Param("days", strconv.Itoa(days))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "view")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doView(view, days, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	items := make([]models.Item, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&items); err != nil {
+		return nil, errors.Wrapf(err, "view")
+	}
+
+	return items, nil
+}
+
+// Search full text search of items, results are ordered by relevance; limit 0 for server default
+func (t *todoImpl) Search(query string, limit int) ([]models.SearchResult, error) {
+	return t.doSearch(query, limit, true)
+}
+
+func (t *todoImpl) doSearch(query string, limit int, refresh bool) ([]models.SearchResult, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	req := t.client.sess.Get("%s/search", t.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		Param("q", query)
+	if limit > 0 {
+		req.Param("limit", strconv.Itoa(limit))
+	}
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "search")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doSearch(query, limit, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	results := make([]models.SearchResult, 0)
+	defer resp.Body.Close()
+	if err := resp.JSON(&results); err != nil {
+		return nil, errors.Wrapf(err, "search")
+	}
+
+	return results, nil
+}
+
+// RenameTag rename tag of all items, tags are merged if new name is already used
+func (t *todoImpl) RenameTag(tag, name string) (*models.Tag, error) {
+	return t.doRenameTag(tag, name, true)
+}
+func (t *todoImpl) doRenameTag(tag, name string, refresh bool) (*models.Tag, error) {
+	if err := t.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.client.sess.Put("%s/tags/%s", t.client.endpoint, url.PathEscape(tag)).
+		Header(echo.HeaderAuthorization, "Bearer "+t.client.accessToken).
+		JSON(&models.Tag{Name: name}).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "rename tag")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := t.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return t.doRenameTag(tag, name, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var renamed models.Tag
+	defer resp.Body.Close()
+	if err := resp.JSON(&renamed); err != nil {
+		return nil, errors.Wrapf(err, "rename tag")
+	}
+
+	return &renamed, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/models"
+)
+
+type userImpl struct {
+	client *clientImpl
+}
+
+// Me get current user
+func (u *userImpl) Me() (*models.User, error) { return u.doMe(true) }
+func (u *userImpl) doMe(refresh bool) (*models.User, error) {
+	if err := u.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.sess.Get("%s/users/me", u.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+u.client.accessToken).
+		Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "me")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := u.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return u.doMe(false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var user models.User
+
+	defer resp.Body.Close()
+	if err := resp.JSON(&user); err != nil {
+		return nil, errors.Wrapf(err, "me")
+	}
+
+	return &user, nil
+}
+
+// Update update settings of current user
+func (u *userImpl) Update(user *models.User) (*models.User, error) { return u.doUpdate(user, true) }
+func (u *userImpl) doUpdate(user *models.User, refresh bool) (*models.User, error) {
+	if err := u.client.ensureAccessToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := u.client.sess.Put("%s/users/me", u.client.endpoint).
+		Header(echo.HeaderAuthorization, "Bearer "+u.client.accessToken).
+		JSON(user).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	if !resp.Success() {
+		if refresh && resp.StatusCode == http.StatusUnauthorized {
+			if err := u.client.auth.refreshAccessToken(); err != nil {
+				return nil, err
+			}
+			return u.doUpdate(user, false)
+		}
+
+		return nil, errors.New(resp.String())
+	}
+
+	var updated models.User
+
+	defer resp.Body.Close()
+	if err := resp.JSON(&updated); err != nil {
+		return nil, errors.Wrapf(err, "update")
+	}
+
+	return &updated, nil
+}
@@ -1,5 +1,9 @@
 package main
 
+import (
+	_ "time/tzdata" // time zone database for user time zones
+)
+
 func main() {
 	rootCmd.Execute()
 }
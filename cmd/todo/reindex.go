@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/whitekid/go-todo/config"
+	"github.com/whitekid/go-todo/storage"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "rebuild search index",
+	Long:  "rebuild search index of all todo items, for items written before search is supported",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// storage flag is also bound by root command, so bind it to the running command
+		return viper.BindPFlags(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := storage.New("todo")
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		return s.SearchService().Rebuild()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+	config.InitFlagSet(reindexCmd.Use, reindexCmd.Flags())
+}
@@ -16,7 +16,7 @@ var configs = map[string][]struct {
 	description  string
 }{
 	"todo": {
-		{keyStorage, "s", "badger", "todo storage type"},
+		{keyStorage, "s", "badger", "todo storage type: badger, sqlite, memory"},
 		{keyClientID, "", "your-client-id", "google auth client id"},
 		{keyClientSecret, "", "your-client-secret", "google auth client secret"},
 		{keyRootURL, "u", "http://127.0.0.1", "application root url"},
@@ -24,10 +24,17 @@ var configs = map[string][]struct {
 		{teyTokenSigningKey, "", []byte("signing-key"), "jwt token signing key"},
 		{keyRefreshTokenDuration, "", time.Hour * 24 * 14, "refresh token duration"}, // refresh token expires in 2 weeks
 		{keyAccessTokenDuration, "", time.Minute * 30, "access token duration"},      // access token expires in 30 mins
+		{keyChildPolicy, "", "block", "policy of subtasks when parent is completed or deleted: cascade, block, orphan"},
+		{keyAttachmentDir, "", "attachments", "directory of attachment contents"},
+		{keyAttachmentMaxSize, "", 10 << 20, "maximum size of an attachment in bytes"},
+		{keyAttachmentQuota, "", 100 << 20, "maximum total size of user's attachments in bytes, 0 for unlimited"},
 	},
 	"hello": {
 		{"world", "w", "world", "saying hello world"},
 	},
+	"reindex": {
+		{keyStorage, "s", "badger", "todo storage type: badger, sqlite, memory"},
+	},
 }
 
 func init() {
@@ -57,6 +64,8 @@ func InitFlagSet(use string, fs *pflag.FlagSet) {
 		switch v := config.defaultValue.(type) {
 		case string:
 			fs.StringP(config.key, config.short, v, config.description)
+		case int:
+			fs.IntP(config.key, config.short, v, config.description)
 		case time.Duration:
 			fs.DurationP(config.key, config.short, v, config.description)
 		case []byte:
@@ -39,6 +39,10 @@ func TestDefault(t *testing.T) {
 		{"TokenSignKey", args{teyTokenSigningKey, func() interface{} { return TokenSignKey() }}},
 		{"RefreshTokenDuration", args{keyRefreshTokenDuration, func() interface{} { return RefreshTokenDuration() }}},
 		{"AccessTokenDuration", args{keyAccessTokenDuration, func() interface{} { return AccessTokenDuration() }}},
+		{"ChildPolicy", args{keyChildPolicy, func() interface{} { return ChildPolicy() }}},
+		{"AttachmentDir", args{keyAttachmentDir, func() interface{} { return AttachmentDir() }}},
+		{"AttachmentMaxSize", args{keyAttachmentMaxSize, func() interface{} { return AttachmentMaxSize() }}},
+		{"AttachmentQuota", args{keyAttachmentQuota, func() interface{} { return AttachmentQuota() }}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
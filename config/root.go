@@ -15,6 +15,10 @@ const (
 	teyTokenSigningKey      = "token_signkey"
 	keyRefreshTokenDuration = "refresh_token_duration"
 	keyAccessTokenDuration  = "access_token_duration"
+	keyChildPolicy          = "child_policy"
+	keyAttachmentDir        = "attachment_dir"
+	keyAttachmentMaxSize    = "attachment_max_size"
+	keyAttachmentQuota      = "attachment_quota"
 )
 
 func ClientID() string                    { return viper.GetString(keyClientID) }
@@ -25,3 +29,7 @@ func Storage() string                     { return viper.GetString(keyStorage) }
 func TokenSignKey() []byte                { return []byte(viper.GetString(teyTokenSigningKey)) }
 func RefreshTokenDuration() time.Duration { return viper.GetDuration(keyRefreshTokenDuration) }
 func AccessTokenDuration() time.Duration  { return viper.GetDuration(keyAccessTokenDuration) }
+func ChildPolicy() string                 { return viper.GetString(keyChildPolicy) }
+func AttachmentDir() string               { return viper.GetString(keyAttachmentDir) }
+func AttachmentMaxSize() int              { return viper.GetInt(keyAttachmentMaxSize) }
+func AttachmentQuota() int                { return viper.GetInt(keyAttachmentQuota) }
@@ -19,7 +19,6 @@ var doc = `{
         "description": "{{.Description}}",
         "title": "{{.Title}}",
         "contact": {},
-        "license": {},
         "version": "{{.Version}}"
     },
     "host": "{{.Host}}",
@@ -32,11 +31,121 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "list todo item",
+                "description": "list todo item, items of lists shared with the user are included\nnext page is returned in Link header with rel=\"next\".\nif tree is true, all matched items are returned as tree of models.TreeItem without pagination",
                 "tags": [
                     "todo"
                 ],
                 "summary": "list todo item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "maximum number of items",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rank",
+                            "due_date",
+                            "title",
+                            "created",
+                            "updated",
+                            "priority"
+                        ],
+                        "type": "string",
+                        "description": "sort order, default rank",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "items due before the date",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "items due after the date",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "open",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "status filter, default all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "items of the list",
+                        "name": "list_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "items with tags, comma for AND, | for OR; e.g. work,urgent|home",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "subtasks of the item",
+                        "name": "parent_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "returns items as tree of subtasks",
+                        "name": "tree",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only items whose dependencies are all completed",
+                        "name": "actionable",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "overdue"
+                        ],
+                        "type": "string",
+                        "description": "items due today or open items overdue, in user's time zone",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query, e.g. tag:work and due\u003c+3d and not done; see package query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -47,6 +156,12 @@ var doc = `{
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -138,27 +253,182 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/todo.HTTPError"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
                     }
                 }
             }
         },
-        "/{item_id}": {
-            "get": {
+        "/batch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get todo item",
+                "description": "apply operations and returns result of each operation in same order.\nupdate and delete are applied only if item matches to revision if given.\nif atomic, all operations are applied or nothing applied; operations not applied by other's failure results 424.\nitem with subtasks can not be deleted in batch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "todo"
                 ],
-                "summary": "get todo item",
+                "summary": "batch create, update, delete todo items",
+                "parameters": [
+                    {
+                        "description": "operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/filters/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list user's saved filters, ordered by name",
+                "tags": [
+                    "filter"
+                ],
+                "summary": "list filters",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Filter"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create saved filter; query is validated on save",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filter"
+                ],
+                "summary": "create filter",
+                "parameters": [
+                    {
+                        "description": "filter",
+                        "name": "filter",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Filter"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Filter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/filters/{filter_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get saved filter",
+                "tags": [
+                    "filter"
+                ],
+                "summary": "get filter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "todo item ID",
-                        "name": "item_id",
+                        "description": "filter ID",
+                        "name": "filter_id",
                         "in": "path",
                         "required": true
                     }
@@ -167,7 +437,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "$ref": "#/definitions/models.Filter"
                         }
                     },
                     "401": {
@@ -196,26 +466,26 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update todo item",
+                "description": "update saved filter",
                 "tags": [
-                    "todo"
+                    "filter"
                 ],
-                "summary": "update todo item",
+                "summary": "update filter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "todo item ID",
-                        "name": "item_id",
+                        "description": "filter ID",
+                        "name": "filter_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "todo item",
-                        "name": "item",
+                        "description": "filter",
+                        "name": "filter",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "$ref": "#/definitions/models.Filter"
                         }
                     }
                 ],
@@ -223,7 +493,7 @@ var doc = `{
                     "202": {
                         "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Item"
+                            "$ref": "#/definitions/models.Filter"
                         }
                     },
                     "400": {
@@ -258,16 +528,16 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "delete todo item",
+                "description": "delete saved filter",
                 "tags": [
-                    "todo"
+                    "filter"
                 ],
-                "summary": "delete todo item",
+                "summary": "delete filter",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "todo item ID",
-                        "name": "item_id",
+                        "description": "filter ID",
+                        "name": "filter_id",
                         "in": "path",
                         "required": true
                     }
@@ -299,39 +569,3360 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Item": {
-            "$ref": "#/definitions/storage.TodoItem"
         },
-        "storage.TodoItem": {
-            "$ref": "#/definitions/types.TodoItem"
-        },
-        "todo.HTTPError": {
-            "$ref": "#/definitions/echo.HTTPError"
-        },
-        "types.TodoItem": {
-            "type": "object",
-            "properties": {
-                "due_date": {
-                    "type": "string",
-                    "example": "2006-01-02"
-                },
-                "id": {
-                    "type": "string",
-                    "format": "uuid",
-                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
-                },
-                "rank": {
-                    "description": "rank order",
-                    "type": "integer",
-                    "format": "int",
-                    "example": 1
-                },
-                "title": {
-                    "type": "string",
-                    "example": "do something in future"
+        "/filters/{filter_id}/todos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list todo items matched to saved filter, items of lists shared with the user are included.\nother list options are applied together; q is combined with the filter by and\nnext page is returned in Link header with rel=\"next\"",
+                "tags": [
+                    "filter"
+                ],
+                "summary": "list todo items of filter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter ID",
+                        "name": "filter_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of items",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rank",
+                            "due_date",
+                            "title",
+                            "created",
+                            "updated",
+                            "priority"
+                        ],
+                        "type": "string",
+                        "description": "sort order, default rank",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "open",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "status filter, default all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "additional query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list user's lists and lists shared with the user, ordered by name",
+                "tags": [
+                    "list"
+                ],
+                "summary": "list lists",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.List"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "create list",
+                "parameters": [
+                    {
+                        "description": "list",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{list_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list",
+                "tags": [
+                    "list"
+                ],
+                "summary": "get list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update list, only owner can update the list",
+                "tags": [
+                    "list"
+                ],
+                "summary": "update list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "list",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete list, only owner can delete the list. list with items is deleted only if cascade is given, then its items are deleted too",
+                "tags": [
+                    "list"
+                ],
+                "summary": "delete list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "delete items of the list",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{list_id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list members of list, owner comes first",
+                "tags": [
+                    "list"
+                ],
+                "summary": "list members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Member"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "share list with the user of email, or change role of the member. only owner can invite",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "list"
+                ],
+                "summary": "invite member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member to invite",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Member"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{list_id}/members/{email}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "stop sharing list with the member. owner can delete any member, member can leave the list",
+                "tags": [
+                    "list"
+                ],
+                "summary": "delete member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "email of member",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{list_id}/todos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list todo items of list, same as listing todo items with list_id",
+                "tags": [
+                    "list"
+                ],
+                "summary": "list todo items of list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "list ID",
+                        "name": "list_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of items",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rank",
+                            "due_date",
+                            "title",
+                            "created",
+                            "updated",
+                            "priority"
+                        ],
+                        "type": "string",
+                        "description": "sort order, default rank",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "items due before the date",
+                        "name": "due_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "items due after the date",
+                        "name": "due_after",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "open",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "status filter, default all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "items with tags, comma for AND, | for OR; e.g. work,urgent|home",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only items whose dependencies are all completed",
+                        "name": "actionable",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "overdue"
+                        ],
+                        "type": "string",
+                        "description": "items due today or open items overdue, in user's time zone",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query, e.g. tag:work and due\u003c+3d and not done; see package query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/quick": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create todo item from natural language text such as \"Pay rent every month on the 1st #home !high tomorrow 9am\".\ntitle, due date, due time, tags, priority and recurrence are parsed in user's time zone;\ninterpreted words are returned as tokens. words in double quotes are kept in title.\nif dry_run, item is not created and 200 is returned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "quick add todo item",
+                "parameters": [
+                    {
+                        "description": "text of todo item",
+                        "name": "quick",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.QuickAddResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "full text search of todo items, items of lists shared with the user are included.\nevery word of query matches to words of item which begin with it, so \"rep\" matches \"report\".\nresults are ordered by relevance; whole words and words in title score higher",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "search todo items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "words to search",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of results, default 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list tags of user's items with number of items, ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "list tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "rename tag of all user's items. if the new name is already used, tags are merged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "rename tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tag to rename",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new name of tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get current user with settings",
+                "tags": [
+                    "user"
+                ],
+                "summary": "get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update settings of current user; email can not be changed\ntime_zone is IANA time zone name, today and overdue filters are computed in the zone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "update current user",
+                "parameters": [
+                    {
+                        "description": "user",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/views/{view}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "open items of the view in user's time zone, items of lists shared with the user are included.\ntoday: due today or before, upcoming: due in next days, overdue: due time is passed.\nitems are ordered by due date, priority from highest, due time and rank; pagination is not applied",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "view of todo items",
+                "parameters": [
+                    {
+                        "enum": [
+                            "today",
+                            "upcoming",
+                            "overdue"
+                        ],
+                        "type": "string",
+                        "description": "view",
+                        "name": "view",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "days of upcoming view, default 7",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get todo item",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "get todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update todo item, completion state is changed by complete and reopen.\nif If-Match is given, item is updated only if it matches to ETag of stored item",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "update todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "todo item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete todo item, if If-Match is given item is deleted only if it matches to ETag of stored item.\nsubtasks are deleted together on cascade, detached on orphan; item with subtasks is not deleted on block",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "delete todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "cascade",
+                            "block",
+                            "orphan"
+                        ],
+                        "type": "string",
+                        "description": "policy of subtasks, default is server configuration",
+                        "name": "children",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update todo item with JSON merge patch, RFC 7396. server managed fields are ignored.\nif If-Match is given, item is updated only if it matches to ETag of stored item",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "patch todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "merge patch of todo item",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list changes of todo item, who created, edited, completed, reopened or moved the item and which fields changed.\nordered by time",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "activity feed of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Activity"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list files attached to todo item, ordered by uploaded time",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "list attachments of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Attachment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "upload file as multipart form. same content is stored once.\nattachments are counted to quota of the item's owner",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "attach file to todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "file to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/attachments/{attachment_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "download content of attached file",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "download attachment of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete attachment, its content is removed if no other attachment has same content",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "delete attachment of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/children": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list direct subtasks of todo item\nnext page is returned in Link header with rel=\"next\"",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "list subtasks of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of items",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "cursor of next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rank",
+                            "due_date",
+                            "title",
+                            "created",
+                            "updated",
+                            "priority"
+                        ],
+                        "type": "string",
+                        "description": "sort order, default rank",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "open",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "status filter, default all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only items whose dependencies are all completed",
+                        "name": "actionable",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "today",
+                            "overdue"
+                        ],
+                        "type": "string",
+                        "description": "items due today or open items overdue, in user's time zone",
+                        "name": "due",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "query, e.g. tag:work and due\u003c+3d and not done; see package query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "render notes as notes_html",
+                        "name": "render",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list comments on todo item, ordered by created time",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "list comments of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Comment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "add comment on todo item, users who can see the item can comment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "comment on todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "comment, only body is used",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/comments/{comment_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get comment on todo item",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "get comment of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "edit body of comment, only the author can edit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "edit comment of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "comment, only body is used",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete comment, the author or owner of the item can delete",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "delete comment of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "comment ID",
+                        "name": "comment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {},
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "mark todo item as completed, completed_at is kept if already completed.\nif item has recurrence, next occurrence is created with the recurrence.\nopen subtasks are completed together on cascade, detached on orphan; item with open subtasks is not completed on block",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "complete todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "cascade",
+                            "block",
+                            "orphan"
+                        ],
+                        "type": "string",
+                        "description": "policy of subtasks, default is server configuration",
+                        "name": "children",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "list items which block the todo item",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "list dependencies of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Dependency"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "todo item is blocked by the item it depends on until the item is completed.\nboth items should have same owner; dependency which makes cycle is rejected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "add dependency of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "item to depend on",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Dependency"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Dependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/dependencies/{dep_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete dependency of todo item",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "delete dependency of todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of item which the item depends on",
+                        "name": "dep_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/move": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "change rank of item to place it right before or after the anchor item.\nonly the moved item is changed, unless there is no room between ranks of neighbours;\nthen ranks of all items are rebalanced",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "todo"
+                ],
+                "summary": "move todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "anchor item, one of before or after",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MoveRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/{item_id}/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "mark todo item as not completed",
+                "tags": [
+                    "todo"
+                ],
+                "summary": "reopen todo item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "todo item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of item",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Item"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of item"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/todo.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "completed",
+                        "reopened",
+                        "moved"
+                    ],
+                    "example": "updated"
+                },
+                "actor": {
+                    "description": "email of user who changed the item",
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "fields": {
+                    "description": "changed fields, in JSON names",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "title",
+                        "due_date"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "7e6d5c4b-3a29-4180-9f8e-7d6c5b4a3928"
+                },
+                "item_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                }
+            }
+        },
+        "models.Attachment": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "image/png"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "created_by": {
+                    "description": "email of uploader",
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "hash": {
+                    "description": "SHA-256 of content",
+                    "type": "string",
+                    "example": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "9a7b6c5d-4e3f-4a2b-8c1d-0e9f8a7b6c5d"
+                },
+                "item_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "name": {
+                    "description": "file name",
+                    "type": "string",
+                    "example": "screenshot.png"
+                },
+                "size": {
+                    "description": "in bytes",
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "models.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "item ID to delete",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "item": {
+                    "description": "item to create or update",
+                    "$ref": "#/definitions/models.Item"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "example": "update"
+                },
+                "revision": {
+                    "description": "expected revision for update and delete, like If-Match",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "description": "all operations succeed or all fail",
+                    "type": "boolean",
+                    "example": false
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchOperation"
+                    }
+                }
+            }
+        },
+        "models.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchResult"
+                    }
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "item": {
+                    "description": "created or updated item",
+                    "$ref": "#/definitions/models.Item"
+                },
+                "status": {
+                    "description": "http status code of operation",
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "models.Comment": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "author": {
+                    "description": "server managed metadata, client supplied values are ignored",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "user@example.com"
+                },
+                "body": {
+                    "description": "up to MaxCommentSize bytes",
+                    "type": "string",
+                    "example": "done with the first draft"
+                },
+                "created_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "3c2b1a09-8f7e-4d6c-9b5a-4e3d2c1b0a9f"
+                },
+                "item_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.Dependency": {
+            "type": "object",
+            "required": [
+                "depends_on",
+                "item_id"
+            ],
+            "properties": {
+                "depends_on": {
+                    "description": "blocking item",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "1c8f3a5e-0b6d-4c1e-9f62-2b7d8e4a5c31"
+                },
+                "item_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                }
+            }
+        },
+        "models.Filter": {
+            "type": "object",
+            "required": [
+                "id",
+                "name",
+                "query"
+            ],
+            "properties": {
+                "created_at": {
+                    "description": "server managed metadata, client supplied values are ignored",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "3e5d2c1b-8f4a-4b6e-9d7c-1a2b3c4d5e6f"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "work this week"
+                },
+                "query": {
+                    "description": "query of package query",
+                    "type": "string",
+                    "example": "tag:work and due\u003c+7d and open"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.Item": {
+            "type": "object",
+            "required": [
+                "id",
+                "title"
+            ],
+            "properties": {
+                "completed": {
+                    "description": "set by complete, reopen",
+                    "type": "boolean",
+                    "readOnly": true,
+                    "example": false
+                },
+                "completed_at": {
+                    "description": "set when completed",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "created_at": {
+                    "description": "server managed metadata, client supplied values are ignored",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "due_time": {
+                    "description": "time of due date in user's time zone, empty for all day",
+                    "type": "string",
+                    "example": "15:04"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "links": {
+                    "description": "derived from notes, client supplied values are ignored",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Link"
+                    },
+                    "readOnly": true
+                },
+                "list_id": {
+                    "description": "list of item, empty for no list",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"
+                },
+                "notes": {
+                    "description": "description in Markdown, up to MaxNotesSize bytes",
+                    "type": "string",
+                    "example": "see [docs](https://example.com/docs)"
+                },
+                "notes_html": {
+                    "description": "sanitized HTML of notes, only for render=html",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "\u003cp\u003esee \u003cem\u003edocs\u003c/em\u003e\u003c/p\u003e"
+                },
+                "parent_id": {
+                    "description": "parent item of subtask",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ],
+                    "example": "high"
+                },
+                "rank": {
+                    "description": "rank order",
+                    "type": "integer",
+                    "format": "int",
+                    "example": 1
+                },
+                "recurrence": {
+                    "description": "repeat rule, next occurrence is created when the item is completed",
+                    "$ref": "#/definitions/types.Recurrence"
+                },
+                "revision": {
+                    "description": "increased on every update",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1
+                },
+                "tags": {
+                    "description": "case insensitive labels",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "urgent"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "do something in future"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.List": {
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "shopping"
+                },
+                "owner": {
+                    "description": "server managed metadata, client supplied values are ignored",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "owner@example.com"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                }
+            }
+        },
+        "models.Member": {
+            "type": "object",
+            "required": [
+                "email",
+                "list_id",
+                "owner",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "friend@example.com"
+                },
+                "list_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"
+                },
+                "owner": {
+                    "description": "email of list owner",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "owner@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "editor",
+                        "viewer"
+                    ],
+                    "example": "editor"
+                }
+            }
+        },
+        "models.MoveRequest": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "before": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                }
+            }
+        },
+        "models.QuickAddRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "parse only, item is not created",
+                    "type": "boolean",
+                    "example": false
+                },
+                "list_id": {
+                    "description": "list of item",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Pay rent every month on the 1st #home !high tomorrow 9am"
+                }
+            }
+        },
+        "models.QuickAddResponse": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/models.Item"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/quickadd.Token"
+                    }
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/types.TodoItem"
+                },
+                "score": {
+                    "description": "relevance to query, higher is better",
+                    "type": "number",
+                    "example": 2.5
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "work"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "IANA time zone, default UTC",
+                    "type": "string",
+                    "example": "Asia/Seoul"
+                }
+            }
+        },
+        "quickadd.Token": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "how the words are interpreted",
+                    "type": "string",
+                    "enum": [
+                        "tag",
+                        "priority",
+                        "date",
+                        "time",
+                        "recurrence"
+                    ],
+                    "example": "date"
+                },
+                "text": {
+                    "description": "words of the text",
+                    "type": "string",
+                    "example": "tomorrow"
+                },
+                "value": {
+                    "description": "interpreted value, recurrence in RRULE format",
+                    "type": "string",
+                    "example": "2006-01-02"
+                }
+            }
+        },
+        "todo.HTTPError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "object"
+                }
+            }
+        },
+        "types.Link": {
+            "type": "object",
+            "properties": {
+                "text": {
+                    "description": "link text, empty for bare URL",
+                    "type": "string",
+                    "example": "docs"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/docs"
+                }
+            }
+        },
+        "types.Recurrence": {
+            "type": "object",
+            "properties": {
+                "after_completion": {
+                    "description": "next due date counts from completion instead of due date",
+                    "type": "boolean",
+                    "example": false
+                },
+                "freq": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ],
+                    "example": "weekly"
+                },
+                "interval": {
+                    "description": "every interval days, weeks or months; default 1",
+                    "type": "integer",
+                    "example": 1
+                },
+                "month_day": {
+                    "description": "monthly: day of month or -1 for the last day, default day of due date",
+                    "type": "integer",
+                    "example": 31
+                },
+                "rrule": {
+                    "description": "RFC 5545 RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, UNTIL.\nit is parsed into the other fields and not stored",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"
+                },
+                "until": {
+                    "description": "no occurrence after the date",
+                    "type": "string",
+                    "example": "2006-12-31"
+                },
+                "weekdays": {
+                    "description": "weekly: days of week, default weekday of due date",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "MO",
+                            "TU",
+                            "WE",
+                            "TH",
+                            "FR",
+                            "SA",
+                            "SU"
+                        ]
+                    },
+                    "example": [
+                        "MO",
+                        "WE"
+                    ]
+                }
+            }
+        },
+        "types.TodoItem": {
+            "type": "object",
+            "required": [
+                "id",
+                "title"
+            ],
+            "properties": {
+                "completed": {
+                    "description": "set by complete, reopen",
+                    "type": "boolean",
+                    "readOnly": true,
+                    "example": false
+                },
+                "completed_at": {
+                    "description": "set when completed",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "created_at": {
+                    "description": "server managed metadata, client supplied values are ignored",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2006-01-02"
+                },
+                "due_time": {
+                    "description": "time of due date in user's time zone, empty for all day",
+                    "type": "string",
+                    "example": "15:04"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "links": {
+                    "description": "derived from notes, client supplied values are ignored",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Link"
+                    },
+                    "readOnly": true
+                },
+                "list_id": {
+                    "description": "list of item, empty for no list",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"
+                },
+                "notes": {
+                    "description": "description in Markdown, up to MaxNotesSize bytes",
+                    "type": "string",
+                    "example": "see [docs](https://example.com/docs)"
+                },
+                "notes_html": {
+                    "description": "sanitized HTML of notes, only for render=html",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "\u003cp\u003esee \u003cem\u003edocs\u003c/em\u003e\u003c/p\u003e"
+                },
+                "parent_id": {
+                    "description": "parent item of subtask",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "urgent"
+                    ],
+                    "example": "high"
+                },
+                "rank": {
+                    "description": "rank order",
+                    "type": "integer",
+                    "format": "int",
+                    "example": 1
+                },
+                "recurrence": {
+                    "description": "repeat rule, next occurrence is created when the item is completed",
+                    "$ref": "#/definitions/types.Recurrence"
+                },
+                "revision": {
+                    "description": "increased on every update",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1
+                },
+                "tags": {
+                    "description": "case insensitive labels",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "work",
+                        "urgent"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "do something in future"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2006-01-02T15:04:05Z"
                 }
             }
         }
@@ -7,7 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 	"github.com/whitekid/go-todo/config"
-	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/storage/memory"
 	"github.com/whitekid/go-todo/tokens"
 	"github.com/whitekid/go-utils/request"
 )
@@ -15,7 +15,7 @@ import (
 func TestAuth(t *testing.T) {
 	e := echo.New()
 
-	storage, err := storage.New("testdb")
+	storage, err := memory.New("")
 	require.NoError(t, err)
 	defer storage.Close()
 	handler := New(storage)
@@ -33,5 +33,8 @@ func TestAuth(t *testing.T) {
 	resp, err := request.Put("%s/tokens", ts.URL).Header(echo.HeaderAuthorization, "Bearer "+token).Do()
 	require.NoError(t, err)
 	require.True(t, resp.Success(), "failed with status %d", resp.StatusCode)
-	require.Equal(t, token, resp.Header.Get(echo.HeaderAuthorization))
+
+	issuer, err := tokens.Parse(resp.Header.Get(echo.HeaderAuthorization))
+	require.NoError(t, err)
+	require.Equal(t, email, issuer)
 }
@@ -0,0 +1,254 @@
+package filter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/handlers/todo"
+	"github.com/whitekid/go-todo/httphandler"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/query"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/tokens"
+	"github.com/whitekid/go-utils/log"
+)
+
+// New create saved filter handler
+func New(storage storage.Interface) httphandler.Interface {
+	return &filterHandler{
+		storage: storage,
+	}
+}
+
+type filterHandler struct {
+	storage storage.Interface
+}
+
+func (h *filterHandler) Route(r httphandler.Router) {
+	r.Use(tokens.TokenMiddleware(h.storage, false))
+
+	r.POST("/", h.handleCreate)
+	r.GET("/", h.handleList)
+	r.GET("/:filter_id", h.handleGet)
+	r.PUT("/:filter_id", h.handleUpdate)
+	r.DELETE("/:filter_id", h.handleDelete)
+	r.GET("/:filter_id/todos", h.handleTodos)
+}
+
+func (h *filterHandler) user(c echo.Context) *storage.User {
+	return c.Get("user").(*storage.User)
+}
+
+// httpError convert storage error to http error
+func httpError(err error) error {
+	switch err {
+	case storage.ErrNotFound:
+		return echo.NewHTTPError(http.StatusNotFound)
+	case storage.ErrInvalidCursor:
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case storage.ErrNotAuthenticated:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	return err
+}
+
+// validate validate filter and its query
+func validate(filter *models.Filter) error {
+	if err := filter.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if _, err := query.Parse(filter.Query); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid query: "+err.Error())
+	}
+
+	return nil
+}
+
+// @summary create filter
+// @description create saved filter; query is validated on save
+// @tags filter
+// @accept json
+// @produce json
+// @param filter body models.Filter true "filter"
+// @success 201 {object} models.Filter
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /filters/ [post]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleCreate(c echo.Context) error {
+	var filter models.Filter
+
+	if err := c.Bind(&filter); err != nil {
+		log.Errorf("bind failed: %s", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	filter.ID = uuid.New().String()
+	filter.CreatedAt = time.Now().UTC().Round(0)
+	filter.UpdatedAt = filter.CreatedAt
+	if err := validate(&filter); err != nil {
+		return err
+	}
+
+	if err := h.storage.FilterService().Create(h.user(c).Email, &filter); err != nil {
+		return errors.Wrapf(err, "filter create failed: %s", err)
+	}
+
+	return c.JSON(http.StatusCreated, &filter)
+}
+
+// @summary list filters
+// @description list user's saved filters, ordered by name
+// @tags filter
+// @success 200 {array} models.Filter
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /filters/ [get]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleList(c echo.Context) error {
+	filters, err := h.storage.FilterService().List(h.user(c).Email)
+	if err != nil {
+		return httpError(err)
+	}
+
+	return c.JSON(http.StatusOK, filters)
+}
+
+// @summary get filter
+// @description get saved filter
+// @tags filter
+// @param filter_id path string true "filter ID"
+// @success 200 {object} models.Filter
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /filters/{filter_id} [get]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleGet(c echo.Context) error {
+	filter, err := h.storage.FilterService().Get(h.user(c).Email, c.Param("filter_id"))
+	if err != nil {
+		return httpError(err)
+	}
+
+	return c.JSON(http.StatusOK, filter)
+}
+
+// @summary update filter
+// @description update saved filter
+// @tags filter
+// @param filter_id path string true "filter ID"
+// @param filter body models.Filter true "filter"
+// @success 202 {object} models.Filter
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /filters/{filter_id} [put]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleUpdate(c echo.Context) error {
+	filterID := c.Param("filter_id")
+
+	var filter models.Filter
+	if err := c.Bind(&filter); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if filter.ID != filterID {
+		return echo.NewHTTPError(http.StatusBadRequest, "filter ID must be same to given to path")
+	}
+
+	if err := validate(&filter); err != nil {
+		return err
+	}
+
+	old, err := h.storage.FilterService().Get(h.user(c).Email, filterID)
+	if err != nil {
+		return httpError(err)
+	}
+
+	filter.CreatedAt = old.CreatedAt
+	filter.UpdatedAt = time.Now().UTC().Round(0)
+
+	if err := h.storage.FilterService().Update(h.user(c).Email, &filter); err != nil {
+		return httpError(err)
+	}
+
+	return c.JSON(http.StatusAccepted, &filter)
+}
+
+// @summary delete filter
+// @description delete saved filter
+// @tags filter
+// @param filter_id path string true "filter ID"
+// @success 204 {string} string
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /filters/{filter_id} [delete]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleDelete(c echo.Context) error {
+	if err := h.storage.FilterService().Delete(h.user(c).Email, c.Param("filter_id")); err != nil {
+		return httpError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @summary list todo items of filter
+// @description list todo items matched to saved filter, items of lists shared with the user are included.
+// @description other list options are applied together; q is combined with the filter by and
+// @description next page is returned in Link header with rel="next"
+// @tags filter
+// @param filter_id path string true "filter ID"
+// @param limit query int false "maximum number of items"
+// @param cursor query string false "cursor of next page"
+// @param sort query string false "sort order, default rank" Enums(rank, due_date, title, created, updated, priority)
+// @param status query string false "status filter, default all" Enums(all, open, completed)
+// @param q query string false "additional query"
+// @param render query string false "render notes as notes_html" Enums(html)
+// @success 200 {array} models.Item
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /filters/{filter_id}/todos [get]
+// @Security ApiKeyAuth
+func (h *filterHandler) handleTodos(c echo.Context) error {
+	filter, err := h.storage.FilterService().Get(h.user(c).Email, c.Param("filter_id"))
+	if err != nil {
+		return httpError(err)
+	}
+
+	opts, err := models.ParseListOptions(c.QueryParams())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	opts.Location = h.user(c).Location()
+
+	if opts.Query == "" {
+		opts.Query = filter.Query
+	} else {
+		opts.Query = "(" + filter.Query + ") and (" + opts.Query + ")"
+	}
+
+	if err := query.Compile(opts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, next, err := storage.ListItems(h.storage, h.user(c).Email, opts)
+	if err != nil {
+		return httpError(err)
+	}
+
+	if err := todo.RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	httphandler.SetNextLink(c, next)
+	return c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,426 @@
+package list
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/handlers/todo"
+	"github.com/whitekid/go-todo/httphandler"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/query"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/tokens"
+	"github.com/whitekid/go-utils/log"
+)
+
+// New create list handler
+func New(storage storage.Interface) httphandler.Interface {
+	return &listHandler{
+		storage: storage,
+	}
+}
+
+type listHandler struct {
+	storage storage.Interface
+}
+
+func (h *listHandler) Route(r httphandler.Router) {
+	r.Use(tokens.TokenMiddleware(h.storage, false))
+
+	r.POST("/", h.handleCreate)
+	r.GET("/", h.handleList)
+	r.GET("/:list_id", h.handleGet)
+	r.PUT("/:list_id", h.handleUpdate)
+	r.DELETE("/:list_id", h.handleDelete)
+	r.GET("/:list_id/todos", h.handleTodos)
+	r.GET("/:list_id/members", h.handleMembers)
+	r.POST("/:list_id/members", h.handleInvite)
+	r.DELETE("/:list_id/members/:email", h.handleDeleteMember)
+}
+
+func (h *listHandler) user(c echo.Context) *storage.User {
+	return c.Get("user").(*storage.User)
+}
+
+// httpError convert storage error to http error
+func httpError(err error) error {
+	switch err {
+	case storage.ErrNotFound:
+		return echo.NewHTTPError(http.StatusNotFound)
+	case storage.ErrNotEmpty:
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case storage.ErrNotAuthenticated:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+	return err
+}
+
+// forbidden returns error for the user who has no permission to the list
+func forbidden() error {
+	return echo.NewHTTPError(http.StatusForbidden, "permission denied")
+}
+
+// role returns owner of the list and role of the user to it
+func (h *listHandler) role(c echo.Context, listID string) (string, storage.Role, error) {
+	owner, role, err := storage.ListRole(h.storage, h.user(c).Email, listID)
+	if err != nil {
+		return "", "", httpError(err)
+	}
+
+	return owner, role, nil
+}
+
+// @summary create list
+// @description create list
+// @tags list
+// @accept json
+// @produce json
+// @param list body models.List true "list"
+// @success 201 {object} models.List
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /lists/ [post]
+// @Security ApiKeyAuth
+func (h *listHandler) handleCreate(c echo.Context) error {
+	var list models.List
+
+	if err := c.Bind(&list); err != nil {
+		log.Errorf("bind failed: %s", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	list.ID = uuid.New().String()
+	list.CreatedAt = time.Now().UTC().Round(0)
+	list.UpdatedAt = list.CreatedAt
+	if err := list.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	list.Owner = ""
+	if err := h.storage.ListService().Create(h.user(c).Email, &list); err != nil {
+		return errors.Wrapf(err, "list create failed: %s", err)
+	}
+
+	list.Owner = h.user(c).Email
+	return c.JSON(http.StatusCreated, &list)
+}
+
+// @summary list lists
+// @description list user's lists and lists shared with the user, ordered by name
+// @tags list
+// @success 200 {array} models.List
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /lists/ [get]
+// @Security ApiKeyAuth
+func (h *listHandler) handleList(c echo.Context) error {
+	email := h.user(c).Email
+	lists, err := h.storage.ListService().List(email)
+	if err != nil {
+		return httpError(err)
+	}
+
+	for i := range lists {
+		lists[i].Owner = email
+	}
+
+	members, err := h.storage.ListService().Memberships(email)
+	if err != nil {
+		return httpError(err)
+	}
+
+	for _, m := range members {
+		list, err := h.storage.ListService().Get(m.Owner, m.ListID)
+		if err != nil {
+			if err == storage.ErrNotFound {
+				continue
+			}
+			return httpError(err)
+		}
+
+		list.Owner = m.Owner
+		lists = append(lists, *list)
+	}
+	storage.SortLists(lists)
+
+	return c.JSON(http.StatusOK, lists)
+}
+
+// @summary get list
+// @description get list
+// @tags list
+// @param list_id path string true "list ID"
+// @success 200 {object} models.List
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id} [get]
+// @Security ApiKeyAuth
+func (h *listHandler) handleGet(c echo.Context) error {
+	owner, _, err := h.role(c, c.Param("list_id"))
+	if err != nil {
+		return err
+	}
+
+	list, err := h.storage.ListService().Get(owner, c.Param("list_id"))
+	if err != nil {
+		return httpError(err)
+	}
+
+	list.Owner = owner
+	return c.JSON(http.StatusOK, list)
+}
+
+// @summary update list
+// @description update list, only owner can update the list
+// @tags list
+// @param list_id path string true "list ID"
+// @param list body models.List true "list"
+// @success 202 {object} models.List
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id} [put]
+// @Security ApiKeyAuth
+func (h *listHandler) handleUpdate(c echo.Context) error {
+	listID := c.Param("list_id")
+
+	var list models.List
+	if err := c.Bind(&list); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if list.ID != listID {
+		return echo.NewHTTPError(http.StatusBadRequest, "list ID must be same to given to path")
+	}
+
+	if err := list.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if _, role, err := h.role(c, listID); err != nil {
+		return err
+	} else if role != storage.RoleOwner {
+		return forbidden()
+	}
+
+	old, err := h.storage.ListService().Get(h.user(c).Email, listID)
+	if err != nil {
+		return httpError(err)
+	}
+
+	list.Owner = ""
+	list.CreatedAt = old.CreatedAt
+	list.UpdatedAt = time.Now().UTC().Round(0)
+
+	if err := h.storage.ListService().Update(h.user(c).Email, &list); err != nil {
+		return httpError(err)
+	}
+
+	list.Owner = h.user(c).Email
+	return c.JSON(http.StatusAccepted, &list)
+}
+
+// @summary delete list
+// @description delete list, only owner can delete the list. list with items is deleted only if cascade is given, then its items are deleted too
+// @tags list
+// @param list_id path string true "list ID"
+// @param cascade query bool false "delete items of the list"
+// @success 204 {string} string
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @failure 409 {object} todo.HTTPError
+// @router /lists/{list_id} [delete]
+// @Security ApiKeyAuth
+func (h *listHandler) handleDelete(c echo.Context) error {
+	var cascade bool
+	if value := c.QueryParam("cascade"); value != "" {
+		var err error
+		if cascade, err = strconv.ParseBool(value); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid cascade: "+value)
+		}
+	}
+
+	if _, role, err := h.role(c, c.Param("list_id")); err != nil {
+		return err
+	} else if role != storage.RoleOwner {
+		return forbidden()
+	}
+
+	if err := h.storage.ListService().Delete(h.user(c).Email, c.Param("list_id"), cascade); err != nil {
+		return httpError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @summary list todo items of list
+// @description list todo items of list, same as listing todo items with list_id
+// @tags list
+// @param list_id path string true "list ID"
+// @param limit query int false "maximum number of items"
+// @param cursor query string false "cursor of next page"
+// @param sort query string false "sort order, default rank" Enums(rank, due_date, title, created, updated, priority)
+// @param due_before query string false "items due before the date" format(date)
+// @param due_after query string false "items due after the date" format(date)
+// @param status query string false "status filter, default all" Enums(all, open, completed)
+// @param tag query string false "items with tags, comma for AND, | for OR; e.g. work,urgent|home"
+// @param actionable query bool false "only items whose dependencies are all completed"
+// @param due query string false "items due today or open items overdue, in user's time zone" Enums(today, overdue)
+// @param q query string false "query, e.g. tag:work and due<+3d and not done; see package query"
+// @param render query string false "render notes as notes_html" Enums(html)
+// @success 200 {array} models.Item
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id}/todos [get]
+// @Security ApiKeyAuth
+func (h *listHandler) handleTodos(c echo.Context) error {
+	listID := c.Param("list_id")
+
+	owner, _, err := h.role(c, listID)
+	if err != nil {
+		return err
+	}
+
+	opts, err := models.ParseListOptions(c.QueryParams())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	opts.ListID = listID
+	opts.Location = h.user(c).Location()
+	if err := query.Compile(opts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, next, err := storage.ListOwnerItems(h.storage, owner, opts)
+	if err != nil {
+		if err == storage.ErrInvalidCursor {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return httpError(err)
+	}
+
+	if err := todo.RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	httphandler.SetNextLink(c, next)
+	return c.JSON(http.StatusOK, items)
+}
+
+// @summary list members
+// @description list members of list, owner comes first
+// @tags list
+// @param list_id path string true "list ID"
+// @success 200 {array} models.Member
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id}/members [get]
+// @Security ApiKeyAuth
+func (h *listHandler) handleMembers(c echo.Context) error {
+	listID := c.Param("list_id")
+
+	owner, _, err := h.role(c, listID)
+	if err != nil {
+		return err
+	}
+
+	members, err := h.storage.ListService().Members(owner, listID)
+	if err != nil {
+		return httpError(err)
+	}
+
+	members = append([]models.Member{{ListID: listID, Owner: owner, Email: owner, Role: storage.RoleOwner}}, members...)
+	return c.JSON(http.StatusOK, members)
+}
+
+// @summary invite member
+// @description share list with the user of email, or change role of the member. only owner can invite
+// @tags list
+// @accept json
+// @produce json
+// @param list_id path string true "list ID"
+// @param member body models.Member true "member to invite"
+// @success 201 {object} models.Member
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id}/members [post]
+// @Security ApiKeyAuth
+func (h *listHandler) handleInvite(c echo.Context) error {
+	listID := c.Param("list_id")
+
+	if _, role, err := h.role(c, listID); err != nil {
+		return err
+	} else if role != storage.RoleOwner {
+		return forbidden()
+	}
+
+	var member models.Member
+	if err := c.Bind(&member); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	member.ListID = listID
+	member.Owner = h.user(c).Email
+	if member.Email == member.Owner {
+		return echo.NewHTTPError(http.StatusBadRequest, "owner can not be member")
+	}
+
+	if err := member.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.storage.ListService().SetMember(&member); err != nil {
+		return httpError(err)
+	}
+
+	return c.JSON(http.StatusCreated, &member)
+}
+
+// @summary delete member
+// @description stop sharing list with the member. owner can delete any member, member can leave the list
+// @tags list
+// @param list_id path string true "list ID"
+// @param email path string true "email of member"
+// @success 204 {string} string
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @failure 404 {object} todo.HTTPError
+// @router /lists/{list_id}/members/{email} [delete]
+// @Security ApiKeyAuth
+func (h *listHandler) handleDeleteMember(c echo.Context) error {
+	listID := c.Param("list_id")
+	email, err := url.PathUnescape(c.Param("email"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	owner, role, err := h.role(c, listID)
+	if err != nil {
+		return err
+	}
+
+	if role != storage.RoleOwner && email != h.user(c).Email {
+		return forbidden()
+	}
+
+	if err := h.storage.ListService().DeleteMember(owner, listID, email); err != nil {
+		return httpError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,4 +1,4 @@
-//Package oauth supports auth with google
+// Package oauth supports auth with google
 package oauth
 
 import (
@@ -0,0 +1,72 @@
+package todo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// forbidden returns error for the user who has no permission to the item
+func forbidden() error {
+	return echo.NewHTTPError(http.StatusForbidden, "permission denied")
+}
+
+// findItem returns owner of the item and the item, which is owned by the user or in lists shared with the user.
+// if write, the user should be allowed to change the item
+func (h *todoHandler) findItem(c echo.Context, itemID string, write bool) (string, *models.Item, error) {
+	owner, item, role, err := storage.FindItem(h.storage, h.user(c).Email, itemID)
+	if err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			return "", nil, echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrNotAuthenticated:
+			return "", nil, echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return "", nil, err
+	}
+
+	if write && !role.CanWrite() {
+		return "", nil, forbidden()
+	}
+
+	return owner, item, nil
+}
+
+// checkList returns owner of item's list, the user should be allowed to change items of the list.
+// returns bad request if the list not exists; item without list belongs to the user
+func (h *todoHandler) checkList(email string, item *models.Item) (string, error) {
+	if item.ListID == "" {
+		return email, nil
+	}
+
+	owner, role, err := storage.ListRole(h.storage, email, item.ListID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return "", echo.NewHTTPError(http.StatusBadRequest, "list not found: "+item.ListID)
+		}
+		return "", err
+	}
+
+	if !role.CanWrite() {
+		return "", forbidden()
+	}
+
+	return owner, nil
+}
+
+// checkMove returns error if item of owner can not be placed to the item's list.
+// items are stored by owner, so items are not moved between owners
+func (h *todoHandler) checkMove(email, owner string, item *models.Item) error {
+	listOwner, err := h.checkList(email, item)
+	if err != nil {
+		return err
+	}
+
+	if listOwner != owner {
+		return echo.NewHTTPError(http.StatusBadRequest, "item can not be moved to list of other owner")
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package todo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-utils/log"
+)
+
+// @summary activity feed of todo item
+// @description list changes of todo item, who created, edited, completed, reopened or moved the item and which fields changed.
+// @description ordered by time
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @success 200 {array} models.Activity
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/activity [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleActivity(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return err
+	}
+
+	activities, err := h.storage.ActivityService().List(owner, item.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, activities)
+}
+
+// record add activity of owner's item by the user.
+// the item is already changed, so failure is logged and not returned
+func (h *todoHandler) record(c echo.Context, owner string, item *models.Item, action string, fields []string) {
+	activity := &models.Activity{
+		ID:        uuid.New().String(),
+		ItemID:    item.ID,
+		Actor:     h.user(c).Email,
+		Action:    action,
+		Fields:    fields,
+		CreatedAt: time.Now().UTC().Round(0),
+	}
+
+	if err := h.storage.ActivityService().Add(owner, activity); err != nil {
+		log.Errorf("record activity failed: %s", err)
+	}
+}
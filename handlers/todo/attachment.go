@@ -0,0 +1,215 @@
+package todo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/config"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/storage/blob"
+	"github.com/whitekid/go-utils/log"
+)
+
+// sweepGrace is period which contents are kept after stored, so contents being attached are not swept
+const sweepGrace = time.Minute
+
+// SweepBlobs delete contents which are not referenced by attachments, such as contents of attachments deleted
+// with their items or users. if hashes are given, only the contents are checked
+func SweepBlobs(s storage.Interface, blobs blob.BlobStore, hashes ...string) (int, error) {
+	return blob.Sweep(blobs, s.AttachmentService().Referenced, sweepGrace, hashes...)
+}
+
+// @summary list attachments of todo item
+// @description list files attached to todo item, ordered by uploaded time
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @success 200 {array} models.Attachment
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/attachments [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleAttachments(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := h.storage.AttachmentService().List(owner, item.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, attachments)
+}
+
+// @summary attach file to todo item
+// @description upload file as multipart form. same content is stored once.
+// @description attachments are counted to quota of the item's owner
+// @tags todo
+// @accept mpfd
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param file formData file true "file to attach"
+// @success 201 {object} models.Attachment
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 413 {object} HTTPError
+// @router /{item_id}/attachments [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleUpload(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), true)
+	if err != nil {
+		return err
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+
+	maxSize := int64(config.AttachmentMaxSize())
+	if fh.Size > maxSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("attachment is larger than %d bytes", maxSize))
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer f.Close()
+
+	// head of content is read to detect content type, then whole content is streamed to blob store
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	head = head[:n]
+
+	contentType := fh.Header.Get(echo.HeaderContentType)
+	if contentType == "" || contentType == echo.MIMEOctetStream {
+		contentType = http.DetectContentType(head)
+	}
+
+	// content is stored before its attachment is written; it is not swept as not referenced in grace period,
+	// and contents failed to be attached are swept later
+	hash, size, err := h.blobs.Put(io.LimitReader(io.MultiReader(bytes.NewReader(head), f), maxSize+1))
+	if err != nil {
+		return err
+	}
+	if size > maxSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("attachment is larger than %d bytes", maxSize))
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New().String(),
+		ItemID:      item.ID,
+		Name:        filepath.Base(fh.Filename),
+		ContentType: contentType,
+		Size:        size,
+		Hash:        hash,
+		CreatedBy:   h.user(c).Email,
+		CreatedAt:   time.Now().UTC().Round(0),
+	}
+
+	if err := h.storage.AttachmentService().Create(owner, attachment, int64(config.AttachmentQuota())); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrQuotaExceeded:
+			return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, attachment)
+}
+
+// @summary download attachment of todo item
+// @description download content of attached file
+// @tags todo
+// @produce octet-stream
+// @param item_id path string true "todo item ID"
+// @param attachment_id path string true "attachment ID"
+// @success 200 {file} file
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/attachments/{attachment_id} [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleDownload(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := h.storage.AttachmentService().Get(owner, item.ID, c.Param("attachment_id"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	r, err := h.blobs.Open(attachment.Hash)
+	if err != nil {
+		if err == blob.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound, "content of attachment not found")
+		}
+		return err
+	}
+	defer r.Close()
+
+	// uploaded content is not rendered by browser as page of this site
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	c.Response().Header().Set(echo.HeaderContentLength, fmt.Sprint(attachment.Size))
+	return c.Stream(http.StatusOK, attachment.ContentType, r)
+}
+
+// @summary delete attachment of todo item
+// @description delete attachment, its content is removed if no other attachment has same content
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param attachment_id path string true "attachment ID"
+// @success 204
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/attachments/{attachment_id} [delete]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleDeleteAttachment(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), true)
+	if err != nil {
+		return err
+	}
+
+	attachments := h.storage.AttachmentService()
+	attachment, err := attachments.Get(owner, item.ID, c.Param("attachment_id"))
+	if err == nil {
+		err = attachments.Delete(owner, item.ID, attachment.ID)
+	}
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	if _, err := SweepBlobs(h.storage, h.blobs, attachment.Hash); err != nil {
+		log.Errorf("sweep failed: %s", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
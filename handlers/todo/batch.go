@@ -0,0 +1,183 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-utils/log"
+)
+
+// maxBatchOperations is maximum number of operations of a batch request
+const maxBatchOperations = 100
+
+// @summary batch create, update, delete todo items
+// @description apply operations and returns result of each operation in same order.
+// @description update and delete are applied only if item matches to revision if given.
+// @description if atomic, all operations are applied or nothing applied; operations not applied by other's failure results 424.
+// @description item with subtasks can not be deleted in batch
+// @tags todo
+// @accept json
+// @produce json
+// @param batch body models.BatchRequest true "operations"
+// @success 200 {object} models.BatchResponse
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @router /batch [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleBatch(c echo.Context) error {
+	var req models.BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if len(req.Operations) == 0 || len(req.Operations) > maxBatchOperations {
+		return echo.NewHTTPError(http.StatusBadRequest, "number of operations should be 1 to 100")
+	}
+
+	email := h.user(c).Email
+	results := make([]models.BatchResult, len(req.Operations))
+
+	ops := []storage.Operation{}
+	indexes := []int{}      // index of result of ops
+	changes := [][]string{} // changed fields of ops
+	for i := range req.Operations {
+		op, old, err := h.prepareOperation(email, &req.Operations[i])
+		if err != nil {
+			results[i] = batchError(err, &req.Operations[i])
+			continue
+		}
+
+		var fields []string
+		if old != nil {
+			fields = storage.ChangedFields(old, op.Item)
+		}
+
+		ops = append(ops, *op)
+		indexes = append(indexes, i)
+		changes = append(changes, fields)
+	}
+
+	var errs []error
+	if req.Atomic && len(ops) < len(req.Operations) {
+		// some operations already failed
+		errs = make([]error, len(ops))
+		for i := range errs {
+			errs[i] = storage.ErrAborted
+		}
+	} else {
+		var err error
+		if errs, err = h.storage.TodoService().Batch(email, ops, req.Atomic); err != nil {
+			return err
+		}
+	}
+
+	for j, err := range errs {
+		i := indexes[j]
+		if err != nil {
+			results[i] = batchError(err, &req.Operations[i])
+			continue
+		}
+
+		switch ops[j].Op {
+		case storage.OpCreate:
+			results[i] = models.BatchResult{Status: http.StatusCreated, Item: ops[j].Item}
+			h.record(c, email, ops[j].Item, storage.ActionCreated, nil)
+		case storage.OpUpdate:
+			results[i] = models.BatchResult{Status: http.StatusOK, Item: ops[j].Item}
+			if len(changes[j]) > 0 {
+				h.record(c, email, ops[j].Item, storage.ActionUpdated, changes[j])
+			}
+		case storage.OpDelete:
+			results[i] = models.BatchResult{Status: http.StatusNoContent}
+		}
+	}
+
+	return c.JSON(http.StatusOK, &models.BatchResponse{Results: results})
+}
+
+// prepareOperation returns storage operation of request operation and stored item to update,
+// server managed fields are set as create and update. only the item of the operation is looked up
+func (h *todoHandler) prepareOperation(email string, o *models.BatchOperation) (*storage.Operation, *models.Item, error) {
+	switch o.Op {
+	case storage.OpCreate, storage.OpUpdate:
+		if o.Item == nil {
+			return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "item required")
+		}
+		item := *o.Item
+
+		var old *models.Item
+		if o.Op == storage.OpCreate {
+			item.ID = uuid.New().String()
+		} else {
+			var err error
+			if old, err = h.storage.TodoService().Get(email, item.ID); err != nil {
+				return nil, nil, err
+			}
+
+			if !old.MatchRevision(o.Revision) {
+				return nil, nil, storage.ErrConflict
+			}
+		}
+
+		setMetadata(&item, old)
+		if err := validate(&item); err != nil {
+			return nil, nil, err
+		}
+
+		// batch is applied to user's own items
+		if err := h.checkMove(email, email, &item); err != nil {
+			return nil, nil, err
+		}
+
+		if err := h.checkParent(email, &item, old); err != nil {
+			return nil, nil, err
+		}
+
+		op := &storage.Operation{Op: o.Op, Item: &item}
+		if old != nil {
+			op.Revision = old.Revision
+		}
+		return op, old, nil
+
+	case storage.OpDelete:
+		if o.ID == "" {
+			return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "id required")
+		}
+
+		return &storage.Operation{Op: o.Op, ItemID: o.ID, Revision: o.Revision, Children: storage.ChildBlock}, nil, nil
+	}
+
+	return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid operation: "+o.Op)
+}
+
+// batchError returns result of failed operation
+func batchError(err error, o *models.BatchOperation) models.BatchResult {
+	status := http.StatusInternalServerError
+	switch err {
+	case storage.ErrNotFound:
+		status = http.StatusNotFound
+	case storage.ErrNotEmpty:
+		return models.BatchResult{Status: http.StatusConflict, Error: "item has subtasks"}
+	case storage.ErrConflict:
+		status = http.StatusConflict
+		if o.Revision != 0 {
+			status = http.StatusPreconditionFailed
+		}
+	case storage.ErrAborted:
+		status = http.StatusFailedDependency
+	case storage.ErrNotAuthenticated:
+		status = http.StatusForbidden
+	default:
+		if e, ok := err.(*echo.HTTPError); ok {
+			return models.BatchResult{Status: e.Code, Error: fmt.Sprint(e.Message)}
+		}
+		log.Errorf("batch operation failed: %+v", err)
+	}
+
+	return models.BatchResult{Status: status, Error: err.Error()}
+}
@@ -0,0 +1,198 @@
+package todo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// @summary list comments of todo item
+// @description list comments on todo item, ordered by created time
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @success 200 {array} models.Comment
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/comments [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleComments(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return err
+	}
+
+	comments, err := h.storage.CommentService().List(owner, item.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, comments)
+}
+
+// @summary comment on todo item
+// @description add comment on todo item, users who can see the item can comment
+// @tags todo
+// @accept json
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param comment body models.Comment true "comment, only body is used"
+// @success 201 {object} models.Comment
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/comments [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleAddComment(c echo.Context) error {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return err
+	}
+
+	var req models.Comment
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	now := time.Now().UTC().Round(0)
+	comment := &models.Comment{
+		ID:        uuid.New().String(),
+		ItemID:    item.ID,
+		Body:      req.Body,
+		Author:    h.user(c).Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := comment.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.storage.CommentService().Create(owner, comment); err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, comment)
+}
+
+// findComment returns owner of the item and the comment, if write the comment should be written by the user
+func (h *todoHandler) findComment(c echo.Context, write bool) (string, *models.Comment, error) {
+	owner, item, err := h.findItem(c, c.Param("item_id"), false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	comment, err := h.storage.CommentService().Get(owner, item.ID, c.Param("comment_id"))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return "", nil, echo.NewHTTPError(http.StatusNotFound)
+		}
+		return "", nil, err
+	}
+
+	if write && comment.Author != h.user(c).Email {
+		return "", nil, forbidden()
+	}
+
+	return owner, comment, nil
+}
+
+// @summary get comment of todo item
+// @description get comment on todo item
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param comment_id path string true "comment ID"
+// @success 200 {object} models.Comment
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/comments/{comment_id} [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleGetComment(c echo.Context) error {
+	_, comment, err := h.findComment(c, false)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, comment)
+}
+
+// @summary edit comment of todo item
+// @description edit body of comment, only the author can edit
+// @tags todo
+// @accept json
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param comment_id path string true "comment ID"
+// @param comment body models.Comment true "comment, only body is used"
+// @success 200 {object} models.Comment
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/comments/{comment_id} [put]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleUpdateComment(c echo.Context) error {
+	owner, comment, err := h.findComment(c, true)
+	if err != nil {
+		return err
+	}
+
+	var req models.Comment
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	comment.Body = req.Body
+	comment.UpdatedAt = time.Now().UTC().Round(0)
+	if err := comment.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.storage.CommentService().Update(owner, comment); err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusOK, comment)
+}
+
+// @summary delete comment of todo item
+// @description delete comment, the author or owner of the item can delete
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param comment_id path string true "comment ID"
+// @success 204
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/comments/{comment_id} [delete]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleDeleteComment(c echo.Context) error {
+	owner, comment, err := h.findComment(c, false)
+	if err != nil {
+		return err
+	}
+
+	if email := h.user(c).Email; comment.Author != email && owner != email {
+		return forbidden()
+	}
+
+	if err := h.storage.CommentService().Delete(owner, comment.ItemID, comment.ID); err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
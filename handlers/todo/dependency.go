@@ -0,0 +1,139 @@
+package todo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// @summary list dependencies of todo item
+// @description list items which block the todo item
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @success 200 {array} models.Dependency
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/dependencies [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleDependencies(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	owner, _, err := h.findItem(c, itemID, false)
+	if err != nil {
+		return err
+	}
+
+	deps, err := h.storage.DependencyService().List(owner)
+	if err != nil {
+		return err
+	}
+
+	result := []models.Dependency{}
+	for _, dep := range deps {
+		if dep.ItemID == itemID {
+			result = append(result, dep)
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// @summary add dependency of todo item
+// @description todo item is blocked by the item it depends on until the item is completed.
+// @description both items should have same owner; dependency which makes cycle is rejected
+// @tags todo
+// @accept json
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param dependency body models.Dependency true "item to depend on"
+// @success 201 {object} models.Dependency
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @router /{item_id}/dependencies [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleAddDependency(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	var dep models.Dependency
+	if err := c.Bind(&dep); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	dep.ItemID = itemID
+	if err := dep.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	owner, _, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	blockingOwner, _, _, err := storage.FindItem(h.storage, h.user(c).Email, dep.DependsOn)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusBadRequest, "item not found: "+dep.DependsOn)
+		}
+		return err
+	}
+
+	if blockingOwner != owner {
+		return echo.NewHTTPError(http.StatusBadRequest, "item can not depend on item of other owner")
+	}
+
+	if err := h.storage.DependencyService().Add(owner, &dep); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrCycle:
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, &dep)
+}
+
+// @summary delete dependency of todo item
+// @description delete dependency of todo item
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param dep_id path string true "ID of item which the item depends on"
+// @success 204 {string} string
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/dependencies/{dep_id} [delete]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleDeleteDependency(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	owner, _, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	if err := h.storage.DependencyService().Delete(owner, &models.Dependency{ItemID: itemID, DependsOn: c.Param("dep_id")}); err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
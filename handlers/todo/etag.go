@@ -0,0 +1,56 @@
+package todo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+)
+
+// headers for optimistic concurrency control
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+)
+
+// etag returns entity tag of item, it is quoted revision of item
+func etag(item *models.Item) string {
+	return strconv.Quote(strconv.Itoa(item.Revision))
+}
+
+// setETag set ETag header of item
+func setETag(c echo.Context, item *models.Item) {
+	c.Response().Header().Set(HeaderETag, etag(item))
+}
+
+// ifMatch returns revision given by If-Match header, 0 if header is not given or "*".
+// returns 412 error if header is not valid entity tag of item
+func ifMatch(c echo.Context) (int, error) {
+	value := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	s, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match: "+value)
+	}
+
+	revision, err := strconv.Atoi(s)
+	if err != nil || revision <= 0 {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "invalid If-Match: "+value)
+	}
+
+	return revision, nil
+}
+
+// conflict returns error for revision conflict;
+// 412 if client requested with If-Match, or 409 if item is changed by others while updating
+func conflict(c echo.Context) error {
+	if c.Request().Header.Get(HeaderIfMatch) != "" {
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "item is modified")
+	}
+	return echo.NewHTTPError(http.StatusConflict, "item is modified")
+}
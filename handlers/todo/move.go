@@ -0,0 +1,162 @@
+package todo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// @summary move todo item
+// @description change rank of item to place it right before or after the anchor item.
+// @description only the moved item is changed, unless there is no room between ranks of neighbours;
+// @description then ranks of all items are rebalanced
+// @tags todo
+// @accept json
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param move body models.MoveRequest true "anchor item, one of before or after"
+// @param If-Match header string false "ETag of item"
+// @success 200 {object} models.Item
+// @header 200 {string} ETag "revision of item"
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
+// @router /{item_id}/move [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleMove(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	var req models.MoveRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if (req.Before == "") == (req.After == "") {
+		return echo.NewHTTPError(http.StatusBadRequest, "one of before or after is required")
+	}
+
+	anchorID := req.Before + req.After
+	if anchorID == itemID {
+		return echo.NewHTTPError(http.StatusBadRequest, "can not move item to itself")
+	}
+
+	revision, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	owner, found, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	// items of other owner are visible only in the shared list
+	opts := &models.ListOptions{Sort: storage.SortRank}
+	if owner != h.user(c).Email {
+		opts.ListID = found.ListID
+	}
+
+	todos := h.storage.TodoService()
+	items, _, err := todos.List(owner, opts)
+	if err != nil {
+		return err
+	}
+
+	// items except moving item, in rank order
+	var item *models.Item
+	others := make([]models.Item, 0, len(items))
+	for i := range items {
+		if items[i].ID == itemID {
+			item = &items[i]
+			continue
+		}
+		others = append(others, items[i])
+	}
+
+	if item == nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	if !item.MatchRevision(revision) {
+		return conflict(c)
+	}
+	before := *item
+
+	pos := -1
+	for i := range others {
+		if others[i].ID == anchorID {
+			pos = i
+			break
+		}
+	}
+
+	if pos == -1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "anchor item not found: "+anchorID)
+	}
+
+	if req.After != "" {
+		pos++
+	}
+
+	var prev, next *models.Item
+	if pos > 0 {
+		prev = &others[pos-1]
+	}
+	if pos < len(others) {
+		next = &others[pos]
+	}
+
+	if rank, ok := storage.RankBetween(prev, next); ok {
+		old := item.Revision
+		item.Rank = rank
+		touch(item)
+		err = todos.Update(owner, item, old)
+	} else {
+		item, err = h.rebalance(owner, others, item, pos)
+	}
+
+	if err != nil {
+		switch err {
+		case storage.ErrNotFound, storage.ErrConflict:
+			return conflict(c)
+		case storage.ErrNotAuthenticated:
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	h.record(c, owner, item, storage.ActionMoved, storage.ChangedFields(&before, item))
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
+
+// rebalance insert item at pos of others and rebalance ranks of all items in one atomic batch.
+// returns ErrConflict if any item is changed by others
+func (h *todoHandler) rebalance(email string, others []models.Item, item *models.Item, pos int) (*models.Item, error) {
+	ordered := make([]models.Item, 0, len(others)+1)
+	ordered = append(ordered, others[:pos]...)
+	ordered = append(ordered, *item)
+	ordered = append(ordered, others[pos:]...)
+
+	ops := []storage.Operation{}
+	for _, i := range storage.Rebalance(ordered) {
+		revision := ordered[i].Revision
+		touch(&ordered[i])
+		ops = append(ops, storage.Operation{Op: storage.OpUpdate, Item: &ordered[i], Revision: revision})
+	}
+
+	if err := h.applyAtomic(email, ops); err != nil {
+		return nil, err
+	}
+
+	return &ordered[pos], nil
+}
@@ -0,0 +1,33 @@
+package todo
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/markdown"
+	"github.com/whitekid/go-todo/models"
+)
+
+// render formats of notes
+const (
+	RenderHTML = "html"
+)
+
+// RenderNotes set sanitized HTML of notes to items if render=html is requested, otherwise items are not changed
+func RenderNotes(c echo.Context, items []models.Item) error {
+	switch render := c.QueryParam("render"); render {
+	case "":
+		return nil
+	case RenderHTML:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown render: "+render)
+	}
+
+	for i := range items {
+		if items[i].Notes != "" {
+			items[i].NotesHTML = markdown.Render(items[i].Notes)
+		}
+	}
+
+	return nil
+}
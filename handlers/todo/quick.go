@@ -0,0 +1,59 @@
+package todo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/quickadd"
+)
+
+// @summary quick add todo item
+// @description create todo item from natural language text such as "Pay rent every month on the 1st #home !high tomorrow 9am".
+// @description title, due date, due time, tags, priority and recurrence are parsed in user's time zone;
+// @description interpreted words are returned as tokens. words in double quotes are kept in title.
+// @description if dry_run, item is not created and 200 is returned
+// @tags todo
+// @accept json
+// @produce json
+// @param quick body models.QuickAddRequest true "text of todo item"
+// @success 200 {object} models.QuickAddResponse
+// @success 201 {object} models.QuickAddResponse
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /quick [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleQuickAdd(c echo.Context) error {
+	var req models.QuickAddRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := quickadd.Parse(req.Text, time.Now().In(h.user(c).Location()))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	item := result.Item
+	item.ID = uuid.New().String()
+	item.ListID = req.ListID
+	resp := &models.QuickAddResponse{Item: &item, Tokens: result.Tokens}
+
+	if req.DryRun {
+		if err := validate(&item); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	if err := h.create(c, &item); err != nil {
+		return err
+	}
+
+	setETag(c, &item)
+	return c.JSON(http.StatusCreated, resp)
+}
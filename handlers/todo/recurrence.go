@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// validate normalize recurrence of item then validate item for save
+func validate(item *models.Item) error {
+	if err := item.NormalizeRecurrence(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := item.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
+// nextOccurrence returns operation to create next occurrence of completed recurring item, nil if there is no more occurrence.
+// recurrence is moved to the next occurrence, so the completed item does not recur again when reopened.
+// completion date is taken in the location of the user who completes the item
+func nextOccurrence(item *models.Item, loc *time.Location) *storage.Operation {
+	if item.Recurrence == nil || item.CompletedAt == nil {
+		return nil
+	}
+
+	recurrence := item.Recurrence
+	item.Recurrence = nil
+
+	completed := models.DateOf(item.CompletedAt.In(loc))
+	due, ok := recurrence.Next(item.DueDate, completed)
+	if !ok {
+		return nil
+	}
+
+	next := models.Item{
+		ID:         uuid.New().String(),
+		Title:      item.Title,
+		DueDate:    due,
+		DueTime:    item.DueTime,
+		Rank:       item.Rank,
+		ListID:     item.ListID,
+		Tags:       item.Tags,
+		ParentID:   item.ParentID,
+		Recurrence: recurrence,
+	}
+	setMetadata(&next, nil)
+
+	return &storage.Operation{Op: storage.OpCreate, Item: &next}
+}
@@ -0,0 +1,56 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// number of search results
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// @summary search todo items
+// @description full text search of todo items, items of lists shared with the user are included.
+// @description every word of query matches to words of item which begin with it, so "rep" matches "report".
+// @description results are ordered by relevance; whole words and words in title score higher
+// @tags todo
+// @param q query string true "words to search"
+// @param limit query int false "maximum number of results, default 20"
+// @success 200 {array} models.SearchResult
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @router /search [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleSearch(c echo.Context) error {
+	query := c.QueryParam("q")
+	if len(models.SearchTerms(query)) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "q is required")
+	}
+
+	limit := defaultSearchLimit
+	if s := c.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("limit should be 1 to %d: %s", maxSearchLimit, s))
+		}
+		limit = n
+	}
+
+	results, err := storage.SearchItems(h.storage, h.user(c).Email, query, limit)
+	if err != nil {
+		if err == storage.ErrNotAuthenticated {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,110 @@
+package todo
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// @summary list tags
+// @description list tags of user's items with number of items, ordered by name
+// @tags todo
+// @produce json
+// @success 200 {array} models.Tag
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @router /tags [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleTags(c echo.Context) error {
+	tags, err := h.storage.TodoService().Tags(h.user(c).Email)
+	if err != nil {
+		if err == storage.ErrNotAuthenticated {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
+
+// @summary rename tag
+// @description rename tag of all user's items. if the new name is already used, tags are merged
+// @tags todo
+// @accept json
+// @produce json
+// @param tag path string true "tag to rename"
+// @param tag body models.Tag true "new name of tag"
+// @success 200 {object} models.Tag
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @router /tags/{tag} [put]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleRenameTag(c echo.Context) error {
+	var req models.Tag
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Name = storage.NormalizeTag(req.Name)
+	if err := req.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	email := h.user(c).Email
+	tag, err := url.PathUnescape(c.Param("tag"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	from := storage.NormalizeTag(tag)
+	todos := h.storage.TodoService()
+
+	items, _, err := todos.List(email, &models.ListOptions{Tags: [][]string{{from}}})
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "tag not found: "+from)
+	}
+
+	if from != req.Name {
+		ops := make([]storage.Operation, 0, len(items))
+		for i := range items {
+			revision := items[i].Revision
+			items[i].RenameTag(from, req.Name)
+			touch(&items[i])
+			ops = append(ops, storage.Operation{Op: storage.OpUpdate, Item: &items[i], Revision: revision})
+		}
+
+		errs, err := todos.Batch(email, ops, true)
+		if err != nil {
+			return err
+		}
+
+		for _, err := range errs {
+			if err != nil && err != storage.ErrAborted {
+				// items are changed by others while renaming
+				return echo.NewHTTPError(http.StatusConflict, "items are modified, try again")
+			}
+		}
+	}
+
+	tags, err := todos.Tags(email)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if tag.Name == req.Name {
+			return c.JSON(http.StatusOK, &tag)
+		}
+	}
+
+	return c.JSON(http.StatusOK, &req)
+}
@@ -1,37 +1,71 @@
 package todo
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
 	"github.com/whitekid/go-todo/httphandler"
+	"github.com/whitekid/go-todo/markdown"
+	"github.com/whitekid/go-todo/mergepatch"
 	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/query"
 	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/storage/blob"
 	"github.com/whitekid/go-todo/tokens"
 	"github.com/whitekid/go-utils/log"
 )
 
 // New create todo handler
-func New(storage storage.Interface) httphandler.Interface {
+func New(storage storage.Interface, blobs blob.BlobStore) httphandler.Interface {
 	return &todoHandler{
 		storage: storage,
+		blobs:   blobs,
 	}
 }
 
 type todoHandler struct {
 	storage storage.Interface
+	blobs   blob.BlobStore // contents of attachments
 }
 
 func (h *todoHandler) Route(r httphandler.Router) {
 	r.Use(tokens.TokenMiddleware(h.storage, false))
 
 	r.POST("/", h.handleCreate)
+	r.POST("/batch", h.handleBatch)
+	r.POST("/quick", h.handleQuickAdd)
 	r.GET("/", h.handleList)
+	r.GET("/tags", h.handleTags)
+	r.PUT("/tags/:tag", h.handleRenameTag)
+	r.GET("/views/:view", h.handleView)
+	r.GET("/search", h.handleSearch)
 	r.GET("/:item_id", h.handleGet)
 	r.PUT("/:item_id", h.handleUpdate)
+	r.PATCH("/:item_id", h.handlePatch)
 	r.DELETE("/:item_id", h.handleDelete)
+	r.POST("/:item_id/complete", h.handleComplete)
+	r.POST("/:item_id/reopen", h.handleReopen)
+	r.POST("/:item_id/move", h.handleMove)
+	r.GET("/:item_id/children", h.handleChildren)
+	r.GET("/:item_id/dependencies", h.handleDependencies)
+	r.POST("/:item_id/dependencies", h.handleAddDependency)
+	r.DELETE("/:item_id/dependencies/:dep_id", h.handleDeleteDependency)
+	r.GET("/:item_id/attachments", h.handleAttachments)
+	r.POST("/:item_id/attachments", h.handleUpload)
+	r.GET("/:item_id/attachments/:attachment_id", h.handleDownload)
+	r.DELETE("/:item_id/attachments/:attachment_id", h.handleDeleteAttachment)
+	r.GET("/:item_id/comments", h.handleComments)
+	r.POST("/:item_id/comments", h.handleAddComment)
+	r.GET("/:item_id/comments/:comment_id", h.handleGetComment)
+	r.PUT("/:item_id/comments/:comment_id", h.handleUpdateComment)
+	r.DELETE("/:item_id/comments/:comment_id", h.handleDeleteComment)
+	r.GET("/:item_id/activity", h.handleActivity)
 }
 
 func (h *todoHandler) user(c echo.Context) *storage.User {
@@ -48,6 +82,7 @@ func (h *todoHandler) user(c echo.Context) *storage.User {
 // @failure 401 {object} HTTPError
 // @failure 400 {object} HTTPError
 // @failure 403 {object} HTTPError
+// @failure 409 {object} HTTPError
 // @router / [post]
 // @Security ApiKeyAuth
 func (h *todoHandler) handleCreate(c echo.Context) error {
@@ -59,31 +94,122 @@ func (h *todoHandler) handleCreate(c echo.Context) error {
 	}
 
 	item.ID = uuid.New().String()
-	if err := item.Validate(); err != nil {
+	if err := h.create(c, &item); err != nil {
+		return err
+	}
+
+	setETag(c, &item)
+	return c.JSON(http.StatusCreated, &item)
+}
+
+// create validate new item and save it to owner of its list
+func (h *todoHandler) create(c echo.Context, item *models.Item) error {
+	setMetadata(item, nil)
+	if err := validate(item); err != nil {
 		log.Errorf("validate failed: %s", err)
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return err
 	}
 
-	if err := h.storage.TodoService().Create(h.user(c).Email, &item); err != nil {
+	owner, err := h.checkList(h.user(c).Email, item)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checkParent(owner, item, nil); err != nil {
+		return err
+	}
+
+	if err := h.storage.TodoService().Create(owner, item); err != nil {
 		return errors.Wrapf(err, "todo create failed: %s", err)
 	}
+	h.record(c, owner, item, storage.ActionCreated, nil)
 
-	return c.JSON(http.StatusCreated, &item)
+	return nil
+}
+
+// setMetadata set server managed fields of item, client supplied values are ignored. tags are normalized.
+// old is the stored item, nil if item is newly created
+func setMetadata(item *models.Item, old *models.Item) {
+	if old == nil {
+		old = &models.Item{}
+	}
+
+	item.Tags = storage.NormalizeTags(item.Tags)
+	item.Links = markdown.Links(item.Notes)
+	item.NotesHTML = ""
+
+	item.CreatedAt = old.CreatedAt
+	item.Revision = old.Revision
+	item.Completed = old.Completed
+	item.CompletedAt = old.CompletedAt
+
+	touch(item)
+	if old.CreatedAt.IsZero() {
+		item.CreatedAt = item.UpdatedAt
+	}
+}
+
+// touch mark item as updated
+func touch(item *models.Item) {
+	item.UpdatedAt = time.Now().UTC().Round(0)
+	item.Revision++
 }
 
 // @summary list todo item
-// @description list todo item
+// @description list todo item, items of lists shared with the user are included
+// @description next page is returned in Link header with rel="next".
+// @description if tree is true, all matched items are returned as tree of models.TreeItem without pagination
 // @tags todo
+// @param limit query int false "maximum number of items"
+// @param cursor query string false "cursor of next page"
+// @param sort query string false "sort order, default rank" Enums(rank, due_date, title, created, updated, priority)
+// @param due_before query string false "items due before the date" format(date)
+// @param due_after query string false "items due after the date" format(date)
+// @param status query string false "status filter, default all" Enums(all, open, completed)
+// @param list_id query string false "items of the list" format(uuid)
+// @param tag query string false "items with tags, comma for AND, | for OR; e.g. work,urgent|home"
+// @param parent_id query string false "subtasks of the item" format(uuid)
+// @param tree query bool false "returns items as tree of subtasks"
+// @param actionable query bool false "only items whose dependencies are all completed"
+// @param due query string false "items due today or open items overdue, in user's time zone" Enums(today, overdue)
+// @param q query string false "query, e.g. tag:work and due<+3d and not done; see package query"
+// @param render query string false "render notes as notes_html" Enums(html)
 // @success 200 {array} models.Item
+// @failure 400 {object} HTTPError
 // @failure 401 {object} HTTPError
 // @failure 403 {object} HTTPError
 // @router / [get]
 // @Security ApiKeyAuth
 func (h *todoHandler) handleList(c echo.Context) error {
-	items, err := h.storage.TodoService().List(h.user(c).Email)
-	if err != nil && err == storage.ErrNotAuthenticated {
-		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	opts, err := models.ParseListOptions(c.QueryParams())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	opts.Location = h.user(c).Location()
+	if err := query.Compile(opts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if c.QueryParam("tree") == "true" {
+		return h.listTree(c, opts)
+	}
+
+	items, next, err := storage.ListItems(h.storage, h.user(c).Email, opts)
+	if err != nil {
+		switch err {
+		case storage.ErrInvalidCursor:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case storage.ErrNotAuthenticated:
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
 	}
+
+	if err := RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	httphandler.SetNextLink(c, next)
 	return c.JSON(http.StatusOK, items)
 }
 
@@ -91,7 +217,9 @@ func (h *todoHandler) handleList(c echo.Context) error {
 // @description get todo item
 // @tags todo
 // @param item_id path string true "todo item ID"
+// @param render query string false "render notes as notes_html" Enums(html)
 // @success 200 {object} models.Item
+// @header 200 {string} ETag "revision of item"
 // @failure 401 {object} HTTPError
 // @failure 403 {object} HTTPError
 // @failure 404 {object} HTTPError
@@ -103,29 +231,35 @@ func (h *todoHandler) handleGet(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound)
 	}
 
-	item, err := h.storage.TodoService().Get(h.user(c).Email, itemID)
+	_, item, err := h.findItem(c, itemID, false)
 	if err != nil {
-		switch err {
-		case storage.ErrNotFound:
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
-		case storage.ErrNotAuthenticated:
-			return echo.NewHTTPError(http.StatusForbidden, err.Error())
-		}
 		return err
 	}
-	return c.JSON(http.StatusOK, item)
+
+	items := []models.Item{*item}
+	if err := RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, &items[0])
 }
 
 // @summary update todo item
-// @description update todo item
+// @description update todo item, completion state is changed by complete and reopen.
+// @description if If-Match is given, item is updated only if it matches to ETag of stored item
 // @tags todo
 // @param item_id path string true "todo item ID"
 // @param item body models.Item true "todo item"
+// @param If-Match header string false "ETag of item"
 // @success 202 {object} models.Item
+// @header 202 {string} ETag "revision of item"
 // @failure 400 {object} HTTPError
 // @failure 401 {object} HTTPError
 // @failure 403 {object} HTTPError
 // @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
 // @router /{item_id} [put]
 // @Security ApiKeyAuth
 func (h *todoHandler) handleUpdate(c echo.Context) error {
@@ -144,14 +278,39 @@ func (h *todoHandler) handleUpdate(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "item ID must be same to given to path")
 	}
 
-	if err := item.Validate(); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	if err := validate(&item); err != nil {
+		return err
+	}
+
+	revision, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	owner, old, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
 	}
 
-	if err := h.storage.TodoService().Update(h.user(c).Email, &item); err != nil {
+	if err := h.checkMove(h.user(c).Email, owner, &item); err != nil {
+		return err
+	}
+
+	if err := h.checkParent(owner, &item, old); err != nil {
+		return err
+	}
+
+	if !old.MatchRevision(revision) {
+		return conflict(c)
+	}
+	setMetadata(&item, old)
+
+	if err := h.storage.TodoService().Update(owner, &item, old.Revision); err != nil {
 		switch err {
 		case storage.ErrNotFound:
 			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrConflict:
+			return conflict(c)
 		case storage.ErrNotAuthenticated:
 			return echo.NewHTTPError(http.StatusForbidden, err.Error())
 		}
@@ -159,17 +318,129 @@ func (h *todoHandler) handleUpdate(c echo.Context) error {
 		return err
 	}
 
+	if fields := storage.ChangedFields(old, &item); len(fields) > 0 {
+		h.record(c, owner, &item, storage.ActionUpdated, fields)
+	}
+
+	setETag(c, &item)
 	return c.JSON(http.StatusAccepted, &item)
 }
 
+// @summary patch todo item
+// @description update todo item with JSON merge patch, RFC 7396. server managed fields are ignored.
+// @description if If-Match is given, item is updated only if it matches to ETag of stored item
+// @tags todo
+// @accept application/merge-patch+json
+// @produce json
+// @param item_id path string true "todo item ID"
+// @param patch body models.Item true "merge patch of todo item"
+// @param If-Match header string false "ETag of item"
+// @success 200 {object} models.Item
+// @header 200 {string} ETag "revision of item"
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
+// @failure 415 {object} HTTPError
+// @router /{item_id} [patch]
+// @Security ApiKeyAuth
+func (h *todoHandler) handlePatch(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType)); mediaType != mergepatch.ContentType && mediaType != echo.MIMEApplicationJSON {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "content type should be "+mergepatch.ContentType)
+	}
+
+	patch, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	revision, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	owner, old, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	if !old.MatchRevision(revision) {
+		return conflict(c)
+	}
+
+	target, err := json.Marshal(old)
+	if err != nil {
+		return err
+	}
+
+	merged, err := mergepatch.Apply(target, patch)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	var item models.Item
+	if err := json.Unmarshal(merged, &item); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if item.ID != itemID {
+		return echo.NewHTTPError(http.StatusBadRequest, "item ID can not be changed")
+	}
+
+	if err := validate(&item); err != nil {
+		return err
+	}
+
+	if err := h.checkMove(h.user(c).Email, owner, &item); err != nil {
+		return err
+	}
+
+	if err := h.checkParent(owner, &item, old); err != nil {
+		return err
+	}
+	setMetadata(&item, old)
+
+	if err := h.storage.TodoService().Update(owner, &item, old.Revision); err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrConflict:
+			return conflict(c)
+		case storage.ErrNotAuthenticated:
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	if fields := storage.ChangedFields(old, &item); len(fields) > 0 {
+		h.record(c, owner, &item, storage.ActionUpdated, fields)
+	}
+
+	setETag(c, &item)
+	return c.JSON(http.StatusOK, &item)
+}
+
 // @summary delete todo item
-// @description delete todo item
+// @description delete todo item, if If-Match is given item is deleted only if it matches to ETag of stored item.
+// @description subtasks are deleted together on cascade, detached on orphan; item with subtasks is not deleted on block
 // @tags todo
 // @param item_id path string true "todo item ID"
+// @param children query string false "policy of subtasks, default is server configuration" Enums(cascade, block, orphan)
+// @param If-Match header string false "ETag of item"
 // @success 204 {string} string
+// @failure 400 {object} HTTPError
 // @failure 401 {object} HTTPError
 // @failure 403 {object} HTTPError
 // @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
 // @router /{item_id} [delete]
 // @Security ApiKeyAuth
 func (h *todoHandler) handleDelete(c echo.Context) error {
@@ -178,10 +449,30 @@ func (h *todoHandler) handleDelete(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound)
 	}
 
-	if err := h.storage.TodoService().Delete(h.user(c).Email, itemID); err != nil {
+	revision, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	owner, _, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	policy, err := childPolicy(c)
+	if err != nil {
+		return err
+	}
+
+	// subtasks are deleted or detached by storage as the policy
+	if err := h.applyAtomic(owner, []storage.Operation{{Op: storage.OpDelete, ItemID: itemID, Revision: revision, Children: policy}}); err != nil {
 		switch err {
 		case storage.ErrNotFound:
 			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrNotEmpty:
+			return echo.NewHTTPError(http.StatusConflict, "item has subtasks, see children policy")
+		case storage.ErrConflict:
+			return conflict(c)
 		case storage.ErrNotAuthenticated:
 			return echo.NewHTTPError(http.StatusForbidden, err.Error())
 		}
@@ -190,3 +481,116 @@ func (h *todoHandler) handleDelete(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// @summary complete todo item
+// @description mark todo item as completed, completed_at is kept if already completed.
+// @description if item has recurrence, next occurrence is created with the recurrence.
+// @description open subtasks are completed together on cascade, detached on orphan; item with open subtasks is not completed on block
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param children query string false "policy of subtasks, default is server configuration" Enums(cascade, block, orphan)
+// @param If-Match header string false "ETag of item"
+// @success 200 {object} models.Item
+// @header 200 {string} ETag "revision of item"
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
+// @router /{item_id}/complete [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleComplete(c echo.Context) error {
+	return h.updateState(c, storage.ActionCompleted, func(item *models.Item) { item.Complete(time.Now().UTC().Round(0)) })
+}
+
+// @summary reopen todo item
+// @description mark todo item as not completed
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param If-Match header string false "ETag of item"
+// @success 200 {object} models.Item
+// @header 200 {string} ETag "revision of item"
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @failure 409 {object} HTTPError
+// @failure 412 {object} HTTPError
+// @router /{item_id}/reopen [post]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleReopen(c echo.Context) error {
+	return h.updateState(c, storage.ActionReopened, func(item *models.Item) { item.Reopen() })
+}
+
+// updateState update item by fn and returns updated item, the change is recorded as action
+func (h *todoHandler) updateState(c echo.Context, action string, fn func(item *models.Item)) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	revision, err := ifMatch(c)
+	if err != nil {
+		return err
+	}
+
+	owner, item, err := h.findItem(c, itemID, true)
+	if err != nil {
+		return err
+	}
+
+	if !item.MatchRevision(revision) {
+		return conflict(c)
+	}
+
+	before := *item
+	completed, old := item.Completed, item.Revision
+	fn(item)
+
+	ops := []storage.Operation{}
+	if item.Completed != completed {
+		touch(item)
+
+		if item.Completed {
+			if ops, err = h.completeChildren(c, owner, item); err != nil {
+				return err
+			}
+
+			if op := nextOccurrence(item, h.user(c).Location()); op != nil {
+				ops = append(ops, *op)
+			}
+		}
+
+		if len(ops) == 0 {
+			err = h.storage.TodoService().Update(owner, item, old)
+		} else {
+			err = h.applyAtomic(owner, append(ops, storage.Operation{Op: storage.OpUpdate, Item: item, Revision: old}))
+		}
+	}
+
+	if err != nil {
+		switch err {
+		case storage.ErrNotFound:
+			return echo.NewHTTPError(http.StatusNotFound)
+		case storage.ErrConflict:
+			return conflict(c)
+		case storage.ErrNotAuthenticated:
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	if item.Completed != completed {
+		h.record(c, owner, item, action, storage.ChangedFields(&before, item))
+
+		// next occurrence of recurring item
+		for _, op := range ops {
+			if op.Op == storage.OpCreate {
+				h.record(c, owner, op.Item, storage.ActionCreated, nil)
+			}
+		}
+	}
+
+	setETag(c, item)
+	return c.JSON(http.StatusOK, item)
+}
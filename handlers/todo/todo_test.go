@@ -2,29 +2,155 @@ package todo
 
 import (
 	"fmt"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
-	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/config"
+	"github.com/whitekid/go-todo/mergepatch"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage/blob"
+	"github.com/whitekid/go-todo/storage/memory"
+	"github.com/whitekid/go-todo/tokens"
 	"github.com/whitekid/go-utils/request"
 )
 
 func TestList(t *testing.T) {
-	storage, err := storage.New("testdb")
+	storage, err := memory.New("")
 	require.NoError(t, err)
-	handler := New(storage)
+	defer storage.Close()
+	handler := New(storage, newBlobStore(t))
 	e := echo.New()
 	handler.Route(e)
 
 	ts := httptest.NewServer(e)
 	defer ts.Close()
 
-	token, err := handler.(*todoHandler).storage.TokenService().Create("whitekid@gmail.com")
+	email := "whitekid@gmail.com"
+	token, err := tokens.New(email, config.AccessTokenDuration())
 	require.NoError(t, err)
+	require.NoError(t, storage.TokenService().Create(email, token))
 
-	resp, err := request.Get(ts.URL).Header(echo.HeaderAuthorization, fmt.Sprintf("Bearer %s", token.Token)).Do()
+	resp, err := request.Get(ts.URL).Header(echo.HeaderAuthorization, fmt.Sprintf("Bearer %s", token)).Do()
 	require.NoError(t, err)
 	require.Truef(t, resp.Success(), "code: %d", resp.StatusCode)
 }
+
+func TestIfMatch(t *testing.T) {
+	storage, err := memory.New("")
+	require.NoError(t, err)
+	defer storage.Close()
+	handler := New(storage, newBlobStore(t))
+	e := echo.New()
+	handler.Route(e)
+
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	email := "whitekid@gmail.com"
+	token, err := tokens.New(email, config.AccessTokenDuration())
+	require.NoError(t, err)
+	require.NoError(t, storage.TokenService().Create(email, token))
+
+	item := &models.Item{ID: "c9be58c3-e164-42de-a301-8ad3fdbf553b", Title: "title", DueDate: models.Today(), Revision: 2}
+	require.NoError(t, storage.TodoService().Create(email, item))
+
+	resp, err := request.Get("%s/%s", ts.URL, item.ID).Header(echo.HeaderAuthorization, "Bearer "+token).Do()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"2"`, resp.Header.Get(HeaderETag))
+
+	tests := [...]struct {
+		name     string
+		ifMatch  string
+		wantCode int
+	}{
+		{"mismatch", `"1"`, http.StatusPreconditionFailed},
+		{"invalid", `W/"2"`, http.StatusPreconditionFailed},
+		{"match", `"2"`, http.StatusNoContent},
+		{"not found", `"2"`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := request.Delete("%s/%s", ts.URL, item.ID).
+				Header(echo.HeaderAuthorization, "Bearer "+token).
+				Header(HeaderIfMatch, tt.ifMatch).
+				Do()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	storage, err := memory.New("")
+	require.NoError(t, err)
+	defer storage.Close()
+	handler := New(storage, newBlobStore(t))
+	e := echo.New()
+	handler.Route(e)
+
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	email := "whitekid@gmail.com"
+	token, err := tokens.New(email, config.AccessTokenDuration())
+	require.NoError(t, err)
+	require.NoError(t, storage.TokenService().Create(email, token))
+
+	item := &models.Item{ID: "c9be58c3-e164-42de-a301-8ad3fdbf553b", Title: "title", DueDate: models.Today(), Revision: 2}
+	require.NoError(t, storage.TodoService().Create(email, item))
+
+	tests := [...]struct {
+		name        string
+		contentType string
+		ifMatch     string
+		patch       string
+		wantCode    int
+		wantETag    string
+	}{
+		{"unsupported", echo.MIMETextPlain, "", `{"title":"patched"}`, http.StatusUnsupportedMediaType, ""},
+		{"invalid patch", mergepatch.ContentType, "", `{"title":`, http.StatusBadRequest, ""},
+		{"invalid result", mergepatch.ContentType, "", `{"title":null}`, http.StatusBadRequest, ""},
+		{"mismatch", mergepatch.ContentType, `"1"`, `{"title":"patched"}`, http.StatusPreconditionFailed, ""},
+		{"patch", mergepatch.ContentType, `"2"`, `{"title":"patched"}`, http.StatusOK, `"3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, ts.URL+"/"+item.ID, strings.NewReader(tt.patch))
+			require.NoError(t, err)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			if tt.ifMatch != "" {
+				req.Header.Set(HeaderIfMatch, tt.ifMatch)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.wantCode, resp.StatusCode)
+			require.Equal(t, tt.wantETag, resp.Header.Get(HeaderETag))
+		})
+	}
+
+	got, err := storage.TodoService().Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, "patched", got.Title)
+}
+
+func newBlobStore(t *testing.T) blob.BlobStore {
+	blobs, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	return blobs
+}
+
+func TestBatchError(t *testing.T) {
+	got := batchError(echo.NewHTTPError(http.StatusBadRequest, []string{"title required"}), &models.BatchOperation{})
+	require.Equal(t, models.BatchResult{Status: http.StatusBadRequest, Error: "[title required]"}, got)
+
+	got = batchError(echo.NewHTTPError(http.StatusNotFound), &models.BatchOperation{})
+	require.Equal(t, models.BatchResult{Status: http.StatusNotFound, Error: http.StatusText(http.StatusNotFound)}, got)
+}
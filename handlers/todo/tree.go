@@ -0,0 +1,215 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/config"
+	"github.com/whitekid/go-todo/httphandler"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/query"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// @summary list subtasks of todo item
+// @description list direct subtasks of todo item
+// @description next page is returned in Link header with rel="next"
+// @tags todo
+// @param item_id path string true "todo item ID"
+// @param limit query int false "maximum number of items"
+// @param cursor query string false "cursor of next page"
+// @param sort query string false "sort order, default rank" Enums(rank, due_date, title, created, updated, priority)
+// @param status query string false "status filter, default all" Enums(all, open, completed)
+// @param actionable query bool false "only items whose dependencies are all completed"
+// @param due query string false "items due today or open items overdue, in user's time zone" Enums(today, overdue)
+// @param q query string false "query, e.g. tag:work and due<+3d and not done; see package query"
+// @param render query string false "render notes as notes_html" Enums(html)
+// @success 200 {array} models.Item
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /{item_id}/children [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleChildren(c echo.Context) error {
+	itemID := c.Param("item_id")
+	if itemID == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	opts, err := models.ParseListOptions(c.QueryParams())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	opts.Location = h.user(c).Location()
+
+	owner, _, err := h.findItem(c, itemID, false)
+	if err != nil {
+		return err
+	}
+
+	opts.ParentID = itemID
+	if err := query.Compile(opts); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, next, err := storage.ListOwnerItems(h.storage, owner, opts)
+	if err != nil {
+		switch err {
+		case storage.ErrInvalidCursor:
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case storage.ErrNotAuthenticated:
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	if err := RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	httphandler.SetNextLink(c, next)
+	return c.JSON(http.StatusOK, items)
+}
+
+// listTree returns all items matched to options as tree, pagination is not applied
+func (h *todoHandler) listTree(c echo.Context, opts *models.ListOptions) error {
+	opts.Limit, opts.Cursor = 0, ""
+
+	items, _, err := storage.ListItems(h.storage, h.user(c).Email, opts)
+	if err != nil {
+		if err == storage.ErrNotAuthenticated {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	if err := RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, storage.BuildTree(items))
+}
+
+// checkParent returns error if item of owner can not be subtask of its parent, old is stored item on update.
+// only parent chain and subtasks of the item are looked up
+func (h *todoHandler) checkParent(owner string, item, old *models.Item) error {
+	tree := storage.NewItemTree(h.storage.TodoService(), owner)
+
+	if old != nil && old.ListID != item.ListID {
+		children, err := tree.Children(item.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(children) > 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "item with subtasks can not be moved to other list")
+		}
+	}
+
+	if item.ParentID == "" {
+		return nil
+	}
+
+	parent, err := tree.Get(item.ParentID)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusBadRequest, "parent not found: "+item.ParentID)
+		}
+		return err
+	}
+
+	if parent.ListID != item.ListID {
+		return echo.NewHTTPError(http.StatusBadRequest, "parent should be in same list")
+	}
+
+	switch err := storage.CheckParent(tree, item.ID, item.ParentID); err {
+	case nil:
+		return nil
+	case storage.ErrCycle:
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case storage.ErrTooDeep:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("subtasks can not be deeper than %d", storage.MaxDepth))
+	default:
+		return err
+	}
+}
+
+// childPolicy returns policy of subtasks from children query parameter, default is configured policy
+func childPolicy(c echo.Context) (string, error) {
+	policy := c.QueryParam("children")
+	if policy == "" {
+		policy = config.ChildPolicy()
+	}
+
+	if !storage.ValidChildPolicy(policy) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "invalid children policy: "+policy)
+	}
+
+	return policy, nil
+}
+
+// completeChildren returns operations of subtasks when item is completed, by policy of children query parameter.
+// on block, item with open subtasks is not completed; on cascade, open descendants are completed together
+func (h *todoHandler) completeChildren(c echo.Context, owner string, item *models.Item) ([]storage.Operation, error) {
+	policy, err := childPolicy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	descendants, err := storage.Descendants(storage.NewItemTree(h.storage.TodoService(), owner), item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []storage.Operation{}
+	for i := range descendants {
+		child := &descendants[i]
+
+		switch policy {
+		case storage.ChildBlock:
+			if !child.Completed {
+				return nil, echo.NewHTTPError(http.StatusConflict, "item has subtasks, see children policy")
+			}
+
+		case storage.ChildCascade:
+			if child.Completed {
+				continue
+			}
+
+			revision := child.Revision
+			child.Complete(*item.CompletedAt)
+			touch(child)
+			ops = append(ops, storage.Operation{Op: storage.OpUpdate, Item: child, Revision: revision})
+
+		case storage.ChildOrphan:
+			if child.ParentID != item.ID {
+				continue
+			}
+
+			revision := child.Revision
+			child.ParentID = ""
+			touch(child)
+			ops = append(ops, storage.Operation{Op: storage.OpUpdate, Item: child, Revision: revision})
+		}
+	}
+
+	return ops, nil
+}
+
+// applyAtomic apply operations of owner in one atomic batch, returns first error of operations
+func (h *todoHandler) applyAtomic(owner string, ops []storage.Operation) error {
+	errs, err := h.storage.TodoService().Batch(owner, ops, true)
+	if err != nil {
+		return err
+	}
+
+	for _, err := range errs {
+		if err != nil && err != storage.ErrAborted {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+)
+
+// days of upcoming view
+const (
+	defaultUpcomingDays = 7
+	maxUpcomingDays     = 365
+)
+
+// @summary view of todo items
+// @description open items of the view in user's time zone, items of lists shared with the user are included.
+// @description today: due today or before, upcoming: due in next days, overdue: due time is passed.
+// @description items are ordered by due date, priority from highest, due time and rank; pagination is not applied
+// @tags todo
+// @param view path string true "view" Enums(today, upcoming, overdue)
+// @param days query int false "days of upcoming view, default 7"
+// @param render query string false "render notes as notes_html" Enums(html)
+// @success 200 {array} models.Item
+// @failure 400 {object} HTTPError
+// @failure 401 {object} HTTPError
+// @failure 403 {object} HTTPError
+// @failure 404 {object} HTTPError
+// @router /views/{view} [get]
+// @Security ApiKeyAuth
+func (h *todoHandler) handleView(c echo.Context) error {
+	user := h.user(c)
+	today := models.TodayIn(user.Location())
+	opts := &models.ListOptions{Status: storage.StatusOpen, Location: user.Location()}
+
+	switch c.Param("view") {
+	case models.ViewToday:
+		tomorrow := today.AddDays(1)
+		opts.DueBefore = &tomorrow
+
+	case models.ViewUpcoming:
+		days := defaultUpcomingDays
+		if s := c.QueryParam("days"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > maxUpcomingDays {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("days should be 1 to %d: %s", maxUpcomingDays, s))
+			}
+			days = n
+		}
+
+		until := today.AddDays(days + 1)
+		opts.DueAfter, opts.DueBefore = &today, &until
+
+	case models.ViewOverdue:
+		opts.Due = storage.DueOverdue
+
+	default:
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	items, _, err := storage.ListItems(h.storage, user.Email, opts)
+	if err != nil {
+		if err == storage.ErrNotAuthenticated {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+		return err
+	}
+
+	if err := RenderNotes(c, items); err != nil {
+		return err
+	}
+
+	storage.SortByDue(items)
+	return c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,83 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/httphandler"
+	"github.com/whitekid/go-todo/models"
+	"github.com/whitekid/go-todo/storage"
+	"github.com/whitekid/go-todo/tokens"
+	"github.com/whitekid/go-utils/log"
+)
+
+// New create user handler
+func New(storage storage.Interface) httphandler.Interface {
+	return &userHandler{
+		storage: storage,
+	}
+}
+
+type userHandler struct {
+	storage storage.Interface
+}
+
+func (h *userHandler) Route(r httphandler.Router) {
+	r.Use(tokens.TokenMiddleware(h.storage, false))
+
+	r.GET("/me", h.handleGet)
+	r.PUT("/me", h.handleUpdate)
+}
+
+func (h *userHandler) user(c echo.Context) *storage.User {
+	return c.Get("user").(*storage.User)
+}
+
+// @summary get current user
+// @description get current user with settings
+// @tags user
+// @success 200 {object} models.User
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /users/me [get]
+// @Security ApiKeyAuth
+func (h *userHandler) handleGet(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.user(c))
+}
+
+// @summary update current user
+// @description update settings of current user; email can not be changed
+// @description time_zone is IANA time zone name, today and overdue filters are computed in the zone
+// @tags user
+// @accept json
+// @produce json
+// @param user body models.User true "user"
+// @success 200 {object} models.User
+// @failure 400 {object} todo.HTTPError
+// @failure 401 {object} todo.HTTPError
+// @failure 403 {object} todo.HTTPError
+// @router /users/me [put]
+// @Security ApiKeyAuth
+func (h *userHandler) handleUpdate(c echo.Context) error {
+	var user models.User
+
+	if err := c.Bind(&user); err != nil {
+		log.Errorf("bind failed: %s", err)
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	user.Email = h.user(c).Email
+	if err := user.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.storage.UserService().Update(&user); err != nil {
+		if err == storage.ErrNotFound {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return errors.Wrapf(err, "user update failed: %s", err)
+	}
+
+	return c.JSON(http.StatusOK, &user)
+}
@@ -0,0 +1,24 @@
+package httphandler
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderLink is link header for pagination
+const HeaderLink = "Link"
+
+// SetNextLink set link header of the next page if cursor is not empty; query parameters except cursor are preserved
+func SetNextLink(c echo.Context, cursor string) {
+	if cursor == "" {
+		return
+	}
+
+	u := *c.Request().URL
+	query := u.Query()
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+
+	c.Response().Header().Set(HeaderLink, fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+}
@@ -0,0 +1,384 @@
+// Package markdown renders notes of todo items, a subset of Markdown, into sanitized HTML.
+//
+//	blocks: paragraphs, # headings, - or 1. list items, > quotes, ``` code blocks and --- rules
+//	inlines: **strong**, *emphasis*, `code`, [text](url), <url> and bare http(s) URLs
+//
+// Raw HTML is never passed through, every text is escaped; links are allowed only for http, https and mailto URLs.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+// Render returns sanitized HTML of Markdown text
+func Render(src string) string {
+	r := newRenderer()
+	r.blocks(lines(src))
+	return r.out.String()
+}
+
+// Links returns links in Markdown text in order, duplicated URLs are removed.
+// links in code are not included
+func Links(src string) []types.Link {
+	r := newRenderer()
+	r.blocks(lines(src))
+	return r.links
+}
+
+type renderer struct {
+	out   strings.Builder
+	links []types.Link
+	seen  map[string]bool
+}
+
+func newRenderer() *renderer {
+	return &renderer{seen: map[string]bool{}}
+}
+
+func lines(src string) []string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	return strings.Split(src, "\n")
+}
+
+var (
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	fenceRe   = regexp.MustCompile("^ {0,3}(```|~~~)\\s*([\\w+-]*)")
+	quoteRe   = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	bulletRe  = regexp.MustCompile(`^ {0,3}[-*+]\s+(.*)$`)
+	orderedRe = regexp.MustCompile(`^ {0,3}\d{1,9}[.)]\s+(.*)$`)
+)
+
+// blank returns true if line has only spaces
+func blank(line string) bool { return strings.TrimSpace(line) == "" }
+
+// rule returns true if line is thematic break, three or more of same -, * or _
+func rule(line string) bool {
+	s := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(s) < 3 || strings.Trim(s, s[:1]) != "" {
+		return false
+	}
+	return s[0] == '-' || s[0] == '*' || s[0] == '_'
+}
+
+// startsBlock returns true if line begins block other than paragraph
+func startsBlock(line string) bool {
+	return headingRe.MatchString(line) || fenceRe.MatchString(line) || quoteRe.MatchString(line) ||
+		rule(line) || bulletRe.MatchString(line) || orderedRe.MatchString(line)
+}
+
+func (r *renderer) blocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case blank(line):
+			i++
+
+		case fenceRe.MatchString(line):
+			i = r.code(lines, i)
+
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			tag := "h" + string(rune('0'+len(m[1])))
+			r.out.WriteString("<" + tag + ">")
+			r.inline(m[2], true)
+			r.out.WriteString("</" + tag + ">\n")
+			i++
+
+		case rule(line):
+			r.out.WriteString("<hr>\n")
+			i++
+
+		case quoteRe.MatchString(line):
+			quoted := []string{}
+			for ; i < len(lines) && quoteRe.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteRe.FindStringSubmatch(lines[i])[1])
+			}
+			r.out.WriteString("<blockquote>\n")
+			r.blocks(quoted)
+			r.out.WriteString("</blockquote>\n")
+
+		case bulletRe.MatchString(line):
+			i = r.list(lines, i, bulletRe, "ul")
+
+		case orderedRe.MatchString(line):
+			i = r.list(lines, i, orderedRe, "ol")
+
+		default:
+			para := []string{}
+			for ; i < len(lines) && !blank(lines[i]) && (len(para) == 0 || !startsBlock(lines[i])); i++ {
+				para = append(para, strings.TrimSpace(lines[i]))
+			}
+			r.out.WriteString("<p>")
+			r.inline(strings.Join(para, "\n"), true)
+			r.out.WriteString("</p>\n")
+		}
+	}
+}
+
+// code render fenced code block from lines[i], returns index of next line
+func (r *renderer) code(lines []string, i int) int {
+	m := fenceRe.FindStringSubmatch(lines[i])
+	fence := m[1]
+
+	if m[2] != "" {
+		r.out.WriteString(`<pre><code class="language-` + html.EscapeString(m[2]) + `">`)
+	} else {
+		r.out.WriteString("<pre><code>")
+	}
+
+	for i++; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		r.out.WriteString(html.EscapeString(lines[i]) + "\n")
+	}
+
+	r.out.WriteString("</code></pre>\n")
+	return i
+}
+
+// list render list items from lines[i], returns index of next line.
+// indented lines are continuation of the item; nested lists are not supported
+func (r *renderer) list(lines []string, i int, item *regexp.Regexp, tag string) int {
+	r.out.WriteString("<" + tag + ">\n")
+
+	for i < len(lines) && item.MatchString(lines[i]) {
+		text := []string{item.FindStringSubmatch(lines[i])[1]}
+		for i++; i < len(lines) && !blank(lines[i]) && strings.HasPrefix(lines[i], "  ") && !item.MatchString(lines[i]); i++ {
+			text = append(text, strings.TrimSpace(lines[i]))
+		}
+
+		r.out.WriteString("<li>")
+		r.inline(strings.Join(text, "\n"), true)
+		r.out.WriteString("</li>\n")
+	}
+
+	r.out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+var bareURLRe = regexp.MustCompile(`^https?://[^\s<>]+`)
+
+// inline render inline elements of text. links are not rendered in link text
+func (r *renderer) inline(text string, link bool) {
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()<>#+-.!|~", text[i+1]) >= 0:
+			r.out.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if n := r.codeSpan(text[i:]); n > 0 {
+				i += n
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if n := r.emphasis(text, i, link); n > 0 {
+				i += n
+				continue
+			}
+
+		case c == '[' && link:
+			if n := r.link(text[i:]); n > 0 {
+				i += n
+				continue
+			}
+
+		case c == '<' && link:
+			if end := strings.IndexByte(text[i:], '>'); end > 0 {
+				if u := text[i+1 : i+end]; !strings.ContainsAny(u, " \n") && r.anchor(u, "") {
+					i += end + 1
+					continue
+				}
+			}
+
+		case (c == 'h' || c == 'H') && link && (i == 0 || !isWordByte(text[i-1])):
+			if u := trimURL(bareURLRe.FindString(text[i:])); u != "" && r.anchor(u, "") {
+				i += len(u)
+				continue
+			}
+
+		case c == '\n':
+			r.out.WriteString("<br>\n")
+			i++
+			continue
+		}
+
+		r.out.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+}
+
+// codeSpan render code span at beginning of text, returns length of the span or 0 if not closed
+func (r *renderer) codeSpan(text string) int {
+	n := len(text) - len(strings.TrimLeft(text, "`"))
+	fence := text[:n]
+
+	end := strings.Index(text[n:], fence)
+	if end < 0 {
+		return 0
+	}
+
+	r.out.WriteString("<code>" + html.EscapeString(strings.TrimSpace(text[n:n+end])) + "</code>")
+	return n + end + n
+}
+
+// emphasis render emphasis at text[i], returns its length or 0 if it is not emphasis.
+// _ is emphasis only at word boundary, so snake_case is kept
+func (r *renderer) emphasis(text string, i int, link bool) int {
+	delim := text[i : i+1]
+	if strings.HasPrefix(text[i:], delim+delim) {
+		delim += delim
+	}
+
+	if delim[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+		return 0
+	}
+
+	start := i + len(delim)
+	if start >= len(text) || text[start] == ' ' || text[start] == '\n' {
+		return 0
+	}
+
+	for j := start + 1; j+len(delim) <= len(text); j++ {
+		if text[j:j+len(delim)] != delim || text[j-1] == ' ' || text[j-1] == '\\' {
+			continue
+		}
+		if len(delim) == 1 && (text[j-1] == delim[0] || j+1 < len(text) && text[j+1] == delim[0]) {
+			continue
+		}
+		if delim[0] == '_' && j+len(delim) < len(text) && isWordByte(text[j+len(delim)]) {
+			continue
+		}
+
+		tag := "em"
+		if len(delim) == 2 {
+			tag = "strong"
+		}
+		r.out.WriteString("<" + tag + ">")
+		r.inline(text[start:j], link)
+		r.out.WriteString("</" + tag + ">")
+		return j + len(delim) - i
+	}
+
+	return 0
+}
+
+// link render [text](url) at beginning of text, returns its length or 0 if it is not link.
+// link with unsafe URL is rendered as its text
+func (r *renderer) link(text string) int {
+	depth := 0
+	closing := -1
+	for j := 0; j < len(text) && closing < 0; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closing = j
+			}
+		}
+	}
+
+	if closing < 0 || closing+1 >= len(text) || text[closing+1] != '(' {
+		return 0
+	}
+
+	end := strings.IndexByte(text[closing+1:], ')')
+	if end < 0 {
+		return 0
+	}
+	end += closing + 1
+
+	label := text[1:closing]
+	dest := strings.TrimSpace(text[closing+2 : end])
+	if k := strings.IndexAny(dest, " \n"); k >= 0 { // ignore title
+		dest = dest[:k]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+
+	if !r.anchor(dest, label) {
+		r.inline(label, false)
+	}
+
+	return end + 1
+}
+
+// anchor render link to u with label if u is safe, returns false if not rendered.
+// empty label is rendered as the URL
+func (r *renderer) anchor(u, label string) bool {
+	href, ok := safeURL(u)
+	if !ok {
+		return false
+	}
+
+	r.out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">`)
+	if label == "" {
+		r.out.WriteString(html.EscapeString(u))
+	} else {
+		r.inline(label, false)
+	}
+	r.out.WriteString("</a>")
+
+	if !r.seen[href] {
+		r.seen[href] = true
+		r.links = append(r.links, types.Link{URL: href, Text: strings.TrimSpace(label)})
+	}
+
+	return true
+}
+
+// safeURL returns normalized URL if it is absolute http, https or mailto URL
+func safeURL(s string) (string, bool) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", false
+		}
+	case "mailto":
+		if u.Opaque == "" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	return u.String(), true
+}
+
+// trimURL remove trailing punctuations of bare URL, which are likely to be of sentence
+func trimURL(u string) string {
+	for u != "" {
+		last := u[len(u)-1]
+		if strings.IndexByte(".,;:!?'\"*_", last) >= 0 || last == ')' && strings.Count(u, "(") < strings.Count(u, ")") {
+			u = u[:len(u)-1]
+			continue
+		}
+		break
+	}
+	return u
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
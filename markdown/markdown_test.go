@@ -0,0 +1,65 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+func TestRender(t *testing.T) {
+	tests := [...]struct {
+		name string
+		src  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"paragraph", "hello\nworld\n\nbye", "<p>hello<br>\nworld</p>\n<p>bye</p>\n"},
+		{"heading", "## plan ##", "<h2>plan</h2>\n"},
+		{"hashtag is not heading", "#work", "<p>#work</p>\n"},
+		{"emphasis", "**bold** and *em* and _em_", "<p><strong>bold</strong> and <em>em</em> and <em>em</em></p>\n"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>\n"},
+		{"snake case", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"unclosed", "2 * 3 = 6", "<p>2 * 3 = 6</p>\n"},
+		{"code", "run `a < b` now", "<p>run <code>a &lt; b</code> now</p>\n"},
+		{"code block", "```go\nif a < b {}\n```", "<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>\n"},
+		{"list", "- milk\n- eggs\n  fresh\n\n1. one\n2. two", "<ul>\n<li>milk</li>\n<li>eggs<br>\nfresh</li>\n</ul>\n<ol>\n<li>one</li>\n<li>two</li>\n</ol>\n"},
+		{"quote", "> said\n> twice", "<blockquote>\n<p>said<br>\ntwice</p>\n</blockquote>\n"},
+		{"rule", "a\n\n---", "<p>a</p>\n<hr>\n"},
+		{"link", "see [the *docs*](https://example.com/docs \"title\")", `<p>see <a href="https://example.com/docs" rel="nofollow noopener noreferrer">the <em>docs</em></a></p>` + "\n"},
+		{"autolink", "<https://example.com>", `<p><a href="https://example.com" rel="nofollow noopener noreferrer">https://example.com</a></p>` + "\n"},
+		{"bare url", "at https://example.com/a?b=1&c=2.", `<p>at <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer">https://example.com/a?b=1&amp;c=2</a>.</p>` + "\n"},
+		{"escaped", `\*not em\*`, "<p>*not em*</p>\n"},
+		{"raw html", `<script>alert("x")</script><b onclick="x">b</b>`, "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;&lt;b onclick=&#34;x&#34;&gt;b&lt;/b&gt;</p>\n"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click)</p>\n"},
+		{"relative link", "[home](/home)", "<p>home</p>\n"},
+		{"attribute injection", `[x](https://example.com/"onmouseover="alert(1))`, `<p><a href="https://example.com/%22onmouseover=%22alert%281" rel="nofollow noopener noreferrer">x</a>)</p>` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Render(tt.src))
+		})
+	}
+}
+
+func TestLinks(t *testing.T) {
+	tests := [...]struct {
+		name string
+		src  string
+		want []types.Link
+	}{
+		{"none", "no links", nil},
+		{"links", "see [docs](https://example.com/docs), <mailto:me@example.com> and https://example.com/b",
+			[]types.Link{{URL: "https://example.com/docs", Text: "docs"}, {URL: "mailto:me@example.com"}, {URL: "https://example.com/b"}}},
+		{"duplicated", "[a](https://example.com) https://example.com", []types.Link{{URL: "https://example.com", Text: "a"}}},
+		{"in heading and list", "# [spec](https://example.com/spec)\n- https://example.com/list",
+			[]types.Link{{URL: "https://example.com/spec", Text: "spec"}, {URL: "https://example.com/list"}}},
+		{"code is skipped", "`https://example.com/a`\n```\nhttps://example.com/b\n```", nil},
+		{"unsafe is skipped", "[x](javascript:alert(1)) [y](ftp://example.com)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Links(tt.src))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package mergepatch implements JSON Merge Patch, RFC 7396
+package mergepatch
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// ContentType is media type of merge patch document
+const ContentType = "application/merge-patch+json"
+
+// Apply apply merge patch to target json document and returns patched document
+func Apply(target, patch []byte) ([]byte, error) {
+	var t interface{}
+	if len(target) > 0 {
+		if err := json.Unmarshal(target, &t); err != nil {
+			return nil, errors.Wrap(err, "invalid target")
+		}
+	}
+
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, errors.Wrap(err, "invalid patch")
+	}
+
+	return json.Marshal(merge(t, p))
+}
+
+// merge is MergePatch(Target, Patch) function of RFC 7396 section 2
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+
+		t[name] = merge(t[name], value)
+	}
+
+	return t
+}
@@ -0,0 +1,51 @@
+package mergepatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// test cases from RFC 7396 Appendix A
+func TestApply(t *testing.T) {
+	type args struct {
+		target string
+		patch  string
+	}
+	tests := [...]struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{"replace", args{`{"a":"b"}`, `{"a":"c"}`}, `{"a":"c"}`, false},
+		{"add", args{`{"a":"b"}`, `{"b":"c"}`}, `{"a":"b","b":"c"}`, false},
+		{"remove", args{`{"a":"b"}`, `{"a":null}`}, `{}`, false},
+		{"remove one", args{`{"a":"b","b":"c"}`, `{"a":null}`}, `{"b":"c"}`, false},
+		{"array to string", args{`{"a":["b"]}`, `{"a":"c"}`}, `{"a":"c"}`, false},
+		{"string to array", args{`{"a":"c"}`, `{"a":["b"]}`}, `{"a":["b"]}`, false},
+		{"nested", args{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`}, `{"a":{"b":"d"}}`, false},
+		{"array of objects", args{`{"a":[{"b":"c"}]}`, `{"a":[1]}`}, `{"a":[1]}`, false},
+		{"array", args{`["a","b"]`, `["c","d"]`}, `["c","d"]`, false},
+		{"object to array", args{`{"a":"b"}`, `["c"]`}, `["c"]`, false},
+		{"null patch", args{`{"a":"foo"}`, `null`}, `null`, false},
+		{"string patch", args{`{"a":"foo"}`, `"bar"`}, `"bar"`, false},
+		{"keep null in patch", args{`{"e":null}`, `{"a":1}`}, `{"a":1,"e":null}`, false},
+		{"array target", args{`[1,2]`, `{"a":"b","c":null}`}, `{"a":"b"}`, false},
+		{"deep null", args{`{}`, `{"a":{"bb":{"ccc":null}}}`}, `{"a":{"bb":{}}}`, false},
+		{"invalid patch", args{`{}`, `{`}, ``, true},
+		{"invalid target", args{`{`, `{}`}, ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.args.target), []byte(tt.args.patch))
+			if (err != nil) != tt.wantErr {
+				require.Failf(t, `Apply() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			require.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "github.com/whitekid/go-todo/storage"
+
+// batch operations
+const (
+	OpCreate = storage.OpCreate
+	OpUpdate = storage.OpUpdate
+	OpDelete = storage.OpDelete
+)
+
+// BatchOperation is operation of batch request
+type BatchOperation struct {
+	Op       string `json:"op" enums:"create,update,delete" example:"update"`
+	ID       string `json:"id,omitempty" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"` // item ID to delete
+	Item     *Item  `json:"item,omitempty"`                                                            // item to create or update
+	Revision int    `json:"revision,omitempty" example:"1"`                                            // expected revision for update and delete, like If-Match
+}
+
+// BatchRequest is list of operations
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic" example:"false"` // all operations succeed or all fail
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult is result of operation
+type BatchResult struct {
+	Status int    `json:"status" example:"200"` // http status code of operation
+	Item   *Item  `json:"item,omitempty"`       // created or updated item
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchResponse is results of operations, in order of requested operations
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/whitekid/go-todo/storage"
+
+type (
+	Comment  = storage.Comment
+	Activity = storage.Activity
+)
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/whitekid/go-todo/storage"
+
+type (
+	List   = storage.List
+	Member = storage.Member
+	Role   = storage.Role
+)
+
+// roles of list members
+const (
+	RoleOwner  = storage.RoleOwner
+	RoleEditor = storage.RoleEditor
+	RoleViewer = storage.RoleViewer
+)
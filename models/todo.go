@@ -1,7 +1,53 @@
 package models
 
-import "github.com/whitekid/go-todo/storage"
+import (
+	"github.com/whitekid/go-todo/quickadd"
+	"github.com/whitekid/go-todo/storage"
+)
 
-type Item = storage.TodoItem
+type (
+	Item        = storage.TodoItem
+	ListOptions = storage.ListOptions
+	Tag         = storage.Tag
+	TreeItem    = storage.TreeItem
+	Dependency  = storage.Dependency
+	Date        = storage.Date
+	Recurrence  = storage.Recurrence
+	Link        = storage.Link
 
-var Today = storage.Today
+	SearchResult = storage.SearchResult
+)
+
+var (
+	Today            = storage.Today
+	TodayIn          = storage.TodayIn
+	DateOf           = storage.DateOf
+	ParseListOptions = storage.ParseListOptions
+	SearchTerms      = storage.SearchTerms
+)
+
+// views of todo items
+const (
+	ViewToday    = "today"    // open items due today or before
+	ViewUpcoming = "upcoming" // open items due in next days
+	ViewOverdue  = "overdue"  // open items which due time is passed
+)
+
+// MoveRequest is anchor of moving item, item is placed right before or after the anchor item
+type MoveRequest struct {
+	Before string `json:"before,omitempty" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"`
+	After  string `json:"after,omitempty" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"`
+}
+
+// QuickAddRequest is natural language text of todo item
+type QuickAddRequest struct {
+	Text   string `json:"text" example:"Pay rent every month on the 1st #home !high tomorrow 9am"`
+	ListID string `json:"list_id,omitempty" format:"uuid" example:"1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"` // list of item
+	DryRun bool   `json:"dry_run,omitempty" example:"false"`                                              // parse only, item is not created
+}
+
+// QuickAddResponse is item parsed from text with interpreted tokens
+type QuickAddResponse struct {
+	Item   *Item            `json:"item"`
+	Tokens []quickadd.Token `json:"tokens"`
+}
@@ -0,0 +1,7 @@
+package models
+
+import "github.com/whitekid/go-todo/storage"
+
+type (
+	User = storage.User
+)
@@ -0,0 +1,361 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+// cond is a term of query
+type cond struct {
+	fn     func(item *types.TodoItem, ctx *context) bool
+	narrow func(opts *types.ListOptions, ctx *context) // narrow list options by the condition, nil if not applicable
+}
+
+func (c *cond) match(item *types.TodoItem, ctx *context) bool { return c.fn(item, ctx) }
+
+// keywords which are same to is:keyword
+var keywords = map[string]bool{"done": true, "open": true, "overdue": true, "recurring": true}
+
+// newWord returns condition of a word without field
+func newWord(word string, pos int) (*cond, error) {
+	lower := strings.ToLower(word)
+
+	if keywords[lower] {
+		return newCond("is", ":", lower, pos)
+	}
+
+	if strings.HasPrefix(lower, "#") && len(lower) > 1 {
+		return newCond("tag", ":", word[1:], pos)
+	}
+
+	return newCond("title", ":", word, pos)
+}
+
+// newCond returns condition of field, op and value
+func newCond(field, op, value string, pos int) (*cond, error) {
+	switch field {
+	case "tag":
+		return tagCond(op, value, pos)
+	case "title":
+		return titleCond(op, value, pos)
+	case "list":
+		return idCond(op, value, pos, func(item *types.TodoItem) string { return item.ListID },
+			func(opts *types.ListOptions) *string { return &opts.ListID })
+	case "parent":
+		return idCond(op, value, pos, func(item *types.TodoItem) string { return item.ParentID },
+			func(opts *types.ListOptions) *string { return &opts.ParentID })
+	case "priority":
+		return priorityCond(op, value, pos)
+	case "due":
+		return dueCond(op, value, pos)
+	case "created":
+		return timeCond(op, value, pos, func(item *types.TodoItem) *time.Time { return &item.CreatedAt })
+	case "updated":
+		return timeCond(op, value, pos, func(item *types.TodoItem) *time.Time { return &item.UpdatedAt })
+	case "completed":
+		return timeCond(op, value, pos, func(item *types.TodoItem) *time.Time { return item.CompletedAt })
+	case "is":
+		return isCond(op, value, pos)
+	}
+
+	return nil, errorf(pos, "unknown field: %s", field)
+}
+
+// equality returns true if op is equality operator, negate is true for !=
+func equality(op string, pos int) (negate bool, err error) {
+	switch op {
+	case ":", "=":
+		return false, nil
+	case "!=":
+		return true, nil
+	}
+	return false, errorf(pos, "operator %s is not allowed", op)
+}
+
+// compare returns result of comparison of op, cmp is -1, 0, 1 as a < b, a == b, a > b
+func compare(op string, cmp int) bool {
+	switch op {
+	case ":", "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func not(negate bool, fn func(item *types.TodoItem, ctx *context) bool) func(item *types.TodoItem, ctx *context) bool {
+	if !negate {
+		return fn
+	}
+	return func(item *types.TodoItem, ctx *context) bool { return !fn(item, ctx) }
+}
+
+func tagCond(op, value string, pos int) (*cond, error) {
+	negate, err := equality(op, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := types.NormalizeTag(value)
+	c := &cond{fn: not(negate, func(item *types.TodoItem, ctx *context) bool { return item.HasTags([]string{tag}) })}
+	if !negate {
+		c.narrow = func(opts *types.ListOptions, ctx *context) {
+			if len(opts.Tags) == 0 {
+				opts.Tags = [][]string{{tag}}
+				return
+			}
+
+			for i := range opts.Tags {
+				opts.Tags[i] = types.NormalizeTags(append(opts.Tags[i], tag))
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func titleCond(op, value string, pos int) (*cond, error) {
+	lower := strings.ToLower(value)
+
+	switch op {
+	case ":":
+		return &cond{fn: func(item *types.TodoItem, ctx *context) bool {
+			return strings.Contains(strings.ToLower(item.Title), lower)
+		}}, nil
+	case "=", "!=":
+		return &cond{fn: not(op == "!=", func(item *types.TodoItem, ctx *context) bool {
+			return strings.EqualFold(item.Title, value)
+		})}, nil
+	}
+
+	return nil, errorf(pos, "operator %s is not allowed", op)
+}
+
+// idCond returns condition of ID field, none for empty ID
+func idCond(op, value string, pos int, field func(item *types.TodoItem) string, option func(opts *types.ListOptions) *string) (*cond, error) {
+	negate, err := equality(op, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	id := value
+	if strings.EqualFold(value, "none") {
+		id = ""
+	}
+
+	c := &cond{fn: not(negate, func(item *types.TodoItem, ctx *context) bool { return field(item) == id })}
+	if !negate && id != "" {
+		c.narrow = func(opts *types.ListOptions, ctx *context) {
+			if o := option(opts); *o == "" {
+				*o = id
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func priorityCond(op, value string, pos int) (*cond, error) {
+	priority := strings.ToLower(value)
+	if priority == "none" {
+		priority = ""
+	}
+
+	if priority != "" && types.PriorityLevel(priority) == 0 {
+		return nil, errorf(pos, "invalid priority: %s", value)
+	}
+
+	level := types.PriorityLevel(priority)
+	return &cond{fn: func(item *types.TodoItem, ctx *context) bool {
+		return compare(op, types.PriorityLevel(item.Priority)-level)
+	}}, nil
+}
+
+var relativeRe = regexp.MustCompile(`^([+-]?)(\d+)([dwm])$`)
+
+// parseDate parse date value, returns nil for none
+func parseDate(value string, pos int) (func(ctx *context) types.Date, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return nil, nil
+	case "today":
+		return func(ctx *context) types.Date { return ctx.today }, nil
+	case "tomorrow":
+		return func(ctx *context) types.Date { return ctx.today.AddDays(1) }, nil
+	case "yesterday":
+		return func(ctx *context) types.Date { return ctx.today.AddDays(-1) }, nil
+	}
+
+	if m := relativeRe.FindStringSubmatch(strings.ToLower(value)); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, errorf(pos, "invalid date: %s", value)
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+
+		switch m[3] {
+		case "d":
+			return func(ctx *context) types.Date { return ctx.today.AddDays(n) }, nil
+		case "w":
+			return func(ctx *context) types.Date { return ctx.today.AddDays(7 * n) }, nil
+		default:
+			return func(ctx *context) types.Date { return types.Date{Time: ctx.today.AddDate(0, n, 0)} }, nil
+		}
+	}
+
+	t, err := time.Parse(types.RFC3339FullDate, value)
+	if err != nil {
+		return nil, errorf(pos, "invalid date: %s", value)
+	}
+
+	return func(ctx *context) types.Date { return types.Date{Time: t} }, nil
+}
+
+// compareDate compare date a to b
+func compareDate(a, b types.Date) int {
+	switch {
+	case a.Before(b.Time):
+		return -1
+	case a.After(b.Time):
+		return 1
+	}
+	return 0
+}
+
+// dateCond returns condition of date field; items without date matches only to none or by !=
+func dateCond(op, value string, pos int, field func(item *types.TodoItem, ctx *context) *types.Date) (*cond, error) {
+	date, err := parseDate(value, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	if date == nil {
+		negate, err := equality(op, pos)
+		if err != nil {
+			return nil, err
+		}
+		return &cond{fn: not(negate, func(item *types.TodoItem, ctx *context) bool { return field(item, ctx) == nil })}, nil
+	}
+
+	return &cond{fn: func(item *types.TodoItem, ctx *context) bool {
+		d := field(item, ctx)
+		if d == nil {
+			return op == "!="
+		}
+		return compare(op, compareDate(*d, date(ctx)))
+	}}, nil
+}
+
+func dueCond(op, value string, pos int) (*cond, error) {
+	c, err := dateCond(op, value, pos, func(item *types.TodoItem, ctx *context) *types.Date {
+		if item.DueDate.IsZero() {
+			return nil
+		}
+		return &item.DueDate
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	date, _ := parseDate(value, pos)
+	if date == nil {
+		return c, nil
+	}
+
+	// due before and due after of list options are exclusive
+	c.narrow = func(opts *types.ListOptions, ctx *context) {
+		d := date(ctx)
+		before := func(b types.Date) {
+			if opts.DueBefore == nil || b.Before(opts.DueBefore.Time) {
+				opts.DueBefore = &b
+			}
+		}
+		after := func(a types.Date) {
+			if opts.DueAfter == nil || a.After(opts.DueAfter.Time) {
+				opts.DueAfter = &a
+			}
+		}
+
+		switch op {
+		case ":", "=":
+			before(d.AddDays(1))
+			after(d.AddDays(-1))
+		case "<":
+			before(d)
+		case "<=":
+			before(d.AddDays(1))
+		case ">":
+			after(d)
+		case ">=":
+			after(d.AddDays(-1))
+		}
+	}
+
+	return c, nil
+}
+
+// timeCond returns condition of date of timestamp in location of now
+func timeCond(op, value string, pos int, field func(item *types.TodoItem) *time.Time) (*cond, error) {
+	return dateCond(op, value, pos, func(item *types.TodoItem, ctx *context) *types.Date {
+		t := field(item)
+		if t == nil || t.IsZero() {
+			return nil
+		}
+
+		d := types.DateOf(t.In(ctx.now.Location()))
+		return &d
+	})
+}
+
+func isCond(op, value string, pos int) (*cond, error) {
+	negate, err := equality(op, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *cond
+	switch strings.ToLower(value) {
+	case "done":
+		c = &cond{fn: func(item *types.TodoItem, ctx *context) bool { return item.Completed }}
+		if !negate {
+			c.narrow = status(types.StatusCompleted)
+		}
+	case "open":
+		c = &cond{fn: func(item *types.TodoItem, ctx *context) bool { return !item.Completed }}
+		if !negate {
+			c.narrow = status(types.StatusOpen)
+		}
+	case "overdue":
+		c = &cond{fn: func(item *types.TodoItem, ctx *context) bool { return item.Overdue(ctx.now) }}
+	case "recurring":
+		c = &cond{fn: func(item *types.TodoItem, ctx *context) bool { return item.Recurrence != nil }}
+	default:
+		return nil, errorf(pos, "unknown keyword: %s", value)
+	}
+
+	c.fn = not(negate, c.fn)
+	return c, nil
+}
+
+// status returns narrow function of status filter
+func status(status string) func(opts *types.ListOptions, ctx *context) {
+	return func(opts *types.ListOptions, ctx *context) {
+		if opts.Status == "" || opts.Status == types.StatusAll {
+			opts.Status = status
+		}
+	}
+}
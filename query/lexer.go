@@ -0,0 +1,124 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenWord             // tag, work, +3d, 2006-01-02
+	tokenString           // "quoted words"
+	tokenOp               // : = != < <= > >=
+	tokenLParen           // (
+	tokenRParen           // )
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in query
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// is returns true if token is the word, case insensitive
+func (t token) is(word string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+// Error is syntax or semantic error of query
+type Error struct {
+	Pos int    // byte offset in query
+	Msg string // error message
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at %d", e.Msg, e.Pos)
+}
+
+func errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// lex split query into tokens, last token is tokenEOF
+func lex(s string) ([]token, error) {
+	tokens := []token{}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+
+		case c == '"':
+			var b strings.Builder
+			start := i
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errorf(start, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, token{tokenString, b.String(), start})
+
+		case c == ':' || c == '=':
+			tokens = append(tokens, token{tokenOp, string(c), i})
+			i++
+
+		case c == '<' || c == '>' || (c == '!' && i+1 < len(s) && s[i+1] == '='):
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			tokens = append(tokens, token{tokenOp, op, i})
+			i += len(op)
+
+		default:
+			start := i
+			for i < len(s) && !isDelimiter(s, i) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, s[start:i], start})
+		}
+	}
+
+	return append(tokens, token{tokenEOF, "", len(s)}), nil
+}
+
+// isDelimiter returns true if character at i ends a word
+func isDelimiter(s string, i int) bool {
+	switch c := s[i]; c {
+	case '(', ')', '"', ':', '=', '<', '>':
+		return true
+	case '!':
+		return i+1 < len(s) && s[i+1] == '='
+	default:
+		return unicode.IsSpace(rune(c))
+	}
+}
@@ -0,0 +1,118 @@
+package query
+
+import "strings"
+
+// parser is recursive descent parser of query:
+//
+//	or    := and ("or" and)*
+//	and   := unary (["and"] unary)*
+//	unary := "not" unary | "(" or ")" | term
+//	term  := field op value | word | string
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []expr{left}
+	for p.peek().is("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+
+	if len(exprs) == 1 {
+		return left, nil
+	}
+	return orExpr(exprs), nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []expr{left}
+	for {
+		t := p.peek()
+		if t.is("and") {
+			p.next()
+		} else if t.kind == tokenEOF || t.kind == tokenRParen || t.is("or") {
+			break
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+
+	if len(exprs) == 1 {
+		return left, nil
+	}
+	return andExpr(exprs), nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	t := p.next()
+
+	switch {
+	case t.is("not"):
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+
+	case t.kind == tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if r := p.next(); r.kind != tokenRParen {
+			return nil, errorf(r.pos, "expected ')' but %s", r)
+		}
+		return e, nil
+
+	case t.kind == tokenString:
+		return newCond("title", ":", t.text, t.pos)
+
+	case t.is("and") || t.is("or"):
+		return nil, errorf(t.pos, "unexpected %s", t)
+
+	case t.kind == tokenWord:
+		if p.peek().kind != tokenOp {
+			return newWord(t.text, t.pos)
+		}
+
+		op := p.next()
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, errorf(value.pos, "expected value but %s", value)
+		}
+		return newCond(strings.ToLower(t.text), op.text, value.text, t.pos)
+	}
+
+	return nil, errorf(t.pos, "unexpected %s", t)
+}
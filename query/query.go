@@ -0,0 +1,142 @@
+// Package query implements small query language over todo items, such as
+//
+//	tag:work and due<+3d and not done
+//
+// terms are "field op value", keywords or words; terms are combined with and, or, not and parentheses.
+// juxtaposed terms are combined with and.
+//
+//	fields: tag, title, list, parent, priority, due, created, updated, completed, is
+//	ops: : = != < <= > >=, : is same to = except title, which contains the value
+//	dates: 2006-01-02, today, tomorrow, yesterday, +3d, -1w, +2m or none
+//	keywords: done, open, overdue, recurring; same to is:done
+//	#work is tag:work, other words and quoted strings are contained in title
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+// Query is parsed query
+type Query struct {
+	text string
+	root expr
+}
+
+// Parse parse query
+func Parse(s string) (*Query, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errorf(0, "empty query")
+	}
+
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorf(t.pos, "unexpected %s", t)
+	}
+
+	return &Query{text: s, root: root}, nil
+}
+
+func (q *Query) String() string { return q.text }
+
+// Match returns true if item matches query; relative dates are computed at now in its location
+func (q *Query) Match(item *types.TodoItem, now time.Time) bool {
+	return q.root.match(item, newContext(now))
+}
+
+// Apply set query as predicate of list options at now in its location.
+// options are narrowed by conditions which every matched item satisfies,
+// so that storage can scan its indexes of tag, list and parent instead of all items
+func (q *Query) Apply(opts *types.ListOptions, now time.Time) {
+	ctx := newContext(now)
+
+	terms := []expr{q.root}
+	if and, ok := q.root.(andExpr); ok {
+		terms = and
+	}
+
+	for _, term := range terms {
+		if c, ok := term.(*cond); ok && c.narrow != nil {
+			c.narrow(opts, ctx)
+		}
+	}
+
+	opts.Predicate = func(item *types.TodoItem) bool { return q.root.match(item, ctx) }
+}
+
+// Compile parse query of list options and apply it, relative dates are computed in location of options.
+// it does nothing if options has no query
+func Compile(opts *types.ListOptions) error {
+	if opts == nil || opts.Query == "" {
+		return nil
+	}
+
+	q, err := Parse(opts.Query)
+	if err != nil {
+		return err
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	q.Apply(opts, time.Now().In(loc))
+	return nil
+}
+
+// context is environment of evaluation
+type context struct {
+	now   time.Time
+	today types.Date
+}
+
+func newContext(now time.Time) *context {
+	return &context{now: now, today: types.DateOf(now)}
+}
+
+type expr interface {
+	match(item *types.TodoItem, ctx *context) bool
+}
+
+type andExpr []expr
+
+func (e andExpr) match(item *types.TodoItem, ctx *context) bool {
+	for _, x := range e {
+		if !x.match(item, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+type orExpr []expr
+
+func (e orExpr) match(item *types.TodoItem, ctx *context) bool {
+	for _, x := range e {
+		if x.match(item, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+type notExpr struct {
+	expr expr
+}
+
+func (e notExpr) match(item *types.TodoItem, ctx *context) bool {
+	return !e.expr.match(item, ctx)
+}
@@ -0,0 +1,151 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"word", "milk", false},
+		{"field", "tag:work", false},
+		{"implicit and", "tag:work due<today", false},
+		{"and or not", "tag:work and (due<+3d or priority>=high) and not done", false},
+		{"quoted", `title:"buy milk" or "call mom"`, false},
+		{"empty", "  ", true},
+		{"unknown field", "color:red", true},
+		{"unknown keyword", "is:blocked", true},
+		{"invalid date", "due<2024-13-01", true},
+		{"invalid relative date", "due<+3y", true},
+		{"invalid priority", "priority:critical", true},
+		{"invalid op", "tag<work", true},
+		{"missing value", "tag:", true},
+		{"missing operand", "tag:work and", true},
+		{"dangling or", "or tag:work", true},
+		{"unbalanced", "(tag:work", true},
+		{"unexpected paren", "tag:work)", true},
+		{"unterminated string", `"buy milk`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if (err != nil) != tt.wantErr {
+				require.Failf(t, `Parse() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+			}
+			if err != nil {
+				require.IsType(t, &Error{}, err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Date(2024, 3, 13, 10, 30, 0, 0, time.UTC)
+	yesterday := now.Add(-24 * time.Hour)
+
+	items := map[string]types.TodoItem{
+		"report": {ID: "report", Title: "Write report", Tags: []string{"work"}, Priority: types.PriorityHigh,
+			DueDate: types.NewDate(2024, 3, 15), ListID: "office", CreatedAt: yesterday},
+		"milk": {ID: "milk", Title: "Buy milk", Tags: []string{"home", "shopping"},
+			DueDate: types.NewDate(2024, 3, 12), CreatedAt: now},
+		"gym": {ID: "gym", Title: "Gym", Priority: types.PriorityLow, Recurrence: &types.Recurrence{Freq: types.FreqDaily},
+			Completed: true, CompletedAt: &now, CreatedAt: yesterday},
+	}
+
+	tests := [...]struct {
+		query string
+		want  []string
+	}{
+		{"milk", []string{"milk"}},
+		{`"write rep"`, []string{"report"}},
+		{"title=gym", []string{"gym"}},
+		{"title!=gym", []string{"milk", "report"}},
+		{"tag:work", []string{"report"}},
+		{"#home", []string{"milk"}},
+		{"tag!=work", []string{"gym", "milk"}},
+		{"tag:work or tag:shopping", []string{"milk", "report"}},
+		{"list:office", []string{"report"}},
+		{"list:none", []string{"gym", "milk"}},
+		{"priority>=medium", []string{"report"}},
+		{"priority:none", []string{"milk"}},
+		{"priority<high", []string{"gym", "milk"}},
+		{"due<today", []string{"milk"}},
+		{"due<=+2d", []string{"milk", "report"}},
+		{"due=2024-03-15", []string{"report"}},
+		{"due:none", []string{"gym"}},
+		{"due!=none", []string{"milk", "report"}},
+		{"due>=yesterday due<1w", []string{"milk", "report"}},
+		{"created:today", []string{"milk"}},
+		{"created<today", []string{"gym", "report"}},
+		{"completed:today", []string{"gym"}},
+		{"done", []string{"gym"}},
+		{"open", []string{"milk", "report"}},
+		{"overdue", []string{"milk"}},
+		{"is:recurring", []string{"gym"}},
+		{"is!=recurring", []string{"milk", "report"}},
+		{"not done and not overdue", []string{"report"}},
+		{"(tag:work or tag:home) priority>low", []string{"report"}},
+		{"TAG:WORK AND NOT DONE", []string{"report"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.query, q.String())
+
+			got := []string{}
+			for _, id := range []string{"gym", "milk", "report"} {
+				item := items[id]
+				if q.Match(&item, now) {
+					got = append(got, id)
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2024, 3, 13, 10, 30, 0, 0, time.UTC)
+	date := func(d int) *types.Date { v := types.NewDate(2024, 3, d); return &v }
+
+	tests := [...]struct {
+		query string
+		opts  types.ListOptions
+		want  types.ListOptions
+	}{
+		{"tag:work", types.ListOptions{}, types.ListOptions{Tags: [][]string{{"work"}}}},
+		{"tag:work", types.ListOptions{Tags: [][]string{{"a"}, {"b"}}}, types.ListOptions{Tags: [][]string{{"a", "work"}, {"b", "work"}}}},
+		{"tag:work or tag:home", types.ListOptions{}, types.ListOptions{}},
+		{"not tag:work", types.ListOptions{}, types.ListOptions{}},
+		{"list:office parent:p1", types.ListOptions{}, types.ListOptions{ListID: "office", ParentID: "p1"}},
+		{"list:office", types.ListOptions{ListID: "home"}, types.ListOptions{ListID: "home"}},
+		{"due<today", types.ListOptions{}, types.ListOptions{DueBefore: date(13)}},
+		{"due<=today due>yesterday", types.ListOptions{}, types.ListOptions{DueBefore: date(14), DueAfter: date(12)}},
+		{"due=today", types.ListOptions{DueBefore: date(20)}, types.ListOptions{DueBefore: date(14), DueAfter: date(12)}},
+		{"due<+7d", types.ListOptions{DueBefore: date(14)}, types.ListOptions{DueBefore: date(14)}},
+		{"done", types.ListOptions{}, types.ListOptions{Status: types.StatusCompleted}},
+		{"open", types.ListOptions{Status: types.StatusAll}, types.ListOptions{Status: types.StatusOpen}},
+		{"open", types.ListOptions{Status: types.StatusCompleted}, types.ListOptions{Status: types.StatusCompleted}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			require.NoError(t, err)
+
+			opts := tt.opts
+			q.Apply(&opts, now)
+			require.NotNil(t, opts.Predicate)
+
+			opts.Predicate = nil
+			require.Equal(t, tt.want, opts)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package quickadd
+
+import (
+	"time"
+
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+// Keyword is meaning of a word in the grammar
+type Keyword int
+
+// keywords of grammar
+const (
+	KeywordNone     Keyword = iota
+	KeywordToday            // today
+	KeywordTomorrow         // tomorrow
+	KeywordNext             // next monday, next week
+	KeywordIn               // in 3 days
+	KeywordOn               // on friday, every month on the 1st
+	KeywordAt               // at 9am
+	KeywordEvery            // every day
+	KeywordOther            // every other week
+	KeywordAnd              // every mon and fri
+	KeywordUntil            // every day until mar 31
+	KeywordThe              // on the 1st
+	KeywordLast             // on the last day
+	KeywordWeekday          // every weekday
+	KeywordOne              // in a week
+	KeywordNoon             // 12:00
+	KeywordAM               // 9am
+	KeywordPM               // 9pm
+	KeywordDaily            // every day
+	KeywordWeekly           // every week
+	KeywordMonthly          // every month
+)
+
+// Grammar is vocabulary of a language; all words are lower case
+type Grammar struct {
+	Keywords      map[string]Keyword
+	Units         map[string]string       // day, week, month to recurrence frequency
+	Weekdays      map[string]time.Weekday // full names, recognized anywhere
+	ShortWeekdays map[string]time.Weekday // abbreviations, recognized only after on, next, every
+	Months        map[string]time.Month
+	Ordinals      []string          // suffixes of day of month: 1st, 2nd
+	Priorities    map[string]string // priority names after ! to item priority
+}
+
+// English is grammar of english
+var English = &Grammar{
+	Keywords: map[string]Keyword{
+		"today":    KeywordToday,
+		"tomorrow": KeywordTomorrow,
+		"tmr":      KeywordTomorrow,
+		"tmrw":     KeywordTomorrow,
+		"next":     KeywordNext,
+		"in":       KeywordIn,
+		"on":       KeywordOn,
+		"by":       KeywordOn,
+		"due":      KeywordOn,
+		"at":       KeywordAt,
+		"@":        KeywordAt,
+		"every":    KeywordEvery,
+		"each":     KeywordEvery,
+		"other":    KeywordOther,
+		"and":      KeywordAnd,
+		"until":    KeywordUntil,
+		"till":     KeywordUntil,
+		"the":      KeywordThe,
+		"last":     KeywordLast,
+		"weekday":  KeywordWeekday,
+		"weekdays": KeywordWeekday,
+		"a":        KeywordOne,
+		"an":       KeywordOne,
+		"noon":     KeywordNoon,
+		"am":       KeywordAM,
+		"a.m.":     KeywordAM,
+		"pm":       KeywordPM,
+		"p.m.":     KeywordPM,
+		"daily":    KeywordDaily,
+		"weekly":   KeywordWeekly,
+		"monthly":  KeywordMonthly,
+	},
+	Units: map[string]string{
+		"day": types.FreqDaily, "days": types.FreqDaily,
+		"week": types.FreqWeekly, "weeks": types.FreqWeekly,
+		"month": types.FreqMonthly, "months": types.FreqMonthly,
+	},
+	Weekdays: map[string]time.Weekday{
+		"sunday": time.Sunday, "sundays": time.Sunday,
+		"monday": time.Monday, "mondays": time.Monday,
+		"tuesday": time.Tuesday, "tuesdays": time.Tuesday,
+		"wednesday": time.Wednesday, "wednesdays": time.Wednesday,
+		"thursday": time.Thursday, "thursdays": time.Thursday,
+		"friday": time.Friday, "fridays": time.Friday,
+		"saturday": time.Saturday, "saturdays": time.Saturday,
+	},
+	ShortWeekdays: map[string]time.Weekday{
+		"sun": time.Sunday,
+		"mon": time.Monday,
+		"tue": time.Tuesday, "tues": time.Tuesday,
+		"wed": time.Wednesday,
+		"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+		"fri": time.Friday,
+		"sat": time.Saturday,
+	},
+	Months: map[string]time.Month{
+		"january": time.January, "jan": time.January,
+		"february": time.February, "feb": time.February,
+		"march": time.March, "mar": time.March,
+		"april": time.April, "apr": time.April,
+		"may":  time.May,
+		"june": time.June, "jun": time.June,
+		"july": time.July, "jul": time.July,
+		"august": time.August, "aug": time.August,
+		"september": time.September, "sep": time.September, "sept": time.September,
+		"october": time.October, "oct": time.October,
+		"november": time.November, "nov": time.November,
+		"december": time.December, "dec": time.December,
+	},
+	Ordinals: []string{"st", "nd", "rd", "th"},
+	Priorities: map[string]string{
+		"low":    types.PriorityLow,
+		"medium": types.PriorityMedium,
+		"med":    types.PriorityMedium,
+		"high":   types.PriorityHigh,
+		"urgent": types.PriorityUrgent,
+		"1":      types.PriorityUrgent,
+		"2":      types.PriorityHigh,
+		"3":      types.PriorityMedium,
+		"4":      types.PriorityLow,
+	},
+}
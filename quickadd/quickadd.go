@@ -0,0 +1,615 @@
+// Package quickadd parses natural language todo item such as
+// "Pay rent every month on the 1st #home !high tomorrow 9am"
+//
+// Words which are not interpreted are title of the item; words in double quotes are always title.
+// Each kind of date, time and recurrence is interpreted once, later one is left in the title.
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+// kinds of interpreted token
+const (
+	KindTag        = "tag"
+	KindPriority   = "priority"
+	KindDate       = "date"
+	KindTime       = "time"
+	KindRecurrence = "recurrence"
+)
+
+// prefixes of tag and priority
+const (
+	TagPrefix      = "#"
+	PriorityPrefix = "!"
+)
+
+var (
+	ErrNoTitle = errors.New("title is required")
+)
+
+// Token is interpreted part of text
+type Token struct {
+	Text  string `json:"text" example:"tomorrow"`                                       // words of the text
+	Kind  string `json:"kind" enums:"tag,priority,date,time,recurrence" example:"date"` // how the words are interpreted
+	Value string `json:"value" example:"2006-01-02"`                                    // interpreted value, recurrence in RRULE format
+}
+
+// Result is todo item parsed from text
+type Result struct {
+	Item   types.TodoItem // title, due date, due time, tags, priority and recurrence
+	Tokens []Token        // interpreted tokens, in order of text
+}
+
+// Parse parse text by English grammar, see Grammar.Parse
+func Parse(text string, now time.Time) (*Result, error) {
+	return English.Parse(text, now)
+}
+
+// Parse parse text into todo item. relative dates are computed from now in its location.
+// if only time or recurrence is given, due date is today or its first occurrence from today;
+// time which is already passed today means tomorrow
+func (g *Grammar) Parse(text string, now time.Time) (*Result, error) {
+	p := &parser{
+		g:     g,
+		words: split(text),
+		now:   now,
+		today: types.DateOf(now),
+	}
+
+	return p.parse()
+}
+
+// word is a word of text
+type word struct {
+	text   string // original text
+	lower  string // lower case without trailing comma, for matching
+	quoted bool   // quoted words are not interpreted
+}
+
+// split split text into words by white spaces; words in double quotes are one word
+func split(text string) []word {
+	words := []word{}
+
+	var b strings.Builder
+	quoted, inQuote := false, false
+	flush := func() {
+		if b.Len() > 0 {
+			s := b.String()
+			words = append(words, word{text: s, lower: strings.TrimRight(strings.ToLower(s), ","), quoted: quoted})
+		}
+		b.Reset()
+		quoted = false
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			flush()
+			inQuote = !inQuote
+			quoted = inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+type parser struct {
+	g     *Grammar
+	words []word
+	now   time.Time
+	today types.Date
+
+	result  Result
+	date    *types.Date
+	time    string
+	recurId int // index of recurrence token, -1 if none
+}
+
+func (p *parser) parse() (*Result, error) {
+	title := []string{}
+	p.recurId = -1
+
+	for i := 0; i < len(p.words); {
+		if n := p.token(i); n > 0 {
+			i += n
+			continue
+		}
+
+		title = append(title, p.words[i].text)
+		i++
+	}
+
+	if len(p.result.Item.Tags) > 0 {
+		p.result.Item.Tags = types.NormalizeTags(p.result.Item.Tags)
+	}
+
+	p.result.Item.Title = strings.Join(title, " ")
+	if strings.TrimSpace(p.result.Item.Title) == "" {
+		return nil, ErrNoTitle
+	}
+
+	if err := p.due(); err != nil {
+		return nil, err
+	}
+
+	return &p.result, nil
+}
+
+// token interpret words at i, returns number of interpreted words
+func (p *parser) token(i int) int {
+	w := p.words[i]
+	if w.quoted {
+		return 0
+	}
+
+	if strings.HasPrefix(w.lower, TagPrefix) && len(w.lower) > len(TagPrefix) {
+		tag := types.NormalizeTag(strings.TrimPrefix(w.lower, TagPrefix))
+		p.result.Item.Tags = append(p.result.Item.Tags, tag)
+		p.add(i, 1, KindTag, tag)
+		return 1
+	}
+
+	if strings.HasPrefix(w.lower, PriorityPrefix) && p.result.Item.Priority == "" {
+		if priority, ok := p.g.Priorities[strings.TrimPrefix(w.lower, PriorityPrefix)]; ok {
+			p.result.Item.Priority = priority
+			p.add(i, 1, KindPriority, priority)
+			return 1
+		}
+	}
+
+	if p.result.Item.Recurrence == nil {
+		if r, n := p.recurrence(i); n > 0 {
+			p.result.Item.Recurrence = r
+			p.recurId = len(p.result.Tokens)
+			p.add(i, n, KindRecurrence, "")
+			return n
+		}
+	}
+
+	if p.date == nil {
+		if d, n := p.dateAt(i); n > 0 {
+			p.date = &d
+			p.add(i, n, KindDate, d.String())
+			return n
+		}
+	}
+
+	if p.time == "" {
+		if t, n := p.timeAt(i); n > 0 {
+			p.time = t
+			p.add(i, n, KindTime, t)
+			return n
+		}
+	}
+
+	return 0
+}
+
+// add add token of n words at i
+func (p *parser) add(i, n int, kind, value string) {
+	texts := []string{}
+	for _, w := range p.words[i : i+n] {
+		texts = append(texts, w.text)
+	}
+
+	p.result.Tokens = append(p.result.Tokens, Token{Text: strings.Join(texts, " "), Kind: kind, Value: value})
+}
+
+// due set due date, due time and recurrence of item
+func (p *parser) due() error {
+	item := &p.result.Item
+	item.DueTime = p.time
+
+	if p.date != nil {
+		item.DueDate = *p.date
+	} else if p.time != "" || item.Recurrence != nil {
+		item.DueDate = p.today
+		if p.time != "" && p.now.Format("15:04") >= p.time {
+			item.DueDate = p.today.AddDays(1)
+		}
+
+		if r := item.Recurrence; r != nil && (len(r.Weekdays) > 0 || r.MonthDay != 0) {
+			// first occurrence on or after the date
+			first := types.Recurrence{Freq: r.Freq, Interval: 1, Weekdays: r.Weekdays, MonthDay: r.MonthDay}
+			item.DueDate, _ = first.Next(item.DueDate.AddDays(-1), item.DueDate)
+		}
+	}
+
+	if item.Recurrence != nil {
+		if err := item.NormalizeRecurrence(); err != nil {
+			return err
+		}
+		p.result.Tokens[p.recurId].Value = item.Recurrence.String()
+	}
+
+	return nil
+}
+
+// lower returns lower case word at i, empty if out of range or quoted
+func (p *parser) lower(i int) string {
+	if i < 0 || i >= len(p.words) || p.words[i].quoted {
+		return ""
+	}
+	return p.words[i].lower
+}
+
+func (p *parser) keyword(i int) Keyword { return p.g.Keywords[p.lower(i)] }
+
+// weekday returns weekday at i; abbreviations are allowed if short
+func (p *parser) weekday(i int, short bool) (time.Weekday, bool) {
+	w := p.lower(i)
+	if wd, ok := p.g.Weekdays[w]; ok {
+		return wd, true
+	}
+
+	if short {
+		if wd, ok := p.g.ShortWeekdays[w]; ok {
+			return wd, true
+		}
+	}
+
+	return 0, false
+}
+
+// weekdays returns list of weekdays at i such as "mon, wed and fri", and number of words
+func (p *parser) weekdays(i int) ([]time.Weekday, int) {
+	days := []time.Weekday{}
+
+	j := i
+	for {
+		wd, ok := p.weekday(j, true)
+		if !ok {
+			break
+		}
+		days = append(days, wd)
+		j++
+
+		if p.keyword(j) == KeywordAnd {
+			if _, ok := p.weekday(j+1, true); ok {
+				j++
+			}
+		}
+	}
+
+	return days, j - i
+}
+
+// number returns positive number at i
+func (p *parser) number(i int) (int, bool) {
+	if p.keyword(i) == KeywordOne {
+		return 1, true
+	}
+
+	n, err := strconv.Atoi(p.lower(i))
+	if err != nil || n < 1 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// day returns day of month at i such as 1, 1st
+func (p *parser) day(i int) (int, bool) {
+	w := p.lower(i)
+	for _, suffix := range p.g.Ordinals {
+		if strings.HasSuffix(w, suffix) {
+			w = strings.TrimSuffix(w, suffix)
+			break
+		}
+	}
+
+	day, err := strconv.Atoi(w)
+	if err != nil || day < 1 || day > 31 {
+		return 0, false
+	}
+
+	return day, true
+}
+
+// year returns 4 digits year at i
+func (p *parser) year(i int) (int, bool) {
+	w := p.lower(i)
+	if len(w) != 4 {
+		return 0, false
+	}
+
+	year, err := strconv.Atoi(w)
+	if err != nil || year < 1000 {
+		return 0, false
+	}
+
+	return year, true
+}
+
+// nextWeekday returns the first weekday after today
+func (p *parser) nextWeekday(wd time.Weekday) types.Date {
+	days := (int(wd) - int(p.today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+
+	return p.today.AddDays(days)
+}
+
+// dateAt returns date at i and number of words
+func (p *parser) dateAt(i int) (types.Date, int) {
+	switch p.keyword(i) {
+	case KeywordToday:
+		return p.today, 1
+
+	case KeywordTomorrow:
+		return p.today.AddDays(1), 1
+
+	case KeywordOn:
+		if wd, ok := p.weekday(i+1, true); ok {
+			return p.nextWeekday(wd), 2
+		}
+		if d, n := p.dateAt(i + 1); n > 0 && p.keyword(i+1) != KeywordOn {
+			return d, n + 1
+		}
+		return types.Date{}, 0
+
+	case KeywordNext:
+		if wd, ok := p.weekday(i+1, true); ok {
+			return p.nextWeekday(wd), 2
+		}
+
+		switch p.g.Units[p.lower(i+1)] {
+		case types.FreqDaily:
+			return p.today.AddDays(1), 2
+		case types.FreqWeekly:
+			return p.nextWeekday(time.Monday), 2
+		case types.FreqMonthly:
+			return types.NewDate(p.today.Year(), p.today.Month()+1, 1), 2
+		}
+		return types.Date{}, 0
+
+	case KeywordIn:
+		n, ok := p.number(i + 1)
+		if !ok {
+			return types.Date{}, 0
+		}
+
+		switch p.g.Units[p.lower(i+2)] {
+		case types.FreqDaily:
+			return p.today.AddDays(n), 3
+		case types.FreqWeekly:
+			return p.today.AddDays(7 * n), 3
+		case types.FreqMonthly:
+			return types.NewDate(p.today.Year(), p.today.Month()+time.Month(n), 1).MonthDay(p.today.Day()), 3
+		}
+		return types.Date{}, 0
+	}
+
+	if wd, ok := p.weekday(i, false); ok {
+		return p.nextWeekday(wd), 1
+	}
+
+	if t, err := time.Parse(types.RFC3339FullDate, p.lower(i)); err == nil {
+		return types.Date{Time: t}, 1
+	}
+
+	// march 10, march 10 2021
+	if month, ok := p.g.Months[p.lower(i)]; ok {
+		if day, ok := p.day(i + 1); ok {
+			year, ok := p.year(i + 2)
+			if d, valid := p.monthDay(year, month, day); valid {
+				if ok {
+					return d, 3
+				}
+				return d, 2
+			}
+		}
+	}
+
+	// 10 march, 10th march 2021
+	if day, ok := p.day(i); ok {
+		if month, ok := p.g.Months[p.lower(i+1)]; ok {
+			year, ok := p.year(i + 2)
+			if d, valid := p.monthDay(year, month, day); valid {
+				if ok {
+					return d, 3
+				}
+				return d, 2
+			}
+		}
+	}
+
+	return types.Date{}, 0
+}
+
+// monthDay returns date of month and day; without year, it is the first one on or after today
+func (p *parser) monthDay(year int, month time.Month, day int) (types.Date, bool) {
+	if year == 0 {
+		year = p.today.Year()
+		if d := types.NewDate(year, month, day); d.Before(p.today.Time) {
+			year++
+		}
+	}
+
+	d := types.NewDate(year, month, day)
+	if d.Month() != month { // overflowed such as feb 30
+		return types.Date{}, false
+	}
+
+	return d, true
+}
+
+var clockRe = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?([a-z.]*)$`)
+
+// timeAt returns time of day at i in 15:04 format and number of words
+func (p *parser) timeAt(i int) (string, int) {
+	switch p.keyword(i) {
+	case KeywordAt:
+		if t, n := p.timeAt(i + 1); n > 0 {
+			return t, n + 1
+		}
+
+		// at 9
+		if hour, err := strconv.Atoi(p.lower(i + 1)); err == nil && hour >= 0 && hour < 24 {
+			return clock(hour, 0), 2
+		}
+		return "", 0
+
+	case KeywordNoon:
+		return clock(12, 0), 1
+	}
+
+	m := clockRe.FindStringSubmatch(p.lower(i))
+	if m == nil {
+		return "", 0
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if minute > 59 {
+		return "", 0
+	}
+
+	n := 1
+	suffix := m[3]
+	if suffix == "" {
+		// 9 am
+		if kw := p.keyword(i + 1); kw == KeywordAM || kw == KeywordPM {
+			suffix = p.lower(i + 1)
+			n = 2
+		}
+	}
+
+	switch p.g.Keywords[suffix] {
+	case KeywordAM, KeywordPM:
+		if hour < 1 || hour > 12 {
+			return "", 0
+		}
+
+		hour %= 12
+		if p.g.Keywords[suffix] == KeywordPM {
+			hour += 12
+		}
+
+	default:
+		// 24 hours clock requires minutes such as 21:00
+		if suffix != "" || m[2] == "" || hour > 23 {
+			return "", 0
+		}
+	}
+
+	return clock(hour, minute), n
+}
+
+func clock(hour, minute int) string {
+	return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC).Format("15:04")
+}
+
+// weekday codes of recurrence
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// recurrence returns recurrence at i and number of words such as
+// daily, every 2 weeks, every other month, every weekday, every mon and fri,
+// every week on friday, every month on the 1st, every month on the last day, every day until mar 31
+func (p *parser) recurrence(i int) (*types.Recurrence, int) {
+	r := &types.Recurrence{Interval: 1}
+	j := i
+
+	switch p.keyword(i) {
+	case KeywordDaily:
+		r.Freq = types.FreqDaily
+		j++
+
+	case KeywordWeekly:
+		r.Freq = types.FreqWeekly
+		j++
+
+	case KeywordMonthly:
+		r.Freq = types.FreqMonthly
+		j++
+
+	case KeywordEvery:
+		j++
+		if p.keyword(j) == KeywordOther {
+			r.Interval = 2
+			j++
+		} else if n, err := strconv.Atoi(p.lower(j)); err == nil && n > 0 && p.g.Units[p.lower(j+1)] != "" {
+			r.Interval = n
+			j++
+		}
+
+		if freq := p.g.Units[p.lower(j)]; freq != "" {
+			r.Freq = freq
+			j++
+		} else if r.Interval == 1 && p.keyword(j) == KeywordWeekday {
+			r.Freq = types.FreqWeekly
+			r.Weekdays = []string{"MO", "TU", "WE", "TH", "FR"}
+			j++
+		} else if days, n := p.weekdays(j); n > 0 && r.Interval == 1 {
+			r.Freq = types.FreqWeekly
+			r.Weekdays = codes(days)
+			j += n
+		} else {
+			return nil, 0
+		}
+
+	default:
+		return nil, 0
+	}
+
+	// on clause
+	if p.keyword(j) == KeywordOn {
+		switch r.Freq {
+		case types.FreqWeekly:
+			if days, n := p.weekdays(j + 1); n > 0 && len(r.Weekdays) == 0 {
+				r.Weekdays = codes(days)
+				j += 1 + n
+			}
+
+		case types.FreqMonthly:
+			k := j + 1
+			if p.keyword(k) == KeywordThe {
+				k++
+			}
+
+			if p.keyword(k) == KeywordLast {
+				k++
+				if p.g.Units[p.lower(k)] == types.FreqDaily {
+					k++
+				}
+				r.MonthDay = types.LastDay
+				j = k
+			} else if day, ok := p.day(k); ok {
+				r.MonthDay = day
+				j = k + 1
+			}
+		}
+	}
+
+	// until clause
+	if p.keyword(j) == KeywordUntil {
+		if d, n := p.dateAt(j + 1); n > 0 {
+			r.Until = &d
+			j += 1 + n
+		}
+	}
+
+	return r, j - i
+}
+
+func codes(days []time.Weekday) []string {
+	codes := make([]string, len(days))
+	for i, d := range days {
+		codes[i] = weekdayCodes[d]
+	}
+	return codes
+}
@@ -0,0 +1,147 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/types"
+)
+
+func TestParse(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 13, 10, 30, 0, 0, seoul) // wednesday
+
+	type want struct {
+		title      string
+		date       string
+		time       string
+		tags       []string
+		priority   string
+		recurrence string
+	}
+	tests := [...]struct {
+		name    string
+		text    string
+		want    want
+		wantErr bool
+	}{
+		{"title only", "Buy milk", want{title: "Buy milk"}, false},
+		{"today", "Buy milk today", want{title: "Buy milk", date: "2024-03-13"}, false},
+		{"tomorrow", "Call mom tmr", want{title: "Call mom", date: "2024-03-14"}, false},
+		{"on weekday", "Submit report on friday", want{title: "Submit report", date: "2024-03-15"}, false},
+		{"weekday", "Submit report friday", want{title: "Submit report", date: "2024-03-15"}, false},
+		{"weekday of today", "Meeting wednesday", want{title: "Meeting", date: "2024-03-20"}, false},
+		{"by short weekday", "Pay bills by fri", want{title: "Pay bills", date: "2024-03-15"}, false},
+		{"next weekday", "Meeting next mon", want{title: "Meeting", date: "2024-03-18"}, false},
+		{"due next weekday", "Sprint review due next friday", want{title: "Sprint review", date: "2024-03-15"}, false},
+		{"next week", "Review next week", want{title: "Review", date: "2024-03-18"}, false},
+		{"next month", "Pay taxes next month", want{title: "Pay taxes", date: "2024-04-01"}, false},
+		{"in days", "Renew passport in 3 days", want{title: "Renew passport", date: "2024-03-16"}, false},
+		{"in a week", "Renew passport in a week", want{title: "Renew passport", date: "2024-03-20"}, false},
+		{"in months", "Renew passport in 2 months", want{title: "Renew passport", date: "2024-05-13"}, false},
+		{"iso date", "Dentist 2024-04-02", want{title: "Dentist", date: "2024-04-02"}, false},
+		{"month day", "Dentist march 20", want{title: "Dentist", date: "2024-03-20"}, false},
+		{"passed month day", "Dentist mar 1st", want{title: "Dentist", date: "2025-03-01"}, false},
+		{"day month year", "Dentist 20 march 2025", want{title: "Dentist", date: "2025-03-20"}, false},
+		{"invalid month day", "Dentist feb 30", want{title: "Dentist feb 30"}, false},
+		{"time", "Lunch 1pm", want{title: "Lunch", date: "2024-03-13", time: "13:00"}, false},
+		{"passed time", "Standup 9:30am", want{title: "Standup", date: "2024-03-14", time: "09:30"}, false},
+		{"at hour", "Standup at 11", want{title: "Standup", date: "2024-03-13", time: "11:00"}, false},
+		{"noon", "Lunch noon", want{title: "Lunch", date: "2024-03-13", time: "12:00"}, false},
+		{"separated am pm", "Call 5 pm", want{title: "Call", date: "2024-03-13", time: "17:00"}, false},
+		{"midnight", "Release 12am tomorrow", want{title: "Release", date: "2024-03-14", time: "00:00"}, false},
+		{"24 hours clock", "Deploy 21:00 tomorrow", want{title: "Deploy", date: "2024-03-14", time: "21:00"}, false},
+		{"invalid time", "Call 13pm", want{title: "Call 13pm"}, false},
+		{"number", "Buy 2 apples", want{title: "Buy 2 apples"}, false},
+		{"year like number", "Read 1984", want{title: "Read 1984"}, false},
+		{"daily", "Water plants daily", want{title: "Water plants", date: "2024-03-13", recurrence: "FREQ=DAILY"}, false},
+		{"every weekdays", "Gym every mon, wed and fri", want{title: "Gym", date: "2024-03-13", recurrence: "FREQ=WEEKLY;BYDAY=MO,WE,FR"}, false},
+		{"every weekday at", "Gym every weekday at 7am",
+			want{title: "Gym", date: "2024-03-14", time: "07:00", recurrence: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"}, false},
+		{"every other week", "Water lawn every other week", want{title: "Water lawn", date: "2024-03-13", recurrence: "FREQ=WEEKLY;INTERVAL=2"}, false},
+		{"every days until", "Backup every 3 days until apr 1",
+			want{title: "Backup", date: "2024-03-13", recurrence: "FREQ=DAILY;INTERVAL=3;UNTIL=20240401"}, false},
+		{"every month on last day", "Pay card every month on the last day",
+			want{title: "Pay card", date: "2024-03-31", recurrence: "FREQ=MONTHLY;BYMONTHDAY=-1"}, false},
+		{"every weeks on weekday", "Team sync every 2 weeks on tue",
+			want{title: "Team sync", date: "2024-03-19", recurrence: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"}, false},
+		{"monthly", "Pay rent monthly", want{title: "Pay rent", date: "2024-03-13", recurrence: "FREQ=MONTHLY;BYMONTHDAY=13"}, false},
+		{"every without unit", "Eat every apple", want{title: "Eat every apple"}, false},
+		{"tags", "#work #Urgent #work fix bug", want{title: "fix bug", tags: []string{"urgent", "work"}}, false},
+		{"priority", "Fix bug !high", want{title: "Fix bug", priority: types.PriorityHigh}, false},
+		{"priority number", "Fix bug !1", want{title: "Fix bug", priority: types.PriorityUrgent}, false},
+		{"unknown priority", "Fix bug !important", want{title: "Fix bug !important"}, false},
+		{"second priority", "Fix bug !high !low", want{title: "Fix bug !low", priority: types.PriorityHigh}, false},
+		{"second date", "Dinner tomorrow tomorrow", want{title: "Dinner tomorrow", date: "2024-03-14"}, false},
+		{"quoted", `"Meet at 9am" friday`, want{title: "Meet at 9am", date: "2024-03-15"}, false},
+		{"keywords in title", "Look at docs on report in fridge", want{title: "Look at docs on report in fridge"}, false},
+		{"short weekday in title", "Buy sun cream", want{title: "Buy sun cream"}, false},
+		{"month in title", "It may rain", want{title: "It may rain"}, false},
+		{"all", "Pay rent every month on the 1st #home !high tomorrow 9am",
+			want{"Pay rent", "2024-03-14", "09:00", []string{"home"}, types.PriorityHigh, "FREQ=MONTHLY;BYMONTHDAY=1"}, false},
+		{"no title", "#home tomorrow", want{}, true},
+		{"empty", "", want{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.text, now)
+			if (err != nil) != tt.wantErr {
+				require.Failf(t, `Parse() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			item := got.Item
+			require.Equal(t, tt.want.title, item.Title)
+			if tt.want.date == "" {
+				require.True(t, item.DueDate.IsZero(), "due date = %s", item.DueDate.String())
+			} else {
+				require.Equal(t, tt.want.date, item.DueDate.String())
+			}
+			require.Equal(t, tt.want.time, item.DueTime)
+			require.Equal(t, tt.want.tags, item.Tags)
+			require.Equal(t, tt.want.priority, item.Priority)
+			if tt.want.recurrence == "" {
+				require.Nil(t, item.Recurrence)
+			} else {
+				require.NotNil(t, item.Recurrence)
+				require.Equal(t, tt.want.recurrence, item.Recurrence.String())
+			}
+
+			item.ID = "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"
+			require.NoError(t, item.Validate())
+		})
+	}
+}
+
+func TestParseTokens(t *testing.T) {
+	now := time.Date(2024, 3, 13, 10, 30, 0, 0, time.UTC)
+
+	got, err := Parse("Pay rent every month on the 1st #Home !high tomorrow 9am", now)
+	require.NoError(t, err)
+	require.Equal(t, []Token{
+		{"every month on the 1st", KindRecurrence, "FREQ=MONTHLY;BYMONTHDAY=1"},
+		{"#Home", KindTag, "home"},
+		{"!high", KindPriority, types.PriorityHigh},
+		{"tomorrow", KindDate, "2024-03-14"},
+		{"9am", KindTime, "09:00"},
+	}, got.Tokens)
+}
+
+func TestParseTimeZone(t *testing.T) {
+	// 2024-03-13 20:00 UTC is already 14th in Seoul
+	now := time.Date(2024, 3, 13, 20, 0, 0, 0, time.UTC)
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	require.NoError(t, err)
+
+	got, err := Parse("Call today", now)
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-13", got.Item.DueDate.String())
+
+	got, err = Parse("Call today", now.In(seoul))
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-14", got.Item.DueDate.String())
+}
@@ -1,9 +1,10 @@
 package badger
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/dgraph-io/badger/v2"
@@ -27,19 +28,23 @@ func New(name string) (Interface, error) {
 	l := &logger{
 		Interface: log.New(),
 	}
-	db, err := badgerx.Open(badger.DefaultOptions(name + ".db").WithLogger(l))
+	s, err := open(badger.DefaultOptions(name + ".db").WithLogger(l))
 	if err != nil {
 		return nil, errors.Wrap(err, "badger.New")
 	}
 
-	ctx, cancel := context.WithCancel(context.TODO())
-	s := &badgerStorage{
-		cancel: cancel,
-		db:     db,
+	return s, nil
+}
+
+// open open storage with badger options
+func open(opts badger.Options) (*badgerStorage, error) {
+	db, err := badgerx.Open(opts)
+	if err != nil {
+		return nil, err
+	}
 
-		userDeleteCh:  make(chan *string),
-		todoDeletedCh: make(chan *string),
-		todoUpdateCh:  make(chan *todoUpdate),
+	s := &badgerStorage{
+		db: db,
 	}
 
 	s.userService = &badgerUserService{
@@ -53,30 +58,169 @@ func New(name string) (Interface, error) {
 	s.todoService = &badgerTodoService{
 		storage: s,
 	}
-	go s.handleUpdates()
 
-	// close() callback
-	go func() {
-		<-ctx.Done()
+	s.listService = &badgerListService{
+		storage: s,
+	}
+
+	s.dependencyService = &badgerDependencyService{
+		storage: s,
+	}
+
+	s.filterService = &badgerFilterService{
+		storage: s,
+	}
+
+	s.searchService = &badgerSearchService{
+		storage: s,
+	}
+
+	s.attachmentService = &badgerAttachmentService{
+		storage: s,
+	}
+
+	s.commentService = &badgerCommentService{
+		storage: s,
+	}
+
+	s.activityService = &badgerActivityService{
+		storage: s,
+	}
 
-		close(s.userDeleteCh)
-		close(s.todoDeletedCh)
-		close(s.todoUpdateCh)
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
 
-		s.db.Close()
-	}()
 	return s, nil
 }
 
-//
+// indexVersion is version of todo item indexes.
+// increase it when todo item index changes, then indexes are rebuilt on open
+const indexVersion = 9
+
+const keyIndexVersion = "/meta/index_version"
+
+// migrate convert refresh tokens of old format, and rebuild todo item indexes if index version changed
+func (s *badgerStorage) migrate() error {
+	if err := s.migrateTokens(); err != nil {
+		return errors.Wrap(err, "migrate tokens")
+	}
+
+	version, err := s.db.GetString(keyIndexVersion)
+	if err != nil && err != badger.ErrKeyNotFound {
+		return err
+	}
+
+	if version == strconv.Itoa(indexVersion) {
+		return nil
+	}
+
+	log.Infof("rebuild todo indexes: version %s --> %d", version, indexVersion)
+
+	return s.reindex()
+}
+
+const keyTokenFormat = "/meta/token_format"
+
+// migrateTokens convert refresh tokens stored as raw string to refreshToken with index by user.
+// owner is taken from issuer of the token, tokens which can not be parsed are deleted
+func (s *badgerStorage) migrateTokens() error {
+	if _, err := s.db.GetString(keyTokenFormat); err != badger.ErrKeyNotFound {
+		return err
+	}
+
+	raw := map[string]string{} // key -> token
+	if err := s.db.Iter(s.tokenService.keyToken(""), func(key string, value []byte) error {
+		if !json.Valid(value) {
+			raw[key] = string(value)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(raw) > 0 {
+		log.Infof("convert %d refresh tokens", len(raw))
+	}
+
+	for key, token := range raw {
+		if err := s.db.UpdateTxn(func(txn *badgerx.Txn) error {
+			// raw value has no indexes to be removed by collection
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+
+			email, err := tokens.Parse(token)
+			if err != nil {
+				return nil
+			}
+
+			return s.tokenService.collection().Set(txn, key, &refreshToken{Token: token, Email: email})
+		}); err != nil {
+			return errors.Wrapf(err, "convert %s", key)
+		}
+	}
+
+	return s.db.SetString(keyTokenFormat, "1")
+}
+
+// reindex drop and rebuild all todo item indexes
+func (s *badgerStorage) reindex() error {
+	for _, prefix := range []string{s.todoService.keyTodoItem("", ""), "/idx/todos/"} {
+		if err := s.db.DropPrefix([]byte(prefix)); err != nil {
+			return err
+		}
+	}
+
+	keys := []string{}
+	if err := s.db.ViewTxn(func(txn *badgerx.Txn) (err error) {
+		keys, err = txn.Keys("/todos/")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		// /todos/{email}/{id}
+		parts := strings.Split(key, "/")
+		if len(parts) != 4 {
+			log.Warnf("invalid todo key: %s", key)
+			continue
+		}
+
+		if err := s.db.UpdateTxn(func(txn *badgerx.Txn) error {
+			var item TodoItem
+			if err := txn.GetJSON(key, &item); err != nil {
+				return err
+			}
+
+			return s.todoService.collection(parts[2]).Set(txn, key, &item)
+		}); err != nil {
+			return errors.Wrapf(err, "reindex %s", key)
+		}
+	}
+
+	return s.db.SetString(keyIndexVersion, strconv.Itoa(indexVersion))
+}
+
+// notFound convert badger not found error to ErrNotFound
+func notFound(err error) error {
+	if err == badger.ErrKeyNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
 // /users/{email} --> User object
-//
 type badgerUserService struct {
 	storage *badgerStorage
 }
 
+func (s *badgerUserService) keyUser(email string) string { return fmt.Sprintf("/users/%s", email) }
+
 func (s *badgerUserService) Create(user *User) error {
-	if err := s.storage.db.SetJSON(fmt.Sprintf("/users/%s", user.Email), user); err != nil {
+	if err := s.storage.db.SetJSON(s.keyUser(user.Email), user); err != nil {
 		return errors.Wrapf(err, "user.Create()")
 	}
 
@@ -86,47 +230,128 @@ func (s *badgerUserService) Create(user *User) error {
 func (s *badgerUserService) Get(email string) (*User, error) {
 	var user User
 
-	if err := s.storage.db.GetJSON(fmt.Sprintf("/users/%s", email), &user); err != nil {
-		return nil, err
+	if err := s.storage.db.GetJSON(s.keyUser(email), &user); err != nil {
+		return nil, notFound(err)
 	}
 
 	return &user, nil
 }
 
+func (s *badgerUserService) Update(user *User) error {
+	return notFound(s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(s.keyUser(user.Email))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(s.keyUser(user.Email), user)
+	}))
+}
+
+// Delete delete user with user's tokens, todo items with attachments, comments and activities, lists, memberships and filters.
+// they are deleted in several transactions not to exceed transaction size of large account;
+// user is deleted last, so failed delete can be retried
 func (s *badgerUserService) Delete(email string) error {
-	if err := s.storage.db.Delete(fmt.Sprintf("/users/%s", email)); err != nil {
-		return errors.Wrapf(err, "delete")
+	if err := s.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(s.keyUser(email))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+		return nil
+	}); err != nil {
+		return notFound(err)
+	}
+
+	if err := s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return s.storage.tokenService.deleteAll(txn, email)
+	}); err != nil {
+		return errors.Wrapf(err, "delete tokens")
 	}
 
-	s.storage.userDeleteCh <- &email
+	if err := s.storage.todoService.deleteAll(email); err != nil {
+		return errors.Wrapf(err, "delete todos")
+	}
 
-	return nil
-}
+	if err := s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return s.storage.listService.deleteAll(txn, email)
+	}); err != nil {
+		return errors.Wrapf(err, "delete lists")
+	}
+
+	if err := s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return s.storage.listService.deleteMemberships(txn, email)
+	}); err != nil {
+		return errors.Wrapf(err, "delete memberships")
+	}
+
+	if err := s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return s.storage.filterService.deleteAll(txn, email)
+	}); err != nil {
+		return errors.Wrapf(err, "delete filters")
+	}
+
+	return notFound(s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(s.keyUser(email))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
 
-type todoUpdate struct {
-	email *string
-	id    *string
+		if err := txn.Delete(s.storage.attachmentService.keyUsage(email)); err != nil {
+			return errors.Wrapf(err, "delete attachment usage")
+		}
+
+		return txn.Delete(s.keyUser(email))
+	}))
 }
 
-//
-//  /todos/all/c9be58c3-e164-42de-a301-8ad3fdbf553b    : all todo object
-//  /todos/{email}/c9be58c3-e164-42de-a301-8ad3fdbf553b  : users todo item
-//
+// /todos/{email}/c9be58c3-e164-42de-a301-8ad3fdbf553b  : users todo item
+// /todos/all/c9be58c3-e164-42de-a301-8ad3fdbf553b      : index of all todo items --> key of users todo item
+// /idx/todos/{email}/{sort}/{sort key}                 : index of user's todo items by sort order --> key of users todo item
+// /idx/todos/{email}/list/{list id}/{id}               : index of user's todo items by list --> key of users todo item
+// /idx/todos/{email}/tag/{escaped tag}/{id}            : index of user's todo items by tag --> key of users todo item
+// /idx/todos/{email}/parent/{parent id}/{id}           : index of user's todo items by parent --> key of users todo item
+// /idx/todos/{email}/search/{word}/{id}                : search index of user's todo items by word --> key of users todo item
+// /lists/{email}/{list id}                             : users list
+// /members/{list id}/{email}                           : member of list
+// /idx/members/{email}/{list id}                       : index of user's memberships --> key of member
+// /dependencies/{email}/{item id}/{depends on id}      : dependency of user's item
+// /idx/dependencies/{email}/{depends on id}/{item id}  : index of dependencies by blocking item --> key of dependency
+// /idx/tokens/{email}/{refresh_token}                  : index of user's tokens --> key of token
+// /filters/{email}/{filter id}                         : user's saved filter
+// /attachments/{email}/{item id}/{attachment id}       : attachment of user's item
+// /idx/attachments/{hash}/{attachment id}              : index of attachments by content hash --> key of attachment
+// /meta/attachments/{email}                            : total size of user's attachments when last created
+// /comments/{email}/{item id}/{comment id}             : comment on user's item
+// /activities/{email}/{item id}/{activity id}          : activity of user's item
+// /meta/index_version                                  : version of todo item indexes
+// /meta/token_format                                   : set when refresh tokens are converted to refreshToken
 type badgerStorage struct {
-	cancel context.CancelFunc
-	db     *badgerx.DB
-
-	userDeleteCh  chan *string
-	todoDeletedCh chan *string
-	todoUpdateCh  chan *todoUpdate
+	db *badgerx.DB
 
 	userService  *badgerUserService
 	tokenService *badgerTokenService
 	todoService  *badgerTodoService
+	listService  *badgerListService
+
+	dependencyService *badgerDependencyService
+	filterService     *badgerFilterService
+	searchService     *badgerSearchService
+	attachmentService *badgerAttachmentService
+	commentService    *badgerCommentService
+	activityService   *badgerActivityService
 }
 
 func (s *badgerStorage) Close() {
-	s.cancel()
+	s.db.Close()
 }
 
 func (s *badgerStorage) UserService() UserService {
@@ -141,122 +366,114 @@ func (s *badgerStorage) TodoService() TodoService {
 	return s.todoService
 }
 
-func (s *badgerStorage) handleUpdates() {
-	go func() {
-		for email := range s.userDeleteCh {
-			// delete user token
-			tokensToDelete := []string{}
-
-			s.db.Iter("/tokens/", func(key string, value []byte) error {
-				issuer, err := tokens.Parse(string(value))
-				if err != nil {
-					tokensToDelete = append(tokensToDelete, string(value))
-					return nil
-				}
-
-				if *email == issuer {
-					tokensToDelete = append(tokensToDelete, string(value))
-					return nil
-				}
-
-				return nil
-			})
+func (s *badgerStorage) ListService() ListService {
+	return s.listService
+}
 
-			for _, t := range tokensToDelete {
-				s.tokenService.Delete(t)
-			}
+func (s *badgerStorage) DependencyService() DependencyService {
+	return s.dependencyService
+}
 
-			// delete user todo
-			ids := []string{}
-			s.db.Iter(fmt.Sprintf("/todos/%s/", *email), func(key string, value []byte) error {
-				var todo TodoItem
-				if err := s.db.GetJSON(key, &todo); err != nil {
-					return err
-				}
-				ids = append(ids, todo.ID)
-				return nil
-			})
-			for _, id := range ids {
-				s.todoService.Delete(*email, id)
-				s.todoService.Delete("", id)
-			}
-		}
-	}()
+func (s *badgerStorage) FilterService() FilterService {
+	return s.filterService
+}
 
-	go func() {
-		for itemID := range s.todoDeletedCh {
-			s.db.Delete(s.todoService.keyTodoItem("", *itemID))
-		}
-	}()
+func (s *badgerStorage) SearchService() SearchService {
+	return s.searchService
+}
 
-	go func() {
-		for updates := range s.todoUpdateCh {
-			var item TodoItem
+func (s *badgerStorage) AttachmentService() AttachmentService {
+	return s.attachmentService
+}
 
-			// sync user todo --> all todo
-			if err := s.db.GetJSON(s.todoService.keyTodoItem(*updates.email, *updates.id), &item); err != nil {
-				log.Errorf("sync update failed: %v", err)
-				continue
-			}
+func (s *badgerStorage) CommentService() CommentService {
+	return s.commentService
+}
 
-			if err := s.db.SetJSON(s.todoService.keyTodoItem("", *updates.id), &item); err != nil {
-				log.Errorf("sync update failed: %v", err)
-			}
-		}
-	}()
+func (s *badgerStorage) ActivityService() ActivityService {
+	return s.activityService
 }
 
-//
 // /tokens/{refresh_tokens} --> RefreshToken object
-//
 type badgerTokenService struct {
 	storage *badgerStorage
 }
 
-func (s *badgerTokenService) Create(email, refreshToken string) error {
-	// check if user exists
-	user, err := s.storage.userService.Get(email)
-	if err != nil {
-		if err == badger.ErrKeyNotFound {
-			user = &User{
-				Email: email,
-			}
+type refreshToken struct {
+	Token string `json:"token"`
+	Email string `json:"email"`
+}
 
-			if err := s.storage.userService.Create(user); err != nil {
-				return err
-			}
-		} else {
+func (s *badgerTokenService) keyToken(token string) string { return fmt.Sprintf("/tokens/%s", token) }
+
+func (s *badgerTokenService) collection() *badgerx.Collection {
+	return &badgerx.Collection{
+		New: func() interface{} { return &refreshToken{} },
+		Indexes: []badgerx.IndexFunc{
+			func(record interface{}) []string {
+				t := record.(*refreshToken)
+				return []string{fmt.Sprintf("/idx/tokens/%s/%s", t.Email, t.Token)}
+			},
+		},
+	}
+}
+
+func (s *badgerTokenService) Create(email, token string) error {
+	return s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		// create user if not exists
+		exists, err := txn.Exists(s.storage.userService.keyUser(email))
+		if err != nil {
 			return err
 		}
-	}
 
-	// create token
-	if err := s.storage.db.SetString(fmt.Sprintf("/tokens/%s", refreshToken), refreshToken); err != nil {
-		return err
-	}
+		if !exists {
+			if err := txn.SetJSON(s.storage.userService.keyUser(email), &User{Email: email}); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return s.collection().Set(txn, s.keyToken(token), &refreshToken{Token: token, Email: email})
+	})
 }
 
 func (s *badgerTokenService) Get(token string) (string, error) {
-	t, err := s.storage.db.GetString(fmt.Sprintf("/tokens/%s", token))
-	if err != nil {
-		return "", err
+	var t refreshToken
+	if err := s.storage.db.GetJSON(s.keyToken(token), &t); err != nil {
+		return "", notFound(err)
 	}
 
-	if t != token {
-		log.Error("token key and data mismatch: key=%s, token=%s", token, t)
+	if t.Token != token {
+		log.Error("token key and data mismatch: key=%s, token=%s", token, t.Token)
 		return "", errors.New("token missmatch")
 	}
 
-	return t, nil
+	return t.Token, nil
 }
 
 func (s *badgerTokenService) Delete(token string) error {
-	if err := s.storage.db.Delete(fmt.Sprintf("/tokens/%s", token)); err != nil {
+	return notFound(s.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return s.collection().Delete(txn, s.keyToken(token))
+	}))
+}
+
+// deleteAll delete all user's tokens
+func (s *badgerTokenService) deleteAll(txn *badgerx.Txn, email string) error {
+	indexKeys, err := txn.Keys(fmt.Sprintf("/idx/tokens/%s/", email))
+	if err != nil {
 		return err
 	}
 
+	for _, indexKey := range indexKeys {
+		key, err := txn.GetString(indexKey)
+		if err != nil {
+			return err
+		}
+
+		if err := s.collection().Delete(txn, key); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -264,74 +481,1310 @@ type badgerTodoService struct {
 	storage *badgerStorage
 }
 
+// keyTodoItem returns key of user's todo item, or all todo item if email is empty
 func (t *badgerTodoService) keyTodoItem(email, id string) string {
 	if email == "" {
-		return "/todos/" + email + "/" + id
-	} else {
 		return "/todos/all/" + id
 	}
-}
 
-func (t *badgerTodoService) List(email string) ([]TodoItem, error) {
-	items := []TodoItem{}
+	return "/todos/" + email + "/" + id
+}
 
-	if err := t.storage.db.Iter(t.keyTodoItem(email, ""), func(key string, value []byte) error {
-		var item TodoItem
+// keySortIndex returns prefix of user's todo item index by sort order
+func (t *badgerTodoService) keySortIndex(email, order string) string {
+	return fmt.Sprintf("/idx/todos/%s/%s/", email, order)
+}
 
-		if err := json.Unmarshal(value, &item); err != nil {
-			return err
-		}
+// keyListIndex returns prefix of user's todo item index by list
+func (t *badgerTodoService) keyListIndex(email, listID string) string {
+	return fmt.Sprintf("/idx/todos/%s/list/%s/", email, listID)
+}
 
-		if !strings.HasSuffix(string(key), "/"+item.ID) {
-			return errors.Errorf("key and value mismatch: key=%s, value=%v", key, item)
-		}
+// keyParentIndex returns prefix of user's todo item index by parent
+func (t *badgerTodoService) keyParentIndex(email, parentID string) string {
+	return fmt.Sprintf("/idx/todos/%s/parent/%s/", email, parentID)
+}
 
-		items = append(items, item)
-		return nil
-	}); err != nil {
-		return nil, err
+// keyTagIndex returns prefix of user's todo item index by tag, or prefix of all tags if tag is empty
+func (t *badgerTodoService) keyTagIndex(email, tag string) string {
+	if tag == "" {
+		return fmt.Sprintf("/idx/todos/%s/tag/", email)
 	}
 
-	return items, nil
+	return fmt.Sprintf("/idx/todos/%s/tag/%s/", email, url.PathEscape(tag))
 }
 
-func (t *badgerTodoService) Create(email string, item *TodoItem) error {
-	if err := t.storage.db.SetJSON(t.keyTodoItem(email, item.ID), item); err != nil {
-		return err
+// keySearchIndex returns prefix of user's todo item search index by word, or by words beginning with it if prefix is true
+func (t *badgerTodoService) keySearchIndex(email, word string, prefix bool) string {
+	if prefix {
+		return fmt.Sprintf("/idx/todos/%s/search/%s", email, word)
 	}
 
-	return nil
+	return fmt.Sprintf("/idx/todos/%s/search/%s/", email, word)
 }
 
-func (t *badgerTodoService) Get(email, itemID string) (*TodoItem, error) {
-	var todo TodoItem
-
-	if err := t.storage.db.GetJSON(t.keyTodoItem(email, itemID), &todo); err != nil {
-		return nil, err
+// collection returns user's todo items with its indexes
+func (t *badgerTodoService) collection(email string) *badgerx.Collection {
+	return &badgerx.Collection{
+		New: func() interface{} { return &TodoItem{} },
+		Indexes: []badgerx.IndexFunc{
+			func(record interface{}) []string { return []string{t.keyTodoItem("", record.(*TodoItem).ID)} },
+			func(record interface{}) []string {
+				keys := []string{}
+				for _, order := range Sorts() {
+					keys = append(keys, t.keySortIndex(email, order)+SortKey(record.(*TodoItem), order))
+				}
+				return keys
+			},
+			func(record interface{}) []string {
+				item := record.(*TodoItem)
+				if item.ListID == "" {
+					return nil
+				}
+				return []string{t.keyListIndex(email, item.ListID) + item.ID}
+			},
+			func(record interface{}) []string {
+				item := record.(*TodoItem)
+				keys := []string{}
+				for _, tag := range item.Tags {
+					keys = append(keys, t.keyTagIndex(email, tag)+item.ID)
+				}
+				return keys
+			},
+			func(record interface{}) []string {
+				item := record.(*TodoItem)
+				if item.ParentID == "" {
+					return nil
+				}
+				return []string{t.keyParentIndex(email, item.ParentID) + item.ID}
+			},
+			func(record interface{}) []string {
+				item := record.(*TodoItem)
+				keys := []string{}
+				for _, word := range SearchTokens(item) {
+					keys = append(keys, t.keySearchIndex(email, word, false)+item.ID)
+				}
+				return keys
+			},
+		},
 	}
-	return &todo, nil
 }
 
-func (t *badgerTodoService) Update(email string, item *TodoItem) error {
-	if err := t.storage.db.SetJSON(t.keyTodoItem(email, item.ID), item); err != nil {
-		return err
+// List iterate sort order index from cursor and stops when page is filled.
+// if parent, tags or list are given, items are looked up by parent, tag or list index instead
+func (t *badgerTodoService) List(email string, opts *ListOptions) ([]TodoItem, string, error) {
+	if opts != nil && opts.ParentID != "" {
+		return t.listByIndex(opts, t.keyParentIndex(email, opts.ParentID))
+	}
+
+	if opts != nil && len(opts.Tags) > 0 {
+		prefixes := []string{}
+		for _, tags := range opts.Tags {
+			prefixes = append(prefixes, t.keyTagIndex(email, tags[0]))
+		}
+		return t.listByIndex(opts, prefixes...)
 	}
 
-	t.storage.todoUpdateCh <- &todoUpdate{&email, &item.ID}
+	if opts != nil && opts.ListID != "" {
+		return t.listByIndex(opts, t.keyListIndex(email, opts.ListID))
+	}
 
-	return nil
+	prefix := t.keySortIndex(email, opts.SortOrder())
+	start := prefix
+
+	if opts != nil && opts.Cursor != "" {
+		key, err := DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start = prefix + key
+	}
+
+	limit := 0
+	if opts != nil {
+		limit = opts.Limit
+	}
+
+	items := []TodoItem{}
+	var next string
+
+	if err := t.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.IterFrom(prefix, start, func(key string, value []byte) error {
+			if key == start && start != prefix {
+				return nil
+			}
+
+			var item TodoItem
+			if err := txn.GetJSON(string(value), &item); err != nil {
+				return errors.Wrapf(err, "index %s", key)
+			}
+
+			if !opts.Match(&item) {
+				return nil
+			}
+
+			if limit > 0 && len(items) == limit {
+				next = EncodeCursor(SortKey(&items[limit-1], opts.SortOrder()))
+				return badgerx.ErrStopIteration
+			}
+
+			items = append(items, item)
+			return nil
+		})
+	}); err != nil {
+		return nil, "", err
+	}
+
+	return items, next, nil
 }
 
-func (t *badgerTodoService) Delete(email string, itemID string) error {
-	if err := t.storage.db.Delete(t.keyTodoItem(email, itemID)); err != nil {
-		if err == badger.ErrKeyNotFound {
-			return ErrNotFound
+// listByIndex returns items of any index prefixes then filter and page them
+func (t *badgerTodoService) listByIndex(opts *ListOptions, prefixes ...string) ([]TodoItem, string, error) {
+	items := []TodoItem{}
+
+	if err := t.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		seen := map[string]bool{}
+		for _, prefix := range prefixes {
+			if err := txn.Iter(prefix, func(key string, value []byte) error {
+				if seen[string(value)] {
+					return nil
+				}
+				seen[string(value)] = true
+
+				var item TodoItem
+				if err := txn.GetJSON(string(value), &item); err != nil {
+					return errors.Wrapf(err, "index %s", key)
+				}
+
+				items = append(items, item)
+				return nil
+			}); err != nil {
+				return err
+			}
 		}
 
-		return err
+		return nil
+	}); err != nil {
+		return nil, "", err
 	}
 
-	t.storage.todoDeletedCh <- &itemID
+	return Page(items, opts)
+}
+
+// Tags count tag index entries
+func (t *badgerTodoService) Tags(email string) ([]Tag, error) {
+	counts := map[string]int{}
+	prefix := t.keyTagIndex(email, "")
+
+	if err := t.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		keys, err := txn.Keys(prefix)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			// {escaped tag}/{id}
+			parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+			if len(parts) != 2 {
+				log.Warnf("invalid tag index: %s", key)
+				continue
+			}
+
+			tag, err := url.PathUnescape(parts[0])
+			if err != nil {
+				return errors.Wrapf(err, "tag index %s", key)
+			}
+			counts[tag]++
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return NewTags(counts), nil
+}
+
+// Get get user's todo item, if email is empty get item from all todo items
+func (t *badgerTodoService) Get(email, itemID string) (*TodoItem, error) {
+	var todo TodoItem
+
+	if err := t.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		if email == "" {
+			return txn.Lookup(t.keyTodoItem("", itemID), &todo)
+		}
+
+		return txn.GetJSON(t.keyTodoItem(email, itemID), &todo)
+	}); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &todo, nil
+}
+
+func (t *badgerTodoService) Create(email string, item *TodoItem) error {
+	return t.write(email, &Operation{Op: OpCreate, Item: item})
+}
+
+func (t *badgerTodoService) Update(email string, item *TodoItem, revision int) error {
+	return t.write(email, &Operation{Op: OpUpdate, Item: item, Revision: revision})
+}
+
+func (t *badgerTodoService) Delete(email string, itemID string, revision int) error {
+	return t.write(email, &Operation{Op: OpDelete, ItemID: itemID, Revision: revision})
+}
+
+func (t *badgerTodoService) write(email string, op *Operation) error {
+	return t.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		if err := t.check(txn, email, op); err != nil {
+			return err
+		}
+
+		return t.apply(txn, email, op)
+	})
+}
+
+// errAborted discards batch transaction
+var errAborted = errors.New("batch aborted")
+
+// Batch apply operations in one write transaction
+func (t *badgerTodoService) Batch(email string, ops []Operation, atomic bool) ([]error, error) {
+	var errs []error
+
+	if err := t.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		errs = make([]error, len(ops)) // transaction could be retried
+
+		for i := range ops {
+			if errs[i] = t.check(txn, email, &ops[i]); errs[i] != nil {
+				continue
+			}
+
+			if err := t.apply(txn, email, &ops[i]); err != nil {
+				return err
+			}
+		}
+
+		if atomic && AbortOnError(errs) {
+			return errAborted
+		}
+
+		return nil
+	}); err != nil && err != errAborted {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// check returns error if operation can not be applied
+func (t *badgerTodoService) check(txn *badgerx.Txn, email string, op *Operation) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+
+	if op.Op == OpCreate {
+		return nil
+	}
+
+	var old TodoItem
+	if err := txn.GetJSON(t.keyTodoItem(email, op.ID()), &old); err != nil {
+		return notFound(err)
+	}
+
+	if !old.MatchRevision(op.Revision) {
+		return ErrConflict
+	}
+
+	if op.Op == OpDelete && op.Children == ChildBlock {
+		children, err := t.children(txn, email, op.ID())
+		if err != nil {
+			return err
+		}
+
+		if len(children) > 0 {
+			return ErrNotEmpty
+		}
+	}
+
+	return nil
+}
+
+// apply write operation to user's items in transaction
+func (t *badgerTodoService) apply(txn *badgerx.Txn, email string, op *Operation) error {
+	key := t.keyTodoItem(email, op.ID())
+	if op.Op == OpDelete {
+		return t.deleteTree(txn, email, op.ID(), op.Children)
+	}
+
+	return t.collection(email).Set(txn, key, op.Item)
+}
+
+// delete delete user's todo item with its dependencies, attachments, comments and activities
+func (t *badgerTodoService) delete(txn *badgerx.Txn, email, itemID string) error {
+	if err := t.collection(email).Delete(txn, t.keyTodoItem(email, itemID)); err != nil {
+		return err
+	}
+
+	if err := t.storage.dependencyService.deleteItem(txn, email, itemID); err != nil {
+		return err
+	}
+
+	if err := t.storage.attachmentService.deleteItem(txn, email, itemID); err != nil {
+		return err
+	}
+
+	if err := deletePrefix(txn, t.storage.commentService.keyComment(email, itemID, "")); err != nil {
+		return err
+	}
+
+	return deletePrefix(txn, t.storage.activityService.keyActivity(email, itemID, ""))
+}
+
+// deleteTree delete user's todo item, its subtasks are deleted on cascade or detached.
+// subtasks on block are checked by check()
+func (t *badgerTodoService) deleteTree(txn *badgerx.Txn, email, itemID, policy string) error {
+	children, err := t.children(txn, email, itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := t.delete(txn, email, itemID); err != nil {
+		return err
+	}
+
+	for i := range children {
+		child := &children[i]
+		if policy == ChildCascade {
+			err = t.deleteTree(txn, email, child.ID, policy)
+		} else {
+			child.Detach()
+			err = t.collection(email).Set(txn, t.keyTodoItem(email, child.ID), child)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// children returns user's subtasks of item by parent index
+func (t *badgerTodoService) children(txn *badgerx.Txn, email, itemID string) ([]TodoItem, error) {
+	indexKeys, err := txn.Keys(t.keyParentIndex(email, itemID))
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]TodoItem, len(indexKeys))
+	for i, indexKey := range indexKeys {
+		key, err := txn.GetString(indexKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := txn.GetJSON(key, &children[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return children, nil
+}
+
+// deleteAll delete all user's todo items, in one transaction per item since every item has many index entries
+func (t *badgerTodoService) deleteAll(email string) error {
+	var keys []string
+	if err := t.storage.db.ViewTxn(func(txn *badgerx.Txn) (err error) {
+		keys, err = txn.Keys(t.keyTodoItem(email, ""))
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := t.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+			return t.delete(txn, email, strings.TrimPrefix(key, t.keyTodoItem(email, "")))
+		}); err != nil && err != badger.ErrKeyNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type badgerListService struct {
+	storage *badgerStorage
+}
+
+func (l *badgerListService) keyList(email, id string) string {
+	return fmt.Sprintf("/lists/%s/%s", email, id)
+}
+
+func (l *badgerListService) Create(email string, list *List) error {
+	return l.storage.db.SetJSON(l.keyList(email, list.ID), list)
+}
+
+func (l *badgerListService) List(email string) ([]List, error) {
+	lists := []List{}
+
+	if err := l.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(l.keyList(email, ""), func(key string, value []byte) error {
+			var list List
+			if err := json.Unmarshal(value, &list); err != nil {
+				return errors.Wrapf(err, "list %s", key)
+			}
+
+			lists = append(lists, list)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortLists(lists)
+
+	return lists, nil
+}
+
+func (l *badgerListService) Get(email, listID string) (*List, error) {
+	var list List
+
+	if err := l.storage.db.GetJSON(l.keyList(email, listID), &list); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &list, nil
+}
+
+func (l *badgerListService) Update(email string, list *List) error {
+	return notFound(l.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(l.keyList(email, list.ID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(l.keyList(email, list.ID), list)
+	}))
+}
+
+// Delete delete list; returns ErrNotEmpty if list has items and not cascade.
+// items of large list do not fit in one transaction, so items are deleted one by one and the list is deleted last
+func (l *badgerListService) Delete(email, listID string, cascade bool) error {
+	todos := l.storage.todoService
+
+	var itemIDs []string
+	if err := l.storage.db.ViewTxn(func(txn *badgerx.Txn) (err error) {
+		exists, err := txn.Exists(l.keyList(email, listID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		itemIDs, err = l.itemIDs(txn, email, listID)
+		return err
+	}); err != nil {
+		return notFound(err)
+	}
+
+	if len(itemIDs) > 0 && !cascade {
+		return ErrNotEmpty
+	}
+
+	for _, itemID := range itemIDs {
+		if err := l.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+			return todos.deleteTree(txn, email, itemID, ChildOrphan)
+		}); err != nil && err != badger.ErrKeyNotFound {
+			return errors.Wrapf(err, "delete item %s", itemID)
+		}
+	}
+
+	return notFound(l.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(l.keyList(email, listID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		itemIDs, err := l.itemIDs(txn, email, listID)
+		if err != nil {
+			return err
+		}
+
+		if len(itemIDs) > 0 && !cascade {
+			return ErrNotEmpty
+		}
+
+		for _, itemID := range itemIDs {
+			if err := todos.deleteTree(txn, email, itemID, ChildOrphan); err != nil {
+				return err
+			}
+		}
+
+		if err := l.deleteMembers(txn, listID); err != nil {
+			return err
+		}
+
+		return txn.Delete(l.keyList(email, listID))
+	}))
+}
+
+// itemIDs returns IDs of items in the list
+func (l *badgerListService) itemIDs(txn *badgerx.Txn, email, listID string) ([]string, error) {
+	todos := l.storage.todoService
+	indexKeys, err := txn.Keys(todos.keyListIndex(email, listID))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(indexKeys))
+	for _, indexKey := range indexKeys {
+		key, err := txn.GetString(indexKey)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, strings.TrimPrefix(key, todos.keyTodoItem(email, "")))
+	}
+
+	return ids, nil
+}
+
+// deleteAll delete all user's lists with their members
+func (l *badgerListService) deleteAll(txn *badgerx.Txn, email string) error {
+	keys, err := txn.Keys(l.keyList(email, ""))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := l.deleteMembers(txn, strings.TrimPrefix(key, l.keyList(email, ""))); err != nil {
+			return err
+		}
+
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *badgerListService) keyMember(listID, email string) string {
+	return fmt.Sprintf("/members/%s/%s", listID, email)
+}
+
+func (l *badgerListService) keyMembership(email string) string {
+	return fmt.Sprintf("/idx/members/%s/", email)
+}
+
+func (l *badgerListService) members() *badgerx.Collection {
+	return &badgerx.Collection{
+		New: func() interface{} { return &Member{} },
+		Indexes: []badgerx.IndexFunc{
+			func(record interface{}) []string {
+				m := record.(*Member)
+				return []string{l.keyMembership(m.Email) + m.ListID}
+			},
+		},
+	}
+}
+
+func (l *badgerListService) SetMember(member *Member) error {
+	return notFound(l.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(l.keyList(member.Owner, member.ListID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return l.members().Set(txn, l.keyMember(member.ListID, member.Email), member)
+	}))
+}
+
+func (l *badgerListService) Members(email, listID string) ([]Member, error) {
+	members := []Member{}
+
+	if err := l.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(l.keyMember(listID, ""), func(key string, value []byte) error {
+			var member Member
+			if err := json.Unmarshal(value, &member); err != nil {
+				return errors.Wrapf(err, "member %s", key)
+			}
+
+			if member.Owner == email {
+				members = append(members, member)
+			}
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortMembers(members)
+
+	return members, nil
+}
+
+func (l *badgerListService) DeleteMember(email, listID, memberEmail string) error {
+	return notFound(l.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		var member Member
+		if err := txn.GetJSON(l.keyMember(listID, memberEmail), &member); err != nil {
+			return err
+		}
+
+		if member.Owner != email {
+			return badger.ErrKeyNotFound
+		}
+
+		return l.members().Delete(txn, l.keyMember(listID, memberEmail))
+	}))
+}
+
+func (l *badgerListService) Memberships(email string) ([]Member, error) {
+	members := []Member{}
+
+	if err := l.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		indexKeys, err := txn.Keys(l.keyMembership(email))
+		if err != nil {
+			return err
+		}
+
+		for _, indexKey := range indexKeys {
+			var member Member
+			if err := txn.Lookup(indexKey, &member); err != nil {
+				return errors.Wrapf(err, "index %s", indexKey)
+			}
+
+			members = append(members, member)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	SortMembers(members)
+
+	return members, nil
+}
+
+// deleteMembers delete all members of list
+func (l *badgerListService) deleteMembers(txn *badgerx.Txn, listID string) error {
+	keys, err := txn.Keys(l.keyMember(listID, ""))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := l.members().Delete(txn, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteMemberships delete all user's memberships
+func (l *badgerListService) deleteMemberships(txn *badgerx.Txn, email string) error {
+	indexKeys, err := txn.Keys(l.keyMembership(email))
+	if err != nil {
+		return err
+	}
+
+	for _, indexKey := range indexKeys {
+		key, err := txn.GetString(indexKey)
+		if err != nil {
+			return err
+		}
+
+		if err := l.members().Delete(txn, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type badgerDependencyService struct {
+	storage *badgerStorage
+}
+
+// keyDependency returns key of dependency, or prefix of dependencies of item if dependsOn is empty
+func (d *badgerDependencyService) keyDependency(email, itemID, dependsOn string) string {
+	return fmt.Sprintf("/dependencies/%s/%s/%s", email, itemID, dependsOn)
+}
+
+// keyBlockingIndex returns prefix of index of dependencies by blocking item
+func (d *badgerDependencyService) keyBlockingIndex(email, dependsOn string) string {
+	return fmt.Sprintf("/idx/dependencies/%s/%s/", email, dependsOn)
+}
+
+func (d *badgerDependencyService) collection(email string) *badgerx.Collection {
+	return &badgerx.Collection{
+		New: func() interface{} { return &Dependency{} },
+		Indexes: []badgerx.IndexFunc{
+			func(record interface{}) []string {
+				dep := record.(*Dependency)
+				return []string{d.keyBlockingIndex(email, dep.DependsOn) + dep.ItemID}
+			},
+		},
+	}
+}
+
+func (d *badgerDependencyService) Add(email string, dep *Dependency) error {
+	return notFound(d.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		for _, id := range []string{dep.ItemID, dep.DependsOn} {
+			exists, err := txn.Exists(d.storage.todoService.keyTodoItem(email, id))
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return badger.ErrKeyNotFound
+			}
+		}
+
+		deps, err := d.list(txn, email)
+		if err != nil {
+			return err
+		}
+
+		if err := CheckDependency(deps, dep); err != nil {
+			return err
+		}
+
+		return d.collection(email).Set(txn, d.keyDependency(email, dep.ItemID, dep.DependsOn), dep)
+	}))
+}
+
+func (d *badgerDependencyService) List(email string) ([]Dependency, error) {
+	var deps []Dependency
+
+	if err := d.storage.db.ViewTxn(func(txn *badgerx.Txn) (err error) {
+		deps, err = d.list(txn, email)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// list returns user's dependencies, keys are ordered by item ID and blocking item ID
+func (d *badgerDependencyService) list(txn *badgerx.Txn, email string) ([]Dependency, error) {
+	deps := []Dependency{}
+
+	if err := txn.Iter(fmt.Sprintf("/dependencies/%s/", email), func(key string, value []byte) error {
+		var dep Dependency
+		if err := json.Unmarshal(value, &dep); err != nil {
+			return errors.Wrapf(err, "dependency %s", key)
+		}
+
+		deps = append(deps, dep)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+func (d *badgerDependencyService) Delete(email string, dep *Dependency) error {
+	return notFound(d.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return d.collection(email).Delete(txn, d.keyDependency(email, dep.ItemID, dep.DependsOn))
+	}))
+}
+
+// deleteItem delete dependencies of the item and dependencies on the item
+func (d *badgerDependencyService) deleteItem(txn *badgerx.Txn, email, itemID string) error {
+	keys, err := txn.Keys(d.keyDependency(email, itemID, ""))
+	if err != nil {
+		return err
+	}
+
+	indexKeys, err := txn.Keys(d.keyBlockingIndex(email, itemID))
+	if err != nil {
+		return err
+	}
+
+	for _, indexKey := range indexKeys {
+		key, err := txn.GetString(indexKey)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		if err := d.collection(email).Delete(txn, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type badgerFilterService struct {
+	storage *badgerStorage
+}
+
+func (f *badgerFilterService) keyFilter(email, id string) string {
+	return fmt.Sprintf("/filters/%s/%s", email, id)
+}
+
+func (f *badgerFilterService) Create(email string, filter *Filter) error {
+	return f.storage.db.SetJSON(f.keyFilter(email, filter.ID), filter)
+}
+
+func (f *badgerFilterService) List(email string) ([]Filter, error) {
+	filters := []Filter{}
+
+	if err := f.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(f.keyFilter(email, ""), func(key string, value []byte) error {
+			var filter Filter
+			if err := json.Unmarshal(value, &filter); err != nil {
+				return errors.Wrapf(err, "filter %s", key)
+			}
+
+			filters = append(filters, filter)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortFilters(filters)
+
+	return filters, nil
+}
+
+func (f *badgerFilterService) Get(email, filterID string) (*Filter, error) {
+	var filter Filter
+
+	if err := f.storage.db.GetJSON(f.keyFilter(email, filterID), &filter); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &filter, nil
+}
+
+func (f *badgerFilterService) Update(email string, filter *Filter) error {
+	return notFound(f.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(f.keyFilter(email, filter.ID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(f.keyFilter(email, filter.ID), filter)
+	}))
+}
+
+func (f *badgerFilterService) Delete(email, filterID string) error {
+	return notFound(f.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(f.keyFilter(email, filterID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.Delete(f.keyFilter(email, filterID))
+	}))
+}
+
+// deleteAll delete all user's filters
+func (f *badgerFilterService) deleteAll(txn *badgerx.Txn, email string) error {
+	keys, err := txn.Keys(f.keyFilter(email, ""))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// badgerSearchService looks up candidate items by search index of todo items
+type badgerSearchService struct {
+	storage *badgerStorage
+}
+
+// Search collects items which have words beginning with every term, then scores them
+func (s *badgerSearchService) Search(email string, query string, limit int) ([]SearchResult, error) {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	todos := s.storage.todoService
+	items := []TodoItem{}
+
+	if err := s.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		var candidates map[string]bool
+		for _, term := range terms {
+			indexKeys, err := txn.Keys(todos.keySearchIndex(email, term, true))
+			if err != nil {
+				return err
+			}
+
+			matched := map[string]bool{}
+			for _, indexKey := range indexKeys {
+				// {word}/{id}
+				id := indexKey[strings.LastIndex(indexKey, "/")+1:]
+				if candidates == nil || candidates[id] {
+					matched[id] = true
+				}
+			}
+
+			candidates = matched
+			if len(candidates) == 0 {
+				return nil
+			}
+		}
+
+		for id := range candidates {
+			var item TodoItem
+			if err := txn.GetJSON(todos.keyTodoItem(email, id), &item); err != nil {
+				return errors.Wrapf(err, "item %s", id)
+			}
+
+			items = append(items, item)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return Search(items, terms, limit), nil
+}
+
+// Rebuild rebuild all todo item indexes with search index
+func (s *badgerSearchService) Rebuild() error {
+	log.Infof("rebuild todo indexes")
+
+	return s.storage.reindex()
+}
+
+type badgerAttachmentService struct {
+	storage *badgerStorage
+}
+
+// keyAttachment returns key of attachment, or prefix of attachments of item if id is empty
+func (a *badgerAttachmentService) keyAttachment(email, itemID, id string) string {
+	return fmt.Sprintf("/attachments/%s/%s/%s", email, itemID, id)
+}
+
+// keyHashIndex returns prefix of index of attachments by content hash
+func (a *badgerAttachmentService) keyHashIndex(hash string) string {
+	return fmt.Sprintf("/idx/attachments/%s/", hash)
+}
+
+// keyUsage returns key of user's usage, which is written with every attachment of user
+// so that concurrent creates conflict and quota is not exceeded
+func (a *badgerAttachmentService) keyUsage(email string) string {
+	return fmt.Sprintf("/meta/attachments/%s", email)
+}
+
+func (a *badgerAttachmentService) collection() *badgerx.Collection {
+	return &badgerx.Collection{
+		New: func() interface{} { return &Attachment{} },
+		Indexes: []badgerx.IndexFunc{
+			func(record interface{}) []string {
+				attachment := record.(*Attachment)
+				return []string{a.keyHashIndex(attachment.Hash) + attachment.ID}
+			},
+		},
+	}
+}
+
+func (a *badgerAttachmentService) Create(email string, attachment *Attachment, quota int64) error {
+	return notFound(a.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(a.storage.todoService.keyTodoItem(email, attachment.ItemID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		if _, err := txn.Exists(a.keyUsage(email)); err != nil {
+			return err
+		}
+
+		usage, err := a.usage(txn, email)
+		if err != nil {
+			return err
+		}
+
+		if err := CheckQuota(usage, attachment.Size, quota); err != nil {
+			return err
+		}
+
+		if err := txn.SetString(a.keyUsage(email), strconv.FormatInt(usage+attachment.Size, 10)); err != nil {
+			return err
+		}
+
+		return a.collection().Set(txn, a.keyAttachment(email, attachment.ItemID, attachment.ID), attachment)
+	}))
+}
+
+func (a *badgerAttachmentService) List(email, itemID string) ([]Attachment, error) {
+	attachments := []Attachment{}
+
+	if err := a.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(a.keyAttachment(email, itemID, ""), func(key string, value []byte) error {
+			var attachment Attachment
+			if err := json.Unmarshal(value, &attachment); err != nil {
+				return errors.Wrapf(err, "attachment %s", key)
+			}
+
+			attachments = append(attachments, attachment)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortAttachments(attachments)
+
+	return attachments, nil
+}
+
+func (a *badgerAttachmentService) Get(email, itemID, attachmentID string) (*Attachment, error) {
+	var attachment Attachment
+
+	if err := a.storage.db.GetJSON(a.keyAttachment(email, itemID, attachmentID), &attachment); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &attachment, nil
+}
+
+func (a *badgerAttachmentService) Delete(email, itemID, attachmentID string) error {
+	return notFound(a.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		return a.collection().Delete(txn, a.keyAttachment(email, itemID, attachmentID))
+	}))
+}
+
+func (a *badgerAttachmentService) Usage(email string) (int64, error) {
+	var usage int64
+
+	if err := a.storage.db.ViewTxn(func(txn *badgerx.Txn) (err error) {
+		usage, err = a.usage(txn, email)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+// usage sum sizes of user's attachments
+func (a *badgerAttachmentService) usage(txn *badgerx.Txn, email string) (int64, error) {
+	var usage int64
+
+	if err := txn.Iter(fmt.Sprintf("/attachments/%s/", email), func(key string, value []byte) error {
+		var attachment Attachment
+		if err := json.Unmarshal(value, &attachment); err != nil {
+			return errors.Wrapf(err, "attachment %s", key)
+		}
+
+		usage += attachment.Size
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+func (a *badgerAttachmentService) Referenced(hash string) (bool, error) {
+	var referenced bool
+
+	if err := a.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		keys, err := txn.Keys(a.keyHashIndex(hash))
+		referenced = len(keys) > 0
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	return referenced, nil
+}
+
+// deleteItem delete attachments of the item
+func (a *badgerAttachmentService) deleteItem(txn *badgerx.Txn, email, itemID string) error {
+	keys, err := txn.Keys(a.keyAttachment(email, itemID, ""))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := a.collection().Delete(txn, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type badgerCommentService struct {
+	storage *badgerStorage
+}
+
+// keyComment returns key of comment, or prefix of comments of item if id is empty
+func (c *badgerCommentService) keyComment(email, itemID, id string) string {
+	return fmt.Sprintf("/comments/%s/%s/%s", email, itemID, id)
+}
+
+func (c *badgerCommentService) Create(email string, comment *Comment) error {
+	return notFound(c.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(c.storage.todoService.keyTodoItem(email, comment.ItemID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(c.keyComment(email, comment.ItemID, comment.ID), comment)
+	}))
+}
+
+func (c *badgerCommentService) List(email, itemID string) ([]Comment, error) {
+	comments := []Comment{}
+
+	if err := c.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(c.keyComment(email, itemID, ""), func(key string, value []byte) error {
+			var comment Comment
+			if err := json.Unmarshal(value, &comment); err != nil {
+				return errors.Wrapf(err, "comment %s", key)
+			}
+
+			comments = append(comments, comment)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortComments(comments)
+
+	return comments, nil
+}
+
+func (c *badgerCommentService) Get(email, itemID, commentID string) (*Comment, error) {
+	var comment Comment
+
+	if err := c.storage.db.GetJSON(c.keyComment(email, itemID, commentID), &comment); err != nil {
+		return nil, notFound(err)
+	}
+
+	return &comment, nil
+}
+
+func (c *badgerCommentService) Update(email string, comment *Comment) error {
+	return notFound(c.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(c.keyComment(email, comment.ItemID, comment.ID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(c.keyComment(email, comment.ItemID, comment.ID), comment)
+	}))
+}
+
+func (c *badgerCommentService) Delete(email, itemID, commentID string) error {
+	return notFound(c.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(c.keyComment(email, itemID, commentID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.Delete(c.keyComment(email, itemID, commentID))
+	}))
+}
+
+type badgerActivityService struct {
+	storage *badgerStorage
+}
+
+// keyActivity returns key of activity, or prefix of activities of item if id is empty
+func (a *badgerActivityService) keyActivity(email, itemID, id string) string {
+	return fmt.Sprintf("/activities/%s/%s/%s", email, itemID, id)
+}
+
+func (a *badgerActivityService) Add(email string, activity *Activity) error {
+	return notFound(a.storage.db.UpdateTxn(func(txn *badgerx.Txn) error {
+		exists, err := txn.Exists(a.storage.todoService.keyTodoItem(email, activity.ItemID))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return badger.ErrKeyNotFound
+		}
+
+		return txn.SetJSON(a.keyActivity(email, activity.ItemID, activity.ID), activity)
+	}))
+}
+
+func (a *badgerActivityService) List(email, itemID string) ([]Activity, error) {
+	activities := []Activity{}
+
+	if err := a.storage.db.ViewTxn(func(txn *badgerx.Txn) error {
+		return txn.Iter(a.keyActivity(email, itemID, ""), func(key string, value []byte) error {
+			var activity Activity
+			if err := json.Unmarshal(value, &activity); err != nil {
+				return errors.Wrapf(err, "activity %s", key)
+			}
+
+			activities = append(activities, activity)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	SortActivities(activities)
+
+	return activities, nil
+}
+
+// deletePrefix delete all keys which have the prefix
+func deletePrefix(txn *badgerx.Txn, prefix string) error {
+	keys, err := txn.Keys(prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
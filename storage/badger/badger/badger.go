@@ -5,8 +5,15 @@ import (
 	"encoding/json"
 
 	"github.com/dgraph-io/badger/v2"
+	"github.com/pkg/errors"
 )
 
+// ErrStopIteration stops iteration without error
+var ErrStopIteration = errors.New("stop iteration")
+
+// maxConflictRetry is number of retry when transaction conflicts
+const maxConflictRetry = 5
+
 func Open(opt badger.Options) (*DB, error) {
 	db, err := badger.Open(opt)
 	if err != nil {
@@ -24,114 +31,156 @@ type DB struct {
 	*badger.DB
 }
 
-func (db *DB) GetString(key string) (value string, err error) {
-	if err := db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(key))
-		if err != nil {
-			return err
-		}
+// ViewTxn run read-only transaction
+func (db *DB) ViewTxn(fn func(txn *Txn) error) error {
+	return db.View(func(txn *badger.Txn) error {
+		return fn(&Txn{txn})
+	})
+}
 
-		if err := item.Value(func(val []byte) error {
-			value = string(val)
-			return nil
-		}); err != nil {
+// UpdateTxn run read-write transaction, transaction is retried when conflicts
+func (db *DB) UpdateTxn(fn func(txn *Txn) error) (err error) {
+	for i := 0; i < maxConflictRetry; i++ {
+		err = db.Update(func(txn *badger.Txn) error {
+			return fn(&Txn{txn})
+		})
+		if err != badger.ErrConflict {
 			return err
 		}
-		return nil
-	}); err != nil {
-		return "", err
 	}
-	return value, nil
-}
 
-func (db *DB) GetJSON(key string, data interface{}) error {
-	if err := db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(key))
-		if err != nil {
-			return err
-		}
+	return err
+}
 
-		if err := item.Value(func(val []byte) error {
-			return json.Unmarshal(val, data)
-		}); err != nil {
-			return err
-		}
-		return nil
-	}); err != nil {
+func (db *DB) GetString(key string) (value string, err error) {
+	err = db.ViewTxn(func(txn *Txn) error {
+		value, err = txn.GetString(key)
 		return err
-	}
-	return nil
+	})
+	return value, err
+}
+
+func (db *DB) GetJSON(key string, data interface{}) error {
+	return db.ViewTxn(func(txn *Txn) error { return txn.GetJSON(key, data) })
 }
 
 func (db *DB) SetString(key string, s string) error {
-	if err := db.Update(func(txn *badger.Txn) error {
-		if err := txn.Set([]byte(key), []byte(s)); err != nil {
-			return err
-		}
+	return db.UpdateTxn(func(txn *Txn) error { return txn.SetString(key, s) })
+}
+
+func (db *DB) SetJSON(key string, data interface{}) error {
+	return db.UpdateTxn(func(txn *Txn) error { return txn.SetJSON(key, data) })
+}
 
+func (db *DB) Delete(key string) error {
+	return db.UpdateTxn(func(txn *Txn) error { return txn.Delete(key) })
+}
+
+func (db *DB) Iter(prefix string, onItem func(key string, vale []byte) error) error {
+	return db.ViewTxn(func(txn *Txn) error { return txn.Iter(prefix, onItem) })
+}
+
+// Txn is badger transaction with handy helpers
+type Txn struct {
+	*badger.Txn
+}
+
+func (txn *Txn) GetString(key string) (value string, err error) {
+	item, err := txn.Get([]byte(key))
+	if err != nil {
+		return "", err
+	}
+
+	if err := item.Value(func(val []byte) error {
+		value = string(val)
 		return nil
 	}); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (txn *Txn) GetJSON(key string, data interface{}) error {
+	item, err := txn.Get([]byte(key))
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return item.Value(func(val []byte) error {
+		return json.Unmarshal(val, data)
+	})
 }
 
-func (db *DB) SetJSON(key string, data interface{}) error {
-	if err := db.Update(func(txn *badger.Txn) error {
-		value, err := json.Marshal(data)
-		if err != nil {
-			return err
+// Exists returns true if key exists
+func (txn *Txn) Exists(key string) (bool, error) {
+	if _, err := txn.Get([]byte(key)); err != nil {
+		if err == badger.ErrKeyNotFound {
+			return false, nil
 		}
+		return false, err
+	}
 
-		if err := txn.Set([]byte(key), value); err != nil {
-			return err
-		}
+	return true, nil
+}
 
-		return nil
-	}); err != nil {
+func (txn *Txn) SetString(key string, s string) error {
+	return txn.Set([]byte(key), []byte(s))
+}
+
+func (txn *Txn) SetJSON(key string, data interface{}) error {
+	value, err := json.Marshal(data)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return txn.Set([]byte(key), value)
 }
 
-func (db *DB) Delete(key string) error {
-	if err := db.DB.Update(func(txn *badger.Txn) error {
-		if err := txn.Delete([]byte(key)); err != nil {
+func (txn *Txn) Delete(key string) error {
+	return txn.Txn.Delete([]byte(key))
+}
+
+// Iter iterate items which has prefix
+func (txn *Txn) Iter(prefix string, onItem func(key string, vale []byte) error) error {
+	return txn.IterFrom(prefix, prefix, onItem)
+}
+
+// IterFrom iterate items which has prefix, starting from start key.
+// return ErrStopIteration from onItem to stop iteration
+func (txn *Txn) IterFrom(prefix, start string, onItem func(key string, vale []byte) error) error {
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	for it.Seek([]byte(start)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+		item := it.Item()
+		key := string(item.Key())
+
+		if err := item.Value(func(v []byte) error {
+			return onItem(key, v)
+		}); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
 			return err
 		}
-
-		return nil
-	}); err != nil {
-		return err
 	}
 
 	return nil
 }
 
-func (db *DB) Iter(prefix string, onItem func(key string, vale []byte) error) error {
-	if err := db.View(func(txn *badger.Txn) error {
-		it := txn.NewIterator(badger.DefaultIteratorOptions)
-		defer it.Close()
-
-		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
-			item := it.Item()
-			key := string(item.Key())
-
-			if err := item.Value(func(v []byte) error {
-				return onItem(key, v)
-			}); err != nil {
-				return err
-			}
+// Keys returns keys which has prefix
+func (txn *Txn) Keys(prefix string) ([]string, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
 
-			return nil
-		}
+	it := txn.NewIterator(opts)
+	defer it.Close()
 
-		return nil
-	}); err != nil {
-		return err
+	keys := []string{}
+	for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+		keys = append(keys, string(it.Item().Key()))
 	}
 
-	return nil
+	return keys, nil
 }
@@ -0,0 +1,92 @@
+package badgeer
+
+import (
+	"github.com/dgraph-io/badger/v2"
+)
+
+// IndexFunc returns secondary index keys of record
+type IndexFunc func(record interface{}) []string
+
+// Collection is set of json records with secondary indexes.
+// record and its index entries are written in same transaction,
+// so reading from either primary or index keyspace is always consistent.
+//
+// value of index entry is the primary key of record.
+type Collection struct {
+	New     func() interface{} // returns empty record to decode stored one
+	Indexes []IndexFunc
+}
+
+func (c *Collection) indexKeys(record interface{}) []string {
+	keys := []string{}
+	for _, index := range c.Indexes {
+		keys = append(keys, index(record)...)
+	}
+	return keys
+}
+
+// Set write record and its index entries, stale index entries of previous record are removed
+func (c *Collection) Set(txn *Txn, key string, record interface{}) error {
+	if err := c.deleteIndex(txn, key); err != nil {
+		return err
+	}
+
+	if err := txn.SetJSON(key, record); err != nil {
+		return err
+	}
+
+	for _, indexKey := range c.indexKeys(record) {
+		if err := txn.SetString(indexKey, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete delete record and its index entries
+// returns badger.ErrKeyNotFound if record not exists
+func (c *Collection) Delete(txn *Txn, key string) error {
+	exists, err := txn.Exists(key)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return badger.ErrKeyNotFound
+	}
+
+	if err := c.deleteIndex(txn, key); err != nil {
+		return err
+	}
+
+	return txn.Delete(key)
+}
+
+func (c *Collection) deleteIndex(txn *Txn, key string) error {
+	old := c.New()
+	if err := txn.GetJSON(key, old); err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		return err
+	}
+
+	for _, indexKey := range c.indexKeys(old) {
+		if err := txn.Delete(indexKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup get record by index key
+func (txn *Txn) Lookup(indexKey string, record interface{}) error {
+	key, err := txn.GetString(indexKey)
+	if err != nil {
+		return err
+	}
+
+	return txn.GetJSON(key, record)
+}
@@ -0,0 +1,56 @@
+package badgeer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCollection(t *testing.T) {
+	db, err := Open(badger.DefaultOptions(filepath.Join(t.TempDir(), "test.db")).WithLogger(nil))
+	require.NoError(t, err)
+	defer db.Close()
+
+	c := &Collection{
+		New: func() interface{} { return &record{} },
+		Indexes: []IndexFunc{
+			func(r interface{}) []string { return []string{"/idx/name/" + r.(*record).Name + "/" + r.(*record).ID} },
+		},
+	}
+
+	keys := func(prefix string) []string {
+		var keys []string
+		require.NoError(t, db.ViewTxn(func(txn *Txn) (err error) {
+			keys, err = txn.Keys(prefix)
+			return err
+		}))
+		return keys
+	}
+
+	require.NoError(t, db.UpdateTxn(func(txn *Txn) error {
+		return c.Set(txn, "/records/1", &record{ID: "1", Name: "hello"})
+	}))
+	require.Equal(t, []string{"/idx/name/hello/1"}, keys("/idx/"))
+
+	var got record
+	require.NoError(t, db.ViewTxn(func(txn *Txn) error { return txn.Lookup("/idx/name/hello/1", &got) }))
+	require.Equal(t, record{ID: "1", Name: "hello"}, got)
+
+	// stale index entry should be removed
+	require.NoError(t, db.UpdateTxn(func(txn *Txn) error {
+		return c.Set(txn, "/records/1", &record{ID: "1", Name: "world"})
+	}))
+	require.Equal(t, []string{"/idx/name/world/1"}, keys("/idx/"))
+
+	require.NoError(t, db.UpdateTxn(func(txn *Txn) error { return c.Delete(txn, "/records/1") }))
+	require.Equal(t, []string{}, keys("/"))
+
+	require.Equal(t, badger.ErrKeyNotFound, db.UpdateTxn(func(txn *Txn) error { return c.Delete(txn, "/records/1") }))
+}
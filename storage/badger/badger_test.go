@@ -1,53 +1,36 @@
 package badger
 
 import (
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/dgraph-io/badger/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/storagetest"
 	. "github.com/whitekid/go-todo/storage/types"
-	"github.com/whitekid/go-utils/fixtures"
+	"github.com/whitekid/go-todo/tokens"
 )
 
-func storageFixture(callbacks ...func(Interface)) func() {
-	var dir string
-	defer fixtures.TempDir("testdb", "test_", func(tempDir string) { dir = tempDir })()
-
-	s, err := New(dir)
-	if err != nil {
-		panic(err)
-	}
-
-	for _, callback := range callbacks {
-		callback(s)
-	}
-
-	return func() {
-		defer s.Close()
-	}
-}
-
 func TestBadger(t *testing.T) {
-	var dir string
-	defer fixtures.TempDir(".", "testdb_", func(tempDir string) { dir = tempDir })()
-
-	s, err := New(dir)
-	defer s.Close()
+	s, err := New(filepath.Join(t.TempDir(), "todo"))
 	require.NoError(t, err)
+	defer s.Close()
 
 	todos := s.TodoService()
-	tokens := s.TokenService()
 
 	email := "whitekid@gmail.com"
 
-	refreshToken, err := tokens.Create(email)
+	refreshToken, err := tokens.New(email, time.Minute)
 	require.NoError(t, err)
-	require.Equal(t, email, refreshToken.Email)
+	require.NoError(t, s.TokenService().Create(email, refreshToken))
 
 	{
-		got, err := tokens.Get(refreshToken.Token)
+		got, err := s.TokenService().Get(refreshToken)
 		require.NoError(t, err)
-		require.Equal(t, got.Email, email)
+		require.Equal(t, refreshToken, got)
 	}
 
 	item := TodoItem{
@@ -56,7 +39,7 @@ func TestBadger(t *testing.T) {
 	}
 	require.NoError(t, todos.Create(email, &item))
 
-	items, err := todos.List(email)
+	items, _, err := todos.List(email, nil)
 	require.NoError(t, err)
 	require.Equal(t, []TodoItem{item}, items)
 
@@ -65,16 +48,121 @@ func TestBadger(t *testing.T) {
 	require.Equal(t, &item, got)
 
 	got.Title = "updated"
-	require.NoError(t, todos.Update(email, got))
+	require.NoError(t, todos.Update(email, got, 0))
 
-	require.NoError(t, todos.Delete(email, got.ID))
+	require.NoError(t, todos.Delete(email, got.ID, 0))
 	if _, err := todos.Get(email, item.ID); err == nil {
 		require.Fail(t, "should error", "want = %v, got = %v", ErrNotFound, err)
 	}
 
 	{
-		items, err := todos.List(email)
+		items, _, err := todos.List(email, nil)
 		require.NoError(t, err)
 		require.Equal(t, 0, len(items))
 	}
 }
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) Interface {
+		s, err := New(filepath.Join(t.TempDir(), "todo"))
+		require.NoError(t, err)
+		return s
+	})
+}
+
+func TestAllIndex(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "todo"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	todos := s.TodoService()
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.UserService().Create(&User{Email: email}))
+
+	item := TodoItem{ID: uuid.New().String(), Title: "title"}
+	require.NoError(t, todos.Create(email, &item))
+
+	// all todo item index should be consistent with user todo item right after write
+	got, err := todos.Get("", item.ID)
+	require.NoError(t, err)
+	require.Equal(t, &item, got)
+
+	item.Title = "updated"
+	require.NoError(t, todos.Update(email, &item, 0))
+	got, err = todos.Get("", item.ID)
+	require.NoError(t, err)
+	require.Equal(t, &item, got)
+
+	require.NoError(t, todos.Delete(email, item.ID, 0))
+	_, err = todos.Get("", item.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, todos.Create(email, &item))
+	require.NoError(t, s.UserService().Delete(email))
+	_, err = todos.Get("", item.ID)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestMigrateTokens(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "todo")
+	s, err := New(name)
+	require.NoError(t, err)
+
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.UserService().Create(&User{Email: email}))
+
+	// refresh tokens were stored as raw string
+	refreshToken, err := tokens.New(email, time.Minute)
+	require.NoError(t, err)
+	db := s.(*badgerStorage).db
+	require.NoError(t, db.SetString("/tokens/"+refreshToken, refreshToken))
+	require.NoError(t, db.SetString("/tokens/invalid", "invalid"))
+	require.NoError(t, db.Delete(keyTokenFormat))
+	s.Close()
+
+	s, err = New(name)
+	require.NoError(t, err)
+	defer s.Close()
+
+	got, err := s.TokenService().Get(refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, refreshToken, got)
+
+	_, err = s.TokenService().Get("invalid")
+	require.Equal(t, ErrNotFound, err)
+
+	// converted tokens are deleted with user
+	require.NoError(t, s.UserService().Delete(email))
+	_, err = s.TokenService().Get(refreshToken)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestDeleteLargeList(t *testing.T) {
+	// small table makes transaction too big with hundreds of items
+	s, err := open(badger.DefaultOptions(filepath.Join(t.TempDir(), "todo.db")).WithLogger(nil).
+		WithMaxTableSize(1 << 20).WithSyncWrites(false))
+	require.NoError(t, err)
+	defer s.Close()
+
+	email := "whitekid@gmail.com"
+	refreshToken, err := tokens.New(email, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, s.TokenService().Create(email, refreshToken))
+
+	list := List{ID: uuid.New().String(), Name: "large"}
+	require.NoError(t, s.ListService().Create(email, &list))
+
+	for i := 0; i < 500; i++ {
+		item := TodoItem{ID: uuid.New().String(), Title: fmt.Sprintf("item %d", i), ListID: list.ID}
+		require.NoError(t, s.TodoService().Create(email, &item))
+	}
+
+	require.NoError(t, s.ListService().Delete(email, list.ID, true))
+
+	_, err = s.ListService().Get(email, list.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	items, _, err := s.TodoService().List(email, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+}
@@ -0,0 +1,126 @@
+// Package blob stores contents of attachments by their hash, so same content is stored once
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+)
+
+// BlobStore stores contents addressed by SHA-256 hash of the content
+type BlobStore interface {
+	// store content and returns its hash and size; content which is already stored is not stored again,
+	// but its modified time is updated
+	Put(r io.Reader) (hash string, size int64, err error)
+
+	// return ErrNotFound if blob not found
+	Open(hash string) (io.ReadCloser, error)
+
+	// return ErrNotFound if blob not found
+	Stat(hash string) (*Blob, error)
+
+	// list all blobs, ordered by hash
+	List() ([]Blob, error)
+
+	// deleting blob which is not found does nothing
+	Delete(hash string) error
+}
+
+// Blob is stored content
+type Blob struct {
+	Hash    string
+	Size    int64
+	ModTime time.Time // when the content is stored last time
+}
+
+// Hash returns hash of content as stored
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash returns true if hash is hex encoded SHA-256 hash, to prevent path traversal by hash
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+// Sweep delete blobs which are not referenced, and returns number of deleted blobs.
+// blobs stored in grace period are kept, because their references may not be written yet.
+// if hashes are given, only the blobs are checked instead of all blobs
+func Sweep(store BlobStore, referenced func(hash string) (bool, error), grace time.Duration, hashes ...string) (int, error) {
+	var blobs []Blob
+	if len(hashes) == 0 {
+		var err error
+		if blobs, err = store.List(); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, hash := range hashes {
+		b, err := store.Stat(hash)
+		if err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			return 0, err
+		}
+		blobs = append(blobs, *b)
+	}
+
+	deleted := 0
+	for _, b := range blobs {
+		if time.Since(b.ModTime) < grace {
+			continue
+		}
+
+		// the blob may be stored again or referenced after listed, so check it again just before deleting
+		ok, err := unused(store, referenced, grace, b.Hash)
+		if err != nil {
+			return deleted, err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := store.Delete(b.Hash); err != nil {
+			return deleted, errors.Wrapf(err, "delete %s", b.Hash)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// unused returns true if the blob is stored before grace period and not referenced
+func unused(store BlobStore, referenced func(hash string) (bool, error), grace time.Duration, hash string) (bool, error) {
+	b, err := store.Stat(hash)
+	if err != nil {
+		if err == ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if time.Since(b.ModTime) < grace {
+		return false, nil
+	}
+
+	ok, err := referenced(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return !ok, nil
+}
@@ -0,0 +1,119 @@
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	hash, size, err := store.Put(strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, Hash([]byte("hello")), hash)
+	require.Equal(t, int64(5), size)
+
+	// same content is stored once
+	again, _, err := store.Put(strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, hash, again)
+
+	other, _, err := store.Put(strings.NewReader("world"))
+	require.NoError(t, err)
+
+	blobs, err := store.List()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(blobs))
+
+	r, err := store.Open(hash)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(other))
+	require.NoError(t, store.Delete(other))
+	_, err = store.Open(other)
+	require.Equal(t, ErrNotFound, err)
+	_, err = store.Stat(other)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = store.Open("../../etc/passwd")
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestSweep(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	used, _, err := store.Put(strings.NewReader("used"))
+	require.NoError(t, err)
+	unused, _, err := store.Put(strings.NewReader("unused"))
+	require.NoError(t, err)
+
+	referenced := func(hash string) (bool, error) { return hash == used, nil }
+
+	// blobs in grace period are kept
+	n, err := Sweep(store, referenced, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = Sweep(store, referenced, 0, used)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = Sweep(store, referenced, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	_, err = store.Stat(unused)
+	require.Equal(t, ErrNotFound, err)
+	_, err = store.Stat(used)
+	require.NoError(t, err)
+}
+
+func TestSweepStoredAgain(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocal(dir)
+	require.NoError(t, err)
+
+	contents := map[string]string{}
+	for _, content := range []string{"first", "second"} {
+		hash, _, err := store.Put(strings.NewReader(content))
+		require.NoError(t, err)
+		contents[hash] = content
+
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, hash[:2], hash), old, old))
+	}
+
+	// while checking the first blob, the other blob is stored again as being attached
+	var again string
+	referenced := func(hash string) (bool, error) {
+		if again == "" {
+			for other, content := range contents {
+				if other != hash {
+					again = other
+					_, _, err := store.Put(strings.NewReader(content))
+					require.NoError(t, err)
+				}
+			}
+		}
+		return false, nil
+	}
+
+	n, err := Sweep(store, referenced, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	_, err = store.Stat(again)
+	require.NoError(t, err)
+}
@@ -0,0 +1,149 @@
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// localStore stores blobs as files in directory
+//
+//	{dir}/{hash[:2]}/{hash}  : content of the hash
+//	{dir}/tmp/               : contents being written
+type localStore struct {
+	dir string
+}
+
+// NewLocal create blob store of local filesystem in dir
+func NewLocal(dir string) (BlobStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0700); err != nil {
+		return nil, errors.Wrap(err, "blob.NewLocal")
+	}
+
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Put write content to temporary file while hashing it, then move it to path of the hash
+func (s *localStore) Put(r io.Reader) (string, int64, error) {
+	f, err := ioutil.TempFile(filepath.Join(s.dir, "tmp"), "blob-")
+	if err != nil {
+		return "", 0, errors.Wrap(err, "put")
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(f, io.TeeReader(r, h))
+	if err != nil {
+		f.Close()
+		return "", 0, errors.Wrap(err, "put")
+	}
+
+	if err := f.Close(); err != nil {
+		return "", 0, errors.Wrap(err, "put")
+	}
+
+	hash := hex.EncodeToString(h.Sum(nil))
+	path := s.path(hash)
+
+	if _, err := os.Stat(path); err == nil {
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return "", 0, errors.Wrap(err, "put")
+		}
+		return hash, size, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", 0, errors.Wrap(err, "put")
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", 0, errors.Wrap(err, "put")
+	}
+
+	return hash, size, nil
+}
+
+func (s *localStore) Open(hash string) (io.ReadCloser, error) {
+	if !validHash(hash) {
+		return nil, ErrNotFound
+	}
+
+	f, err := os.Open(s.path(hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "open")
+	}
+
+	return f, nil
+}
+
+func (s *localStore) Stat(hash string) (*Blob, error) {
+	if !validHash(hash) {
+		return nil, ErrNotFound
+	}
+
+	fi, err := os.Stat(s.path(hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "stat")
+	}
+
+	return &Blob{Hash: hash, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (s *localStore) List() ([]Blob, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "??", "*"))
+	if err != nil {
+		return nil, errors.Wrap(err, "list")
+	}
+
+	blobs := []Blob{}
+	for _, path := range paths {
+		hash := filepath.Base(path)
+		if !validHash(hash) {
+			continue
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, "list")
+		}
+
+		blobs = append(blobs, Blob{Hash: hash, Size: fi.Size(), ModTime: fi.ModTime()})
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].Hash < blobs[j].Hash })
+
+	return blobs, nil
+}
+
+func (s *localStore) Delete(hash string) error {
+	if !validHash(hash) {
+		return nil
+	}
+
+	if err := os.Remove(s.path(hash)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "delete")
+	}
+
+	return nil
+}
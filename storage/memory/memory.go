@@ -0,0 +1,980 @@
+// Package memory provides in-memory todo storage for tests and ephemeral demos
+package memory
+
+import (
+	"sync"
+
+	. "github.com/whitekid/go-todo/storage/types"
+)
+
+const Name = "memory"
+
+// New create new memory storage, name is ignored
+func New(name string) (Interface, error) {
+	s := &memoryStorage{
+		users:   map[string]*User{},
+		tokens:  map[string]string{},
+		todos:   map[string]map[string]*TodoItem{},
+		lists:   map[string]map[string]*List{},
+		members: map[string]map[string]*Member{},
+		deps:    map[string]map[Dependency]bool{},
+		filters: map[string]map[string]*Filter{},
+
+		attachments: map[string]map[string]*Attachment{},
+		comments:    map[string]map[string]*Comment{},
+		activities:  map[string]map[string]*Activity{},
+	}
+
+	s.userService = &memoryUserService{storage: s}
+	s.tokenService = &memoryTokenService{storage: s}
+	s.todoService = &memoryTodoService{storage: s}
+	s.listService = &memoryListService{storage: s}
+	s.dependencyService = &memoryDependencyService{storage: s}
+	s.filterService = &memoryFilterService{storage: s}
+	s.searchService = &memorySearchService{storage: s}
+	s.attachmentService = &memoryAttachmentService{storage: s}
+	s.commentService = &memoryCommentService{storage: s}
+	s.activityService = &memoryActivityService{storage: s}
+
+	return s, nil
+}
+
+type memoryStorage struct {
+	mu sync.RWMutex
+
+	users   map[string]*User                // email -> user
+	tokens  map[string]string               // refresh token -> email
+	todos   map[string]map[string]*TodoItem // email -> item id -> item
+	lists   map[string]map[string]*List     // email -> list id -> list
+	members map[string]map[string]*Member   // list id -> member email -> member
+	deps    map[string]map[Dependency]bool  // email -> dependencies
+	filters map[string]map[string]*Filter   // email -> filter id -> filter
+
+	attachments map[string]map[string]*Attachment // email -> attachment id -> attachment
+	comments    map[string]map[string]*Comment    // email -> comment id -> comment
+	activities  map[string]map[string]*Activity   // email -> activity id -> activity
+
+	userService       *memoryUserService
+	tokenService      *memoryTokenService
+	todoService       *memoryTodoService
+	listService       *memoryListService
+	dependencyService *memoryDependencyService
+	filterService     *memoryFilterService
+	searchService     *memorySearchService
+	attachmentService *memoryAttachmentService
+	commentService    *memoryCommentService
+	activityService   *memoryActivityService
+}
+
+func (s *memoryStorage) Close() {}
+
+func (s *memoryStorage) UserService() UserService {
+	return s.userService
+}
+
+func (s *memoryStorage) TokenService() TokenService {
+	return s.tokenService
+}
+
+func (s *memoryStorage) TodoService() TodoService {
+	return s.todoService
+}
+
+func (s *memoryStorage) ListService() ListService {
+	return s.listService
+}
+
+func (s *memoryStorage) DependencyService() DependencyService {
+	return s.dependencyService
+}
+
+func (s *memoryStorage) FilterService() FilterService {
+	return s.filterService
+}
+
+func (s *memoryStorage) SearchService() SearchService {
+	return s.searchService
+}
+
+func (s *memoryStorage) AttachmentService() AttachmentService {
+	return s.attachmentService
+}
+
+func (s *memoryStorage) CommentService() CommentService {
+	return s.commentService
+}
+
+func (s *memoryStorage) ActivityService() ActivityService {
+	return s.activityService
+}
+
+type memoryUserService struct {
+	storage *memoryStorage
+}
+
+func (s *memoryUserService) Create(user *User) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	u := *user
+	s.storage.users[user.Email] = &u
+
+	return nil
+}
+
+func (s *memoryUserService) Get(email string) (*User, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	user, ok := s.storage.users[email]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	u := *user
+	return &u, nil
+}
+
+func (s *memoryUserService) Update(user *User) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.users[user.Email]; !ok {
+		return ErrNotFound
+	}
+
+	u := *user
+	s.storage.users[user.Email] = &u
+
+	return nil
+}
+
+// Delete delete user with its tokens, todo items with attachments, comments and activities, lists, memberships and filters
+func (s *memoryUserService) Delete(email string) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.users[email]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.storage.users, email)
+	delete(s.storage.todos, email)
+	delete(s.storage.deps, email)
+	delete(s.storage.filters, email)
+	delete(s.storage.attachments, email)
+	delete(s.storage.comments, email)
+	delete(s.storage.activities, email)
+	for listID := range s.storage.lists[email] {
+		delete(s.storage.members, listID)
+	}
+	delete(s.storage.lists, email)
+	for _, members := range s.storage.members {
+		delete(members, email)
+	}
+	for token, owner := range s.storage.tokens {
+		if owner == email {
+			delete(s.storage.tokens, token)
+		}
+	}
+
+	return nil
+}
+
+type memoryTokenService struct {
+	storage *memoryStorage
+}
+
+// Create create refresh token, user is created if not exists
+func (s *memoryTokenService) Create(email, refreshToken string) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.users[email]; !ok {
+		s.storage.users[email] = &User{Email: email}
+	}
+	s.storage.tokens[refreshToken] = email
+
+	return nil
+}
+
+func (s *memoryTokenService) Get(token string) (string, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	if _, ok := s.storage.tokens[token]; !ok {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+func (s *memoryTokenService) Delete(token string) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.tokens[token]; !ok {
+		return ErrNotFound
+	}
+	delete(s.storage.tokens, token)
+
+	return nil
+}
+
+type memoryTodoService struct {
+	storage *memoryStorage
+}
+
+// copyItem returns deep copy of item, so stored item is not changed by caller
+func copyItem(item *TodoItem) *TodoItem {
+	i := *item
+	if item.CompletedAt != nil {
+		completedAt := *item.CompletedAt
+		i.CompletedAt = &completedAt
+	}
+	if item.Tags != nil {
+		i.Tags = append([]string{}, item.Tags...)
+	}
+	if item.Recurrence != nil {
+		i.Recurrence = copyRecurrence(item.Recurrence)
+	}
+	if item.Links != nil {
+		i.Links = append([]Link{}, item.Links...)
+	}
+	return &i
+}
+
+func copyRecurrence(recurrence *Recurrence) *Recurrence {
+	r := *recurrence
+	if recurrence.Weekdays != nil {
+		r.Weekdays = append([]string{}, recurrence.Weekdays...)
+	}
+	if recurrence.Until != nil {
+		until := *recurrence.Until
+		r.Until = &until
+	}
+	return &r
+}
+
+func (t *memoryTodoService) List(email string, opts *ListOptions) ([]TodoItem, string, error) {
+	t.storage.mu.RLock()
+	defer t.storage.mu.RUnlock()
+
+	items := []TodoItem{}
+	for _, item := range t.storage.todos[email] {
+		items = append(items, *copyItem(item))
+	}
+
+	return Page(items, opts)
+}
+
+func (t *memoryTodoService) Tags(email string) ([]Tag, error) {
+	t.storage.mu.RLock()
+	defer t.storage.mu.RUnlock()
+
+	items := []TodoItem{}
+	for _, item := range t.storage.todos[email] {
+		items = append(items, *item)
+	}
+
+	return CountTags(items), nil
+}
+
+func (t *memoryTodoService) Get(email, itemID string) (*TodoItem, error) {
+	t.storage.mu.RLock()
+	defer t.storage.mu.RUnlock()
+
+	item, ok := t.storage.todos[email][itemID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return copyItem(item), nil
+}
+
+func (t *memoryTodoService) Create(email string, item *TodoItem) error {
+	return t.write(email, &Operation{Op: OpCreate, Item: item})
+}
+
+func (t *memoryTodoService) Update(email string, item *TodoItem, revision int) error {
+	return t.write(email, &Operation{Op: OpUpdate, Item: item, Revision: revision})
+}
+
+func (t *memoryTodoService) Delete(email string, itemID string, revision int) error {
+	return t.write(email, &Operation{Op: OpDelete, ItemID: itemID, Revision: revision})
+}
+
+func (t *memoryTodoService) write(email string, op *Operation) error {
+	t.storage.mu.Lock()
+	defer t.storage.mu.Unlock()
+
+	todos, ok := t.storage.todos[email]
+	if !ok {
+		todos = map[string]*TodoItem{}
+		t.storage.todos[email] = todos
+	}
+
+	deleted, err := apply(todos, op)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range deleted {
+		t.storage.deleteItem(email, id)
+	}
+
+	return nil
+}
+
+// Batch apply operations to copy of user's items, and replace user's items if succeed
+func (t *memoryTodoService) Batch(email string, ops []Operation, atomic bool) ([]error, error) {
+	t.storage.mu.Lock()
+	defer t.storage.mu.Unlock()
+
+	todos := map[string]*TodoItem{}
+	for id, item := range t.storage.todos[email] {
+		todos[id] = item
+	}
+
+	errs := make([]error, len(ops))
+	deleted := []string{}
+	for i := range ops {
+		var ids []string
+		ids, errs[i] = apply(todos, &ops[i])
+		deleted = append(deleted, ids...)
+	}
+
+	if atomic && AbortOnError(errs) {
+		return errs, nil
+	}
+
+	t.storage.todos[email] = todos
+	for _, id := range deleted {
+		t.storage.deleteItem(email, id)
+	}
+
+	return errs, nil
+}
+
+// apply apply operation to user's items, returns IDs of deleted items
+func apply(todos map[string]*TodoItem, op *Operation) ([]string, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	if op.Op == OpCreate {
+		todos[op.ID()] = copyItem(op.Item)
+		return nil, nil
+	}
+
+	old, ok := todos[op.ID()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if !old.MatchRevision(op.Revision) {
+		return nil, ErrConflict
+	}
+
+	if op.Op == OpDelete {
+		return deleteTree(todos, op.ID(), op.Children)
+	}
+
+	todos[op.ID()] = copyItem(op.Item)
+	return nil, nil
+}
+
+// deleteTree delete item from user's items, its subtasks are deleted on cascade or detached.
+// returns IDs of deleted items, or ErrNotEmpty if item has subtasks on block
+func deleteTree(todos map[string]*TodoItem, itemID, policy string) ([]string, error) {
+	children := []*TodoItem{}
+	for _, item := range todos {
+		if item.ParentID == itemID {
+			children = append(children, item)
+		}
+	}
+
+	if len(children) > 0 && policy == ChildBlock {
+		return nil, ErrNotEmpty
+	}
+
+	delete(todos, itemID)
+	deleted := []string{itemID}
+
+	for _, child := range children {
+		if policy == ChildCascade {
+			ids, err := deleteTree(todos, child.ID, policy)
+			if err != nil {
+				return nil, err
+			}
+			deleted = append(deleted, ids...)
+			continue
+		}
+
+		detached := copyItem(child)
+		detached.Detach()
+		todos[child.ID] = detached
+	}
+
+	return deleted, nil
+}
+
+type memoryListService struct {
+	storage *memoryStorage
+}
+
+func (l *memoryListService) Create(email string, list *List) error {
+	l.storage.mu.Lock()
+	defer l.storage.mu.Unlock()
+
+	lists, ok := l.storage.lists[email]
+	if !ok {
+		lists = map[string]*List{}
+		l.storage.lists[email] = lists
+	}
+
+	v := *list
+	lists[list.ID] = &v
+
+	return nil
+}
+
+func (l *memoryListService) List(email string) ([]List, error) {
+	l.storage.mu.RLock()
+	defer l.storage.mu.RUnlock()
+
+	lists := []List{}
+	for _, list := range l.storage.lists[email] {
+		lists = append(lists, *list)
+	}
+	SortLists(lists)
+
+	return lists, nil
+}
+
+func (l *memoryListService) Get(email, listID string) (*List, error) {
+	l.storage.mu.RLock()
+	defer l.storage.mu.RUnlock()
+
+	list, ok := l.storage.lists[email][listID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	v := *list
+	return &v, nil
+}
+
+func (l *memoryListService) Update(email string, list *List) error {
+	l.storage.mu.Lock()
+	defer l.storage.mu.Unlock()
+
+	if _, ok := l.storage.lists[email][list.ID]; !ok {
+		return ErrNotFound
+	}
+
+	v := *list
+	l.storage.lists[email][list.ID] = &v
+
+	return nil
+}
+
+func (l *memoryListService) Delete(email, listID string, cascade bool) error {
+	l.storage.mu.Lock()
+	defer l.storage.mu.Unlock()
+
+	if _, ok := l.storage.lists[email][listID]; !ok {
+		return ErrNotFound
+	}
+
+	todos := l.storage.todos[email]
+	for id, item := range todos {
+		if item.ListID != listID {
+			continue
+		}
+
+		if !cascade {
+			return ErrNotEmpty
+		}
+
+		// subtasks are in same list, so they are deleted in turn or already deleted
+		deleted, _ := deleteTree(todos, id, ChildOrphan)
+		for _, id := range deleted {
+			l.storage.deleteItem(email, id)
+		}
+	}
+
+	delete(l.storage.lists[email], listID)
+	delete(l.storage.members, listID)
+
+	return nil
+}
+
+func (l *memoryListService) SetMember(member *Member) error {
+	l.storage.mu.Lock()
+	defer l.storage.mu.Unlock()
+
+	if _, ok := l.storage.lists[member.Owner][member.ListID]; !ok {
+		return ErrNotFound
+	}
+
+	members, ok := l.storage.members[member.ListID]
+	if !ok {
+		members = map[string]*Member{}
+		l.storage.members[member.ListID] = members
+	}
+
+	v := *member
+	members[member.Email] = &v
+
+	return nil
+}
+
+func (l *memoryListService) Members(email, listID string) ([]Member, error) {
+	l.storage.mu.RLock()
+	defer l.storage.mu.RUnlock()
+
+	members := []Member{}
+	if _, ok := l.storage.lists[email][listID]; !ok {
+		return members, nil
+	}
+
+	for _, member := range l.storage.members[listID] {
+		members = append(members, *member)
+	}
+	SortMembers(members)
+
+	return members, nil
+}
+
+func (l *memoryListService) DeleteMember(email, listID, memberEmail string) error {
+	l.storage.mu.Lock()
+	defer l.storage.mu.Unlock()
+
+	if _, ok := l.storage.lists[email][listID]; !ok {
+		return ErrNotFound
+	}
+
+	if _, ok := l.storage.members[listID][memberEmail]; !ok {
+		return ErrNotFound
+	}
+
+	delete(l.storage.members[listID], memberEmail)
+
+	return nil
+}
+
+func (l *memoryListService) Memberships(email string) ([]Member, error) {
+	l.storage.mu.RLock()
+	defer l.storage.mu.RUnlock()
+
+	members := []Member{}
+	for _, m := range l.storage.members {
+		if member, ok := m[email]; ok {
+			members = append(members, *member)
+		}
+	}
+	SortMembers(members)
+
+	return members, nil
+}
+
+// memoryDependencyService keeps dependencies of deleted items, they are dropped on read
+type memoryDependencyService struct {
+	storage *memoryStorage
+}
+
+// dependencies returns user's dependencies between existing items
+func (d *memoryDependencyService) dependencies(email string) []Dependency {
+	todos := d.storage.todos[email]
+
+	deps := []Dependency{}
+	for dep := range d.storage.deps[email] {
+		if todos[dep.ItemID] != nil && todos[dep.DependsOn] != nil {
+			deps = append(deps, dep)
+		}
+	}
+	SortDependencies(deps)
+
+	return deps
+}
+
+func (d *memoryDependencyService) Add(email string, dep *Dependency) error {
+	d.storage.mu.Lock()
+	defer d.storage.mu.Unlock()
+
+	todos := d.storage.todos[email]
+	if todos[dep.ItemID] == nil || todos[dep.DependsOn] == nil {
+		return ErrNotFound
+	}
+
+	if err := CheckDependency(d.dependencies(email), dep); err != nil {
+		return err
+	}
+
+	deps, ok := d.storage.deps[email]
+	if !ok {
+		deps = map[Dependency]bool{}
+		d.storage.deps[email] = deps
+	}
+	deps[*dep] = true
+
+	return nil
+}
+
+func (d *memoryDependencyService) List(email string) ([]Dependency, error) {
+	d.storage.mu.RLock()
+	defer d.storage.mu.RUnlock()
+
+	return d.dependencies(email), nil
+}
+
+func (d *memoryDependencyService) Delete(email string, dep *Dependency) error {
+	d.storage.mu.Lock()
+	defer d.storage.mu.Unlock()
+
+	if !d.storage.deps[email][*dep] {
+		return ErrNotFound
+	}
+
+	delete(d.storage.deps[email], *dep)
+
+	return nil
+}
+
+type memoryFilterService struct {
+	storage *memoryStorage
+}
+
+func (f *memoryFilterService) Create(email string, filter *Filter) error {
+	f.storage.mu.Lock()
+	defer f.storage.mu.Unlock()
+
+	filters, ok := f.storage.filters[email]
+	if !ok {
+		filters = map[string]*Filter{}
+		f.storage.filters[email] = filters
+	}
+
+	v := *filter
+	filters[filter.ID] = &v
+
+	return nil
+}
+
+func (f *memoryFilterService) List(email string) ([]Filter, error) {
+	f.storage.mu.RLock()
+	defer f.storage.mu.RUnlock()
+
+	filters := []Filter{}
+	for _, filter := range f.storage.filters[email] {
+		filters = append(filters, *filter)
+	}
+	SortFilters(filters)
+
+	return filters, nil
+}
+
+func (f *memoryFilterService) Get(email, filterID string) (*Filter, error) {
+	f.storage.mu.RLock()
+	defer f.storage.mu.RUnlock()
+
+	filter, ok := f.storage.filters[email][filterID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	v := *filter
+	return &v, nil
+}
+
+func (f *memoryFilterService) Update(email string, filter *Filter) error {
+	f.storage.mu.Lock()
+	defer f.storage.mu.Unlock()
+
+	if _, ok := f.storage.filters[email][filter.ID]; !ok {
+		return ErrNotFound
+	}
+
+	v := *filter
+	f.storage.filters[email][filter.ID] = &v
+
+	return nil
+}
+
+func (f *memoryFilterService) Delete(email, filterID string) error {
+	f.storage.mu.Lock()
+	defer f.storage.mu.Unlock()
+
+	if _, ok := f.storage.filters[email][filterID]; !ok {
+		return ErrNotFound
+	}
+
+	delete(f.storage.filters[email], filterID)
+
+	return nil
+}
+
+// memorySearchService scans all items of user, so it has no index to maintain
+type memorySearchService struct {
+	storage *memoryStorage
+}
+
+func (s *memorySearchService) Search(email string, query string, limit int) ([]SearchResult, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	items := []TodoItem{}
+	for _, item := range s.storage.todos[email] {
+		items = append(items, *copyItem(item))
+	}
+
+	return Search(items, SearchTerms(query), limit), nil
+}
+
+func (s *memorySearchService) Rebuild() error { return nil }
+
+type memoryAttachmentService struct {
+	storage *memoryStorage
+}
+
+func (a *memoryAttachmentService) Create(email string, attachment *Attachment, quota int64) error {
+	a.storage.mu.Lock()
+	defer a.storage.mu.Unlock()
+
+	if _, ok := a.storage.todos[email][attachment.ItemID]; !ok {
+		return ErrNotFound
+	}
+
+	if err := CheckQuota(a.storage.usage(email), attachment.Size, quota); err != nil {
+		return err
+	}
+
+	attachments, ok := a.storage.attachments[email]
+	if !ok {
+		attachments = map[string]*Attachment{}
+		a.storage.attachments[email] = attachments
+	}
+
+	v := *attachment
+	attachments[attachment.ID] = &v
+
+	return nil
+}
+
+func (a *memoryAttachmentService) List(email, itemID string) ([]Attachment, error) {
+	a.storage.mu.RLock()
+	defer a.storage.mu.RUnlock()
+
+	attachments := []Attachment{}
+	for _, attachment := range a.storage.attachments[email] {
+		if attachment.ItemID == itemID {
+			attachments = append(attachments, *attachment)
+		}
+	}
+	SortAttachments(attachments)
+
+	return attachments, nil
+}
+
+func (a *memoryAttachmentService) Get(email, itemID, attachmentID string) (*Attachment, error) {
+	a.storage.mu.RLock()
+	defer a.storage.mu.RUnlock()
+
+	attachment, ok := a.storage.attachments[email][attachmentID]
+	if !ok || attachment.ItemID != itemID {
+		return nil, ErrNotFound
+	}
+
+	v := *attachment
+	return &v, nil
+}
+
+func (a *memoryAttachmentService) Delete(email, itemID, attachmentID string) error {
+	a.storage.mu.Lock()
+	defer a.storage.mu.Unlock()
+
+	attachment, ok := a.storage.attachments[email][attachmentID]
+	if !ok || attachment.ItemID != itemID {
+		return ErrNotFound
+	}
+
+	delete(a.storage.attachments[email], attachmentID)
+
+	return nil
+}
+
+func (a *memoryAttachmentService) Usage(email string) (int64, error) {
+	a.storage.mu.RLock()
+	defer a.storage.mu.RUnlock()
+
+	return a.storage.usage(email), nil
+}
+
+func (a *memoryAttachmentService) Referenced(hash string) (bool, error) {
+	a.storage.mu.RLock()
+	defer a.storage.mu.RUnlock()
+
+	for _, attachments := range a.storage.attachments {
+		for _, attachment := range attachments {
+			if attachment.Hash == hash {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// usage returns total size of user's attachments, lock should be held by caller
+func (s *memoryStorage) usage(email string) int64 {
+	var usage int64
+	for _, attachment := range s.attachments[email] {
+		usage += attachment.Size
+	}
+	return usage
+}
+
+// deleteItem delete attachments, comments and activities of user's deleted item, lock should be held by caller
+func (s *memoryStorage) deleteItem(email, itemID string) {
+	for id, attachment := range s.attachments[email] {
+		if attachment.ItemID == itemID {
+			delete(s.attachments[email], id)
+		}
+	}
+
+	for id, comment := range s.comments[email] {
+		if comment.ItemID == itemID {
+			delete(s.comments[email], id)
+		}
+	}
+
+	for id, activity := range s.activities[email] {
+		if activity.ItemID == itemID {
+			delete(s.activities[email], id)
+		}
+	}
+}
+
+type memoryCommentService struct {
+	storage *memoryStorage
+}
+
+func (s *memoryCommentService) Create(email string, comment *Comment) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.todos[email][comment.ItemID]; !ok {
+		return ErrNotFound
+	}
+
+	comments, ok := s.storage.comments[email]
+	if !ok {
+		comments = map[string]*Comment{}
+		s.storage.comments[email] = comments
+	}
+
+	v := *comment
+	comments[comment.ID] = &v
+
+	return nil
+}
+
+func (s *memoryCommentService) List(email, itemID string) ([]Comment, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	comments := []Comment{}
+	for _, comment := range s.storage.comments[email] {
+		if comment.ItemID == itemID {
+			comments = append(comments, *comment)
+		}
+	}
+	SortComments(comments)
+
+	return comments, nil
+}
+
+func (s *memoryCommentService) Get(email, itemID, commentID string) (*Comment, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	comment, ok := s.storage.comments[email][commentID]
+	if !ok || comment.ItemID != itemID {
+		return nil, ErrNotFound
+	}
+
+	v := *comment
+	return &v, nil
+}
+
+func (s *memoryCommentService) Update(email string, comment *Comment) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	old, ok := s.storage.comments[email][comment.ID]
+	if !ok || old.ItemID != comment.ItemID {
+		return ErrNotFound
+	}
+
+	v := *comment
+	s.storage.comments[email][comment.ID] = &v
+
+	return nil
+}
+
+func (s *memoryCommentService) Delete(email, itemID, commentID string) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	comment, ok := s.storage.comments[email][commentID]
+	if !ok || comment.ItemID != itemID {
+		return ErrNotFound
+	}
+
+	delete(s.storage.comments[email], commentID)
+
+	return nil
+}
+
+type memoryActivityService struct {
+	storage *memoryStorage
+}
+
+func (s *memoryActivityService) Add(email string, activity *Activity) error {
+	s.storage.mu.Lock()
+	defer s.storage.mu.Unlock()
+
+	if _, ok := s.storage.todos[email][activity.ItemID]; !ok {
+		return ErrNotFound
+	}
+
+	activities, ok := s.storage.activities[email]
+	if !ok {
+		activities = map[string]*Activity{}
+		s.storage.activities[email] = activities
+	}
+
+	v := *activity
+	v.Fields = append([]string(nil), activity.Fields...)
+	activities[activity.ID] = &v
+
+	return nil
+}
+
+func (s *memoryActivityService) List(email, itemID string) ([]Activity, error) {
+	s.storage.mu.RLock()
+	defer s.storage.mu.RUnlock()
+
+	activities := []Activity{}
+	for _, activity := range s.storage.activities[email] {
+		if activity.ItemID == itemID {
+			v := *activity
+			v.Fields = append([]string(nil), activity.Fields...)
+			activities = append(activities, v)
+		}
+	}
+	SortActivities(activities)
+
+	return activities, nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/storagetest"
+	. "github.com/whitekid/go-todo/storage/types"
+)
+
+func TestMemory(t *testing.T) {
+	s, err := New("")
+	require.NoError(t, err)
+	defer s.Close()
+
+	todos := s.TodoService()
+	tokens := s.TokenService()
+
+	email := "whitekid@gmail.com"
+
+	require.NoError(t, tokens.Create(email, "refresh-token"))
+	{
+		got, err := tokens.Get("refresh-token")
+		require.NoError(t, err)
+		require.Equal(t, "refresh-token", got)
+
+		user, err := s.UserService().Get(email)
+		require.NoError(t, err)
+		require.Equal(t, email, user.Email)
+	}
+
+	item := TodoItem{
+		ID:      uuid.New().String(),
+		Title:   "title",
+		DueDate: Today(),
+		Rank:    1,
+	}
+	require.NoError(t, todos.Create(email, &item))
+
+	items, _, err := todos.List(email, nil)
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{item}, items)
+
+	got, err := todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, &item, got)
+
+	// returned item should not share stored item
+	got.Title = "updated"
+	{
+		stored, err := todos.Get(email, item.ID)
+		require.NoError(t, err)
+		require.Equal(t, "title", stored.Title)
+	}
+
+	require.NoError(t, todos.Update(email, got, 0))
+	{
+		updated, err := todos.Get(email, item.ID)
+		require.NoError(t, err)
+		require.Equal(t, got, updated)
+	}
+
+	require.NoError(t, todos.Delete(email, got.ID, 0))
+	if _, err := todos.Get(email, item.ID); err != ErrNotFound {
+		require.Fail(t, "should error", "want = %v, got = %v", ErrNotFound, err)
+	}
+	require.Equal(t, ErrNotFound, todos.Update(email, got, 0))
+	require.Equal(t, ErrNotFound, todos.Delete(email, got.ID, 0))
+
+	{
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(items))
+	}
+}
+
+func TestUserDeleteCascade(t *testing.T) {
+	s, err := New("")
+	require.NoError(t, err)
+	defer s.Close()
+
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.TokenService().Create(email, "refresh-token"))
+
+	item := TodoItem{ID: uuid.New().String(), Title: "title"}
+	require.NoError(t, s.TodoService().Create(email, &item))
+
+	require.NoError(t, s.UserService().Delete(email))
+
+	_, err = s.UserService().Get(email)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.TokenService().Get("refresh-token")
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.TodoService().Get(email, item.ID)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) Interface {
+		s, err := New("")
+		require.NoError(t, err)
+		return s
+	})
+}
@@ -33,6 +33,34 @@ func (m *MockInterface) EXPECT() *MockInterfaceMockRecorder {
 	return m.recorder
 }
 
+// ActivityService mocks base method
+func (m *MockInterface) ActivityService() types.ActivityService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivityService")
+	ret0, _ := ret[0].(types.ActivityService)
+	return ret0
+}
+
+// ActivityService indicates an expected call of ActivityService
+func (mr *MockInterfaceMockRecorder) ActivityService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivityService", reflect.TypeOf((*MockInterface)(nil).ActivityService))
+}
+
+// AttachmentService mocks base method
+func (m *MockInterface) AttachmentService() types.AttachmentService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachmentService")
+	ret0, _ := ret[0].(types.AttachmentService)
+	return ret0
+}
+
+// AttachmentService indicates an expected call of AttachmentService
+func (mr *MockInterfaceMockRecorder) AttachmentService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachmentService", reflect.TypeOf((*MockInterface)(nil).AttachmentService))
+}
+
 // Close mocks base method
 func (m *MockInterface) Close() {
 	m.ctrl.T.Helper()
@@ -45,6 +73,76 @@ func (mr *MockInterfaceMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockInterface)(nil).Close))
 }
 
+// CommentService mocks base method
+func (m *MockInterface) CommentService() types.CommentService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentService")
+	ret0, _ := ret[0].(types.CommentService)
+	return ret0
+}
+
+// CommentService indicates an expected call of CommentService
+func (mr *MockInterfaceMockRecorder) CommentService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentService", reflect.TypeOf((*MockInterface)(nil).CommentService))
+}
+
+// DependencyService mocks base method
+func (m *MockInterface) DependencyService() types.DependencyService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DependencyService")
+	ret0, _ := ret[0].(types.DependencyService)
+	return ret0
+}
+
+// DependencyService indicates an expected call of DependencyService
+func (mr *MockInterfaceMockRecorder) DependencyService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DependencyService", reflect.TypeOf((*MockInterface)(nil).DependencyService))
+}
+
+// FilterService mocks base method
+func (m *MockInterface) FilterService() types.FilterService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterService")
+	ret0, _ := ret[0].(types.FilterService)
+	return ret0
+}
+
+// FilterService indicates an expected call of FilterService
+func (mr *MockInterfaceMockRecorder) FilterService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterService", reflect.TypeOf((*MockInterface)(nil).FilterService))
+}
+
+// ListService mocks base method
+func (m *MockInterface) ListService() types.ListService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListService")
+	ret0, _ := ret[0].(types.ListService)
+	return ret0
+}
+
+// ListService indicates an expected call of ListService
+func (mr *MockInterfaceMockRecorder) ListService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListService", reflect.TypeOf((*MockInterface)(nil).ListService))
+}
+
+// SearchService mocks base method
+func (m *MockInterface) SearchService() types.SearchService {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchService")
+	ret0, _ := ret[0].(types.SearchService)
+	return ret0
+}
+
+// SearchService indicates an expected call of SearchService
+func (mr *MockInterfaceMockRecorder) SearchService() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchService", reflect.TypeOf((*MockInterface)(nil).SearchService))
+}
+
 // TodoService mocks base method
 func (m *MockInterface) TodoService() types.TodoService {
 	m.ctrl.T.Helper()
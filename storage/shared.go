@@ -0,0 +1,169 @@
+package storage
+
+import "github.com/whitekid/go-todo/storage/types"
+
+type (
+	Role   = types.Role
+	Member = types.Member
+)
+
+const (
+	RoleOwner  = types.RoleOwner
+	RoleEditor = types.RoleEditor
+	RoleViewer = types.RoleViewer
+)
+
+// ListRole returns owner of the list and role of the user to it.
+// returns ErrNotFound if list is neither owned by nor shared with the user
+func ListRole(s Interface, email, listID string) (string, Role, error) {
+	if _, err := s.ListService().Get(email, listID); err != ErrNotFound {
+		return email, RoleOwner, err
+	}
+
+	members, err := s.ListService().Memberships(email)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, m := range members {
+		if m.ListID == listID {
+			return m.Owner, m.Role, nil
+		}
+	}
+
+	return "", "", ErrNotFound
+}
+
+// FindItem returns owner of the item, the item and role of the user to it.
+// items of others are found only if they are in lists shared with the user.
+// returns ErrNotFound if item is not found
+func FindItem(s Interface, email, itemID string) (string, *TodoItem, Role, error) {
+	todos := s.TodoService()
+
+	item, err := todos.Get(email, itemID)
+	if err != ErrNotFound {
+		return email, item, RoleOwner, err
+	}
+
+	members, err := s.ListService().Memberships(email)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	for _, m := range members {
+		item, err := todos.Get(m.Owner, itemID)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return "", nil, "", err
+		}
+
+		if item.ListID == m.ListID {
+			return m.Owner, item, m.Role, nil
+		}
+	}
+
+	return "", nil, "", ErrNotFound
+}
+
+// ListItems list user's items with items of lists shared with the user
+func ListItems(s Interface, email string, opts *ListOptions) ([]TodoItem, string, error) {
+	members, err := s.ListService().Memberships(email)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(members) == 0 {
+		return ListOwnerItems(s, email, opts)
+	}
+
+	// items are spread over owners, so collect all matching items then page them
+	var all ListOptions
+	if opts != nil {
+		all = *opts
+		all.Limit = 0
+		all.Cursor = ""
+	}
+
+	items, _, err := ListOwnerItems(s, email, &all)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, m := range members {
+		if all.ListID != "" && all.ListID != m.ListID {
+			continue
+		}
+
+		shared := all
+		shared.ListID = m.ListID
+		sharedItems, _, err := ListOwnerItems(s, m.Owner, &shared)
+		if err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, sharedItems...)
+	}
+
+	return types.Page(items, opts)
+}
+
+// SearchItems search user's items with items of lists shared with the user, ordered by relevance
+func SearchItems(s Interface, email string, query string, limit int) ([]SearchResult, error) {
+	results, err := s.SearchService().Search(email, query, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.ListService().Memberships(email)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range members {
+		shared, err := s.SearchService().Search(m.Owner, query, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range shared {
+			if result.Item.ListID == m.ListID {
+				results = append(results, result)
+			}
+		}
+	}
+
+	types.SortSearchResults(results)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// ListOwnerItems list owner's items; if actionable, blocked items are resolved by owner's dependencies
+func ListOwnerItems(s Interface, owner string, opts *ListOptions) ([]TodoItem, string, error) {
+	if opts == nil || !opts.Actionable {
+		return s.TodoService().List(owner, opts)
+	}
+
+	deps, err := s.DependencyService().List(owner)
+	if err != nil {
+		return nil, "", err
+	}
+
+	actionable := *opts
+	actionable.Blocked = nil
+	if len(deps) > 0 {
+		items, _, err := s.TodoService().List(owner, &ListOptions{Status: types.StatusOpen})
+		if err != nil {
+			return nil, "", err
+		}
+
+		actionable.Blocked = types.Blocked(deps, items)
+	}
+
+	return s.TodoService().List(owner, &actionable)
+}
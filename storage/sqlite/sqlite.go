@@ -0,0 +1,1748 @@
+// Package sqlite provides todo storage backed by a single sqlite database file
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver
+	"github.com/pkg/errors"
+	. "github.com/whitekid/go-todo/storage/types"
+	"github.com/whitekid/go-utils/log"
+)
+
+const Name = "sqlite"
+
+// schema migrations; applied in order and tracked by PRAGMA user_version
+// NOTE never change applied migration, add new one instead
+var migrations = []string{
+	`CREATE TABLE users (
+		email TEXT PRIMARY KEY
+	);
+
+	CREATE TABLE tokens (
+		token TEXT PRIMARY KEY,
+		email TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE
+	);
+	CREATE INDEX tokens_email ON tokens(email);
+
+	CREATE TABLE todos (
+		id       TEXT PRIMARY KEY,
+		email    TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		title    TEXT NOT NULL,
+		due_date TEXT NOT NULL,
+		rank     INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX todos_email ON todos(email);`,
+
+	`ALTER TABLE todos ADD COLUMN created_at TEXT NOT NULL DEFAULT '';`,
+
+	// sort keys of existing items are written by fillSortKeys()
+	`CREATE TABLE sort_keys (
+		email   TEXT NOT NULL,
+		item_id TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		sort    TEXT NOT NULL,
+		key     TEXT NOT NULL,
+		PRIMARY KEY (item_id, sort)
+	);
+	CREATE INDEX sort_keys_key ON sort_keys(email, sort, key);`,
+
+	`ALTER TABLE todos ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE todos ADD COLUMN completed_at TEXT NOT NULL DEFAULT '';`,
+
+	`ALTER TABLE todos ADD COLUMN updated_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE todos ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;`,
+
+	`CREATE TABLE lists (
+		id         TEXT PRIMARY KEY,
+		email      TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		name       TEXT NOT NULL,
+		created_at TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX lists_email ON lists(email);
+
+	ALTER TABLE todos ADD COLUMN list_id TEXT NOT NULL DEFAULT '';
+	CREATE INDEX todos_list ON todos(email, list_id);`,
+
+	`CREATE TABLE members (
+		list_id TEXT NOT NULL REFERENCES lists(id) ON DELETE CASCADE,
+		email   TEXT NOT NULL,
+		role    TEXT NOT NULL,
+		PRIMARY KEY (list_id, email)
+	);
+	CREATE INDEX members_email ON members(email);`,
+
+	`ALTER TABLE todos ADD COLUMN tags TEXT NOT NULL DEFAULT '';`,
+	`ALTER TABLE todos ADD COLUMN parent_id TEXT NOT NULL DEFAULT '';
+	CREATE INDEX todos_parent ON todos(email, parent_id);`,
+
+	`CREATE TABLE dependencies (
+		email      TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		item_id    TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		depends_on TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		PRIMARY KEY (item_id, depends_on)
+	);
+	CREATE INDEX dependencies_email ON dependencies(email);
+	CREATE INDEX dependencies_depends_on ON dependencies(depends_on);`,
+
+	`ALTER TABLE todos ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';`,
+
+	`ALTER TABLE todos ADD COLUMN due_time TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';`,
+
+	`ALTER TABLE todos ADD COLUMN priority TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE filters (
+		id         TEXT PRIMARY KEY,
+		email      TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		name       TEXT NOT NULL,
+		query      TEXT NOT NULL,
+		created_at TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX filters_email ON filters(email);`,
+
+	// words of existing items are indexed by rebuild
+	`CREATE TABLE search (
+		email   TEXT NOT NULL,
+		word    TEXT NOT NULL,
+		item_id TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		PRIMARY KEY (item_id, word)
+	);
+	CREATE INDEX search_word ON search(email, word);`,
+
+	// notes of existing items are indexed by rebuild
+	`ALTER TABLE todos ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+	ALTER TABLE todos ADD COLUMN links TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE attachments (
+		id           TEXT PRIMARY KEY,
+		email        TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		item_id      TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		name         TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size         INTEGER NOT NULL,
+		hash         TEXT NOT NULL,
+		created_by   TEXT NOT NULL DEFAULT '',
+		created_at   TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX attachments_item ON attachments(email, item_id);
+	CREATE INDEX attachments_hash ON attachments(hash);`,
+	`CREATE TABLE comments (
+		id         TEXT PRIMARY KEY,
+		email      TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		item_id    TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		body       TEXT NOT NULL,
+		author     TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL DEFAULT '',
+		updated_at TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX comments_item ON comments(email, item_id);
+	CREATE TABLE activities (
+		id         TEXT PRIMARY KEY,
+		email      TEXT NOT NULL REFERENCES users(email) ON DELETE CASCADE,
+		item_id    TEXT NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+		actor      TEXT NOT NULL DEFAULT '',
+		action     TEXT NOT NULL,
+		fields     TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX activities_item ON activities(email, item_id);`,
+}
+
+// New create new sqlite storage
+func New(name string) (Interface, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s.sqlite?_foreign_keys=on&_busy_timeout=5000", name))
+	if err != nil {
+		return nil, errors.Wrap(err, "sqlite.New")
+	}
+
+	// sqlite allows only one writer
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "sqlite.New")
+	}
+
+	if err := fillSortKeys(db); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "sqlite.New")
+	}
+
+	s := &sqliteStorage{db: db}
+	s.userService = &sqliteUserService{storage: s}
+	s.tokenService = &sqliteTokenService{storage: s}
+	s.todoService = &sqliteTodoService{storage: s}
+	s.listService = &sqliteListService{storage: s}
+	s.dependencyService = &sqliteDependencyService{storage: s}
+	s.filterService = &sqliteFilterService{storage: s}
+	s.searchService = &sqliteSearchService{storage: s}
+	s.attachmentService = &sqliteAttachmentService{storage: s}
+	s.commentService = &sqliteCommentService{storage: s}
+	s.activityService = &sqliteActivityService{storage: s}
+
+	return s, nil
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	for ; version < len(migrations); version++ {
+		log.Debugf("migrate schema to version %d", version+1)
+
+		if err := withTx(db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(migrations[version]); err != nil {
+				return err
+			}
+
+			// PRAGMA does not support placeholders
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1))
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "migration %d", version+1)
+		}
+	}
+
+	return nil
+}
+
+// withTx run fn in transaction, commit if fn success or rollback
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execer is common interface of sql.DB and sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// rowsAffected returns ErrNotFound if nothing affected
+func rowsAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+type sqliteStorage struct {
+	db *sql.DB
+
+	userService       *sqliteUserService
+	tokenService      *sqliteTokenService
+	todoService       *sqliteTodoService
+	listService       *sqliteListService
+	dependencyService *sqliteDependencyService
+	filterService     *sqliteFilterService
+	searchService     *sqliteSearchService
+	attachmentService *sqliteAttachmentService
+	commentService    *sqliteCommentService
+	activityService   *sqliteActivityService
+}
+
+func (s *sqliteStorage) Close() {
+	s.db.Close()
+}
+
+func (s *sqliteStorage) UserService() UserService {
+	return s.userService
+}
+
+func (s *sqliteStorage) TokenService() TokenService {
+	return s.tokenService
+}
+
+func (s *sqliteStorage) TodoService() TodoService {
+	return s.todoService
+}
+
+func (s *sqliteStorage) ListService() ListService {
+	return s.listService
+}
+
+func (s *sqliteStorage) DependencyService() DependencyService {
+	return s.dependencyService
+}
+
+func (s *sqliteStorage) FilterService() FilterService {
+	return s.filterService
+}
+
+func (s *sqliteStorage) SearchService() SearchService {
+	return s.searchService
+}
+
+func (s *sqliteStorage) AttachmentService() AttachmentService {
+	return s.attachmentService
+}
+
+func (s *sqliteStorage) CommentService() CommentService {
+	return s.commentService
+}
+
+func (s *sqliteStorage) ActivityService() ActivityService {
+	return s.activityService
+}
+
+type sqliteUserService struct {
+	storage *sqliteStorage
+}
+
+func (s *sqliteUserService) Create(user *User) error {
+	if _, err := s.storage.db.Exec(`INSERT INTO users(email, time_zone) VALUES(?, ?)
+		ON CONFLICT(email) DO UPDATE SET time_zone = excluded.time_zone`, user.Email, user.TimeZone); err != nil {
+		return errors.Wrapf(err, "user.Create()")
+	}
+
+	return nil
+}
+
+func (s *sqliteUserService) Get(email string) (*User, error) {
+	var user User
+
+	if err := s.storage.db.QueryRow("SELECT email, time_zone FROM users WHERE email = ?", email).Scan(&user.Email, &user.TimeZone); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *sqliteUserService) Update(user *User) error {
+	result, err := s.storage.db.Exec("UPDATE users SET time_zone = ? WHERE email = ?", user.TimeZone, user.Email)
+	if err != nil {
+		return errors.Wrapf(err, "user.Update()")
+	}
+
+	return rowsAffected(result)
+}
+
+// Delete delete user; tokens, todo items, lists and filters are deleted by foreign key cascade
+func (s *sqliteUserService) Delete(email string) error {
+	return withTx(s.storage.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec("DELETE FROM users WHERE email = ?", email)
+		if err != nil {
+			return errors.Wrapf(err, "delete")
+		}
+
+		if err := rowsAffected(result); err != nil {
+			return err
+		}
+
+		// member could be not a user, so it has no foreign key to users
+		if _, err := tx.Exec("DELETE FROM members WHERE email = ?", email); err != nil {
+			return errors.Wrapf(err, "delete memberships")
+		}
+
+		return nil
+	})
+}
+
+type sqliteTokenService struct {
+	storage *sqliteStorage
+}
+
+// Create create refresh token, user is created if not exists
+func (s *sqliteTokenService) Create(email, refreshToken string) error {
+	return withTx(s.storage.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO users(email) VALUES(?)", email); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`INSERT INTO tokens(token, email) VALUES(?, ?)
+			ON CONFLICT(token) DO UPDATE SET email = excluded.email`, refreshToken, email); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *sqliteTokenService) Get(token string) (string, error) {
+	var t string
+
+	if err := s.storage.db.QueryRow("SELECT token FROM tokens WHERE token = ?", token).Scan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+
+	return t, nil
+}
+
+func (s *sqliteTokenService) Delete(token string) error {
+	result, err := s.storage.db.Exec("DELETE FROM tokens WHERE token = ?", token)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+type sqliteTodoService struct {
+	storage *sqliteStorage
+}
+
+// todo item columns; should be same order with todoValues() and scanTodoItem()
+var todoColumns = []string{"id", "title", "due_date", "rank", "created_at", "completed", "completed_at", "updated_at", "revision", "list_id", "tags", "parent_id", "recurrence", "due_time", "priority", "notes", "links"}
+
+var (
+	sqlSelectTodo = "SELECT " + strings.Join(todoColumns, ", ") + " FROM todos"
+	sqlInsertTodo = "INSERT INTO todos(email, " + strings.Join(todoColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(todoColumns)) + ")"
+	sqlUpdateTodo = "UPDATE todos SET " + strings.Join(todoColumns, " = ?, ") + " = ? WHERE email = ? AND id = ?"
+)
+
+func todoValues(item *TodoItem) []interface{} {
+	var completedAt time.Time
+	if item.CompletedAt != nil {
+		completedAt = *item.CompletedAt
+	}
+
+	return []interface{}{item.ID, item.Title, item.DueDate.Format(RFC3339FullDate), item.Rank, formatTime(item.CreatedAt),
+		item.Completed, formatTime(completedAt), formatTime(item.UpdatedAt), item.Revision, item.ListID, formatTags(item.Tags), item.ParentID, formatRecurrence(item.Recurrence), item.DueTime, item.Priority, item.Notes, formatLinks(item.Links)}
+}
+
+// formatTags returns tags as text column, JSON array or empty string for no tags
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(tags)
+	return string(data)
+}
+
+// parseTags parse text column of tags
+func parseTags(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(s), &tags); err != nil {
+		return nil, errors.Wrapf(err, "invalid tags: %s", s)
+	}
+
+	return tags, nil
+}
+
+// formatRecurrence returns recurrence as text column, JSON object or empty string for no recurrence
+func formatRecurrence(recurrence *Recurrence) string {
+	if recurrence == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(recurrence)
+	return string(data)
+}
+
+// parseRecurrence parse text column of recurrence
+func parseRecurrence(s string) (*Recurrence, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var recurrence Recurrence
+	if err := json.Unmarshal([]byte(s), &recurrence); err != nil {
+		return nil, errors.Wrapf(err, "invalid recurrence: %s", s)
+	}
+
+	return &recurrence, nil
+}
+
+// formatLinks returns links as text column, JSON array or empty string for no links
+func formatLinks(links []Link) string {
+	if len(links) == 0 {
+		return ""
+	}
+
+	data, _ := json.Marshal(links)
+	return string(data)
+}
+
+// parseLinks parse text column of links
+func parseLinks(s string) ([]Link, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var links []Link
+	if err := json.Unmarshal([]byte(s), &links); err != nil {
+		return nil, errors.Wrapf(err, "invalid links: %s", s)
+	}
+
+	return links, nil
+}
+
+// scanner is common interface of sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTodoItem(row scanner) (*TodoItem, error) {
+	var item TodoItem
+	var dueDate, createdAt, completedAt, updatedAt, tags, recurrence, links string
+
+	if err := row.Scan(&item.ID, &item.Title, &dueDate, &item.Rank, &createdAt, &item.Completed, &completedAt,
+		&updatedAt, &item.Revision, &item.ListID, &tags, &item.ParentID, &recurrence, &item.DueTime, &item.Priority, &item.Notes, &links); err != nil {
+		return nil, err
+	}
+
+	t, err := time.Parse(RFC3339FullDate, dueDate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid due date: %s", dueDate)
+	}
+	item.DueDate.Time = t
+
+	if item.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+
+	if item.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	if item.Tags, err = parseTags(tags); err != nil {
+		return nil, err
+	}
+
+	if item.Recurrence, err = parseRecurrence(recurrence); err != nil {
+		return nil, err
+	}
+
+	if item.Links, err = parseLinks(links); err != nil {
+		return nil, err
+	}
+
+	if completedAt != "" {
+		t, err := parseTime(completedAt)
+		if err != nil {
+			return nil, err
+		}
+		item.CompletedAt = &t
+	}
+
+	return &item, nil
+}
+
+// formatTime returns time as text column
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTime parse text column, empty string for zero time
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid time: %s", s)
+	}
+
+	return t, nil
+}
+
+// List iterate items in order of sort keys from the cursor.
+// items are read by pages of limit and filtered, until the page is filled or no more items
+func (t *sqliteTodoService) List(email string, opts *ListOptions) ([]TodoItem, string, error) {
+	sortOrder := opts.SortOrder()
+
+	var after string
+	batch := -1 // no limit
+	if opts != nil {
+		if opts.Cursor != "" {
+			key, err := DecodeCursor(opts.Cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			after = key
+		}
+
+		if opts.Limit > 0 {
+			// one more item is read to know whether next page exists
+			batch = opts.Limit + 1
+		}
+	}
+
+	where, args := listFilter(opts)
+	query := sqlSelectTodo + " JOIN sort_keys ON item_id = id WHERE sort_keys.email = ? AND sort = ? AND key > ?" + where + " ORDER BY key LIMIT ?"
+
+	items := []TodoItem{}
+	for {
+		n := 0
+		if err := func() error {
+			rows, err := t.storage.db.Query(query, append(append([]interface{}{email, sortOrder, after}, args...), batch)...)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				item, err := scanTodoItem(rows)
+				if err != nil {
+					return err
+				}
+
+				n++
+				after = SortKey(item, sortOrder)
+				if opts.Match(item) {
+					items = append(items, *item)
+				}
+			}
+
+			return rows.Err()
+		}(); err != nil {
+			return nil, "", err
+		}
+
+		if batch < 0 || n < batch || len(items) >= batch {
+			break
+		}
+	}
+
+	var next string
+	if batch > 0 && len(items) >= batch {
+		items = items[:opts.Limit]
+		next = EncodeCursor(SortKey(&items[opts.Limit-1], sortOrder))
+	}
+
+	return items, next, nil
+}
+
+// listFilter returns where clause and its arguments of list options, which are filtered in SQL.
+// other filters are applied by ListOptions.Match() after read
+func listFilter(opts *ListOptions) (string, []interface{}) {
+	if opts == nil {
+		return "", nil
+	}
+
+	where := ""
+	args := []interface{}{}
+
+	// items without due date have zero date, which are excluded by due date filters
+	if opts.DueBefore != nil {
+		where += " AND due_date != ? AND due_date < ?"
+		args = append(args, Date{}.Format(RFC3339FullDate), opts.DueBefore.Format(RFC3339FullDate))
+	}
+
+	if opts.DueAfter != nil {
+		where += " AND due_date != ? AND due_date > ?"
+		args = append(args, Date{}.Format(RFC3339FullDate), opts.DueAfter.Format(RFC3339FullDate))
+	}
+
+	if opts.ListID != "" {
+		where += " AND list_id = ?"
+		args = append(args, opts.ListID)
+	}
+
+	if opts.ParentID != "" {
+		where += " AND parent_id = ?"
+		args = append(args, opts.ParentID)
+	}
+
+	switch opts.Status {
+	case StatusOpen:
+		where += " AND completed = 0"
+	case StatusCompleted:
+		where += " AND completed != 0"
+	}
+
+	return where, args
+}
+
+// writeSortKeys write sort keys of item for all sort orders
+func writeSortKeys(e execer, email string, item *TodoItem) error {
+	for _, order := range Sorts() {
+		if _, err := e.Exec(`INSERT INTO sort_keys(email, item_id, sort, key) VALUES(?, ?, ?, ?)
+			ON CONFLICT(item_id, sort) DO UPDATE SET key = excluded.key`, email, item.ID, order, SortKey(item, order)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillSortKeys write sort keys of items which do not have keys of all sort orders,
+// such as items written before the sort order is added
+func fillSortKeys(db *sql.DB) error {
+	return withTx(db, func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT email, id FROM todos WHERE (SELECT COUNT(*) FROM sort_keys WHERE item_id = id) < ?", len(Sorts()))
+		if err != nil {
+			return err
+		}
+
+		type key struct{ email, id string }
+		keys := []key{}
+		for rows.Next() {
+			var k key
+			if err := rows.Scan(&k.email, &k.id); err != nil {
+				rows.Close()
+				return err
+			}
+			keys = append(keys, k)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			log.Infof("write sort keys of %d items", len(keys))
+		}
+
+		for _, k := range keys {
+			item, err := scanTodoItem(tx.QueryRow(sqlSelectTodo+" WHERE id = ?", k.id))
+			if err != nil {
+				return err
+			}
+
+			if err := writeSortKeys(tx, k.email, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (t *sqliteTodoService) Tags(email string) ([]Tag, error) {
+	rows, err := t.storage.db.Query("SELECT tags FROM todos WHERE email = ? AND tags != ''", email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []TodoItem{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+
+		tags, err := parseTags(s)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, TodoItem{Tags: tags})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return CountTags(items), nil
+}
+
+func (t *sqliteTodoService) Get(email, itemID string) (*TodoItem, error) {
+	item, err := scanTodoItem(t.storage.db.QueryRow(sqlSelectTodo+" WHERE email = ? AND id = ?", email, itemID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (t *sqliteTodoService) Create(email string, item *TodoItem) error {
+	return t.write(email, &Operation{Op: OpCreate, Item: item})
+}
+
+func (t *sqliteTodoService) Update(email string, item *TodoItem, revision int) error {
+	return t.write(email, &Operation{Op: OpUpdate, Item: item, Revision: revision})
+}
+
+func (t *sqliteTodoService) Delete(email string, itemID string, revision int) error {
+	return t.write(email, &Operation{Op: OpDelete, ItemID: itemID, Revision: revision})
+}
+
+func (t *sqliteTodoService) write(email string, op *Operation) error {
+	return withTx(t.storage.db, func(tx *sql.Tx) error { return apply(tx, email, op) })
+}
+
+// errAborted rollbacks batch transaction
+var errAborted = errors.New("batch aborted")
+
+// Batch apply operations in one transaction
+func (t *sqliteTodoService) Batch(email string, ops []Operation, atomic bool) ([]error, error) {
+	errs := make([]error, len(ops))
+
+	if err := withTx(t.storage.db, func(tx *sql.Tx) error {
+		for i := range ops {
+			// operation failed before its write, so other operations are not affected
+			errs[i] = apply(tx, email, &ops[i])
+		}
+
+		if atomic && AbortOnError(errs) {
+			return errAborted
+		}
+
+		return nil
+	}); err != nil && err != errAborted {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// apply apply operation to user's items
+func apply(tx *sql.Tx, email string, op *Operation) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+
+	if op.Op == OpCreate {
+		if _, err := tx.Exec(sqlInsertTodo, append([]interface{}{email}, todoValues(op.Item)...)...); err != nil {
+			return err
+		}
+		if err := writeSortKeys(tx, email, op.Item); err != nil {
+			return err
+		}
+		return indexSearch(tx, email, op.Item)
+	}
+
+	if err := checkRevision(tx, email, op.ID(), op.Revision); err != nil {
+		return err
+	}
+
+	if op.Op == OpDelete {
+		return deleteTree(tx, email, op.ID(), op.Children)
+	}
+
+	return update(tx, email, op.Item)
+}
+
+// update write item with its sort keys and search index
+func update(tx *sql.Tx, email string, item *TodoItem) error {
+	if _, err := tx.Exec(sqlUpdateTodo, append(todoValues(item), email, item.ID)...); err != nil {
+		return err
+	}
+	if err := writeSortKeys(tx, email, item); err != nil {
+		return err
+	}
+	return indexSearch(tx, email, item)
+}
+
+// deleteTree delete item, its subtasks are deleted on cascade or detached; returns ErrNotEmpty if item has subtasks on block.
+// sort keys and search index are deleted with the item by foreign key cascade
+func deleteTree(tx *sql.Tx, email, itemID, policy string) error {
+	children, err := queryTodoItems(tx, sqlSelectTodo+" WHERE email = ? AND parent_id = ?", email, itemID)
+	if err != nil {
+		return err
+	}
+
+	if len(children) > 0 && policy == ChildBlock {
+		return ErrNotEmpty
+	}
+
+	if _, err := tx.Exec("DELETE FROM todos WHERE email = ? AND id = ?", email, itemID); err != nil {
+		return err
+	}
+
+	for i := range children {
+		child := &children[i]
+		if policy == ChildCascade {
+			err = deleteTree(tx, email, child.ID, policy)
+		} else {
+			child.Detach()
+			err = update(tx, email, child)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexSearch replace words of item in search index
+func indexSearch(tx *sql.Tx, email string, item *TodoItem) error {
+	if _, err := tx.Exec("DELETE FROM search WHERE item_id = ?", item.ID); err != nil {
+		return err
+	}
+
+	for _, word := range SearchTokens(item) {
+		if _, err := tx.Exec("INSERT INTO search(email, word, item_id) VALUES(?, ?, ?)", email, word, item.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryTodoItems returns all items of query, rows are closed before return so that tx could be used
+func queryTodoItems(tx *sql.Tx, query string, args ...interface{}) ([]TodoItem, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []TodoItem{}
+	for rows.Next() {
+		item, err := scanTodoItem(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *item)
+	}
+
+	return items, rows.Err()
+}
+
+// checkRevision returns ErrNotFound if item not exists, ErrConflict if revision mismatch
+func checkRevision(tx *sql.Tx, email, itemID string, revision int) error {
+	var current int
+	if err := tx.QueryRow("SELECT revision FROM todos WHERE email = ? AND id = ?", email, itemID).Scan(&current); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if revision != 0 && current != revision {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+type sqliteListService struct {
+	storage *sqliteStorage
+}
+
+// list columns; should be same order with listValues() and scanList()
+var listColumns = []string{"id", "name", "created_at", "updated_at"}
+
+var (
+	sqlSelectList = "SELECT " + strings.Join(listColumns, ", ") + " FROM lists"
+	sqlInsertList = "INSERT INTO lists(email, " + strings.Join(listColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(listColumns)) + ")"
+	sqlUpdateList = "UPDATE lists SET " + strings.Join(listColumns, " = ?, ") + " = ? WHERE email = ? AND id = ?"
+)
+
+func listValues(list *List) []interface{} {
+	return []interface{}{list.ID, list.Name, formatTime(list.CreatedAt), formatTime(list.UpdatedAt)}
+}
+
+func scanList(row scanner) (*List, error) {
+	var list List
+	var createdAt, updatedAt string
+
+	if err := row.Scan(&list.ID, &list.Name, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if list.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+
+	if list.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// Create create list, user is created if not exists
+func (l *sqliteListService) Create(email string, list *List) error {
+	return withTx(l.storage.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO users(email) VALUES(?)", email); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(sqlInsertList, append([]interface{}{email}, listValues(list)...)...)
+		return err
+	})
+}
+
+func (l *sqliteListService) List(email string) ([]List, error) {
+	rows, err := l.storage.db.Query(sqlSelectList+" WHERE email = ?", email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []List{}
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		lists = append(lists, *list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortLists(lists)
+
+	return lists, nil
+}
+
+func (l *sqliteListService) Get(email, listID string) (*List, error) {
+	list, err := scanList(l.storage.db.QueryRow(sqlSelectList+" WHERE email = ? AND id = ?", email, listID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (l *sqliteListService) Update(email string, list *List) error {
+	result, err := l.storage.db.Exec(sqlUpdateList, append(listValues(list), email, list.ID)...)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+// Delete delete list; returns ErrNotEmpty if list has items and not cascade
+func (l *sqliteListService) Delete(email, listID string, cascade bool) error {
+	return withTx(l.storage.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec("DELETE FROM lists WHERE email = ? AND id = ?", email, listID)
+		if err != nil {
+			return err
+		}
+
+		if err := rowsAffected(result); err != nil {
+			return err
+		}
+
+		if !cascade {
+			var n int
+			if err := tx.QueryRow("SELECT COUNT(*) FROM todos WHERE email = ? AND list_id = ?", email, listID).Scan(&n); err != nil {
+				return err
+			}
+
+			if n > 0 {
+				return ErrNotEmpty
+			}
+		}
+
+		// subtasks are in same list with their parent, detach others if any
+		children, err := queryTodoItems(tx, sqlSelectTodo+` WHERE email = ? AND list_id != ?
+			AND parent_id IN (SELECT id FROM todos WHERE email = ? AND list_id = ?)`, email, listID, email, listID)
+		if err != nil {
+			return err
+		}
+
+		for i := range children {
+			children[i].Detach()
+			if err := update(tx, email, &children[i]); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("DELETE FROM todos WHERE email = ? AND list_id = ?", email, listID)
+		return err
+	})
+}
+
+const sqlSelectMember = "SELECT m.list_id, l.email, m.email, m.role FROM members m JOIN lists l ON l.id = m.list_id"
+
+func (l *sqliteListService) SetMember(member *Member) error {
+	return withTx(l.storage.db, func(tx *sql.Tx) error {
+		var n int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM lists WHERE email = ? AND id = ?", member.Owner, member.ListID).Scan(&n); err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return ErrNotFound
+		}
+
+		_, err := tx.Exec(`INSERT INTO members(list_id, email, role) VALUES(?, ?, ?)
+			ON CONFLICT(list_id, email) DO UPDATE SET role = excluded.role`, member.ListID, member.Email, member.Role)
+		return err
+	})
+}
+
+func (l *sqliteListService) Members(email, listID string) ([]Member, error) {
+	return l.queryMembers(sqlSelectMember+" WHERE l.email = ? AND m.list_id = ? ORDER BY m.email", email, listID)
+}
+
+func (l *sqliteListService) DeleteMember(email, listID, memberEmail string) error {
+	result, err := l.storage.db.Exec("DELETE FROM members WHERE list_id = (SELECT id FROM lists WHERE email = ? AND id = ?) AND email = ?",
+		email, listID, memberEmail)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+func (l *sqliteListService) Memberships(email string) ([]Member, error) {
+	return l.queryMembers(sqlSelectMember+" WHERE m.email = ? ORDER BY m.list_id", email)
+}
+
+func (l *sqliteListService) queryMembers(query string, args ...interface{}) ([]Member, error) {
+	rows, err := l.storage.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []Member{}
+	for rows.Next() {
+		var member Member
+		if err := rows.Scan(&member.ListID, &member.Owner, &member.Email, &member.Role); err != nil {
+			return nil, err
+		}
+
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// sqliteDependencyService stores dependencies, which are deleted with their items by foreign key cascade
+type sqliteDependencyService struct {
+	storage *sqliteStorage
+}
+
+func (d *sqliteDependencyService) Add(email string, dep *Dependency) error {
+	return withTx(d.storage.db, func(tx *sql.Tx) error {
+		var n int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM todos WHERE email = ? AND id IN (?, ?)", email, dep.ItemID, dep.DependsOn).Scan(&n); err != nil {
+			return err
+		}
+
+		want := 2
+		if dep.ItemID == dep.DependsOn {
+			want = 1
+		}
+
+		if n != want {
+			return ErrNotFound
+		}
+
+		deps, err := d.query(tx, email)
+		if err != nil {
+			return err
+		}
+
+		if err := CheckDependency(deps, dep); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("INSERT OR IGNORE INTO dependencies(email, item_id, depends_on) VALUES(?, ?, ?)", email, dep.ItemID, dep.DependsOn)
+		return err
+	})
+}
+
+func (d *sqliteDependencyService) List(email string) ([]Dependency, error) {
+	var deps []Dependency
+
+	if err := withTx(d.storage.db, func(tx *sql.Tx) (err error) {
+		deps, err = d.query(tx, email)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+func (d *sqliteDependencyService) query(tx *sql.Tx, email string) ([]Dependency, error) {
+	rows, err := tx.Query("SELECT item_id, depends_on FROM dependencies WHERE email = ? ORDER BY item_id, depends_on", email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deps := []Dependency{}
+	for rows.Next() {
+		var dep Dependency
+		if err := rows.Scan(&dep.ItemID, &dep.DependsOn); err != nil {
+			return nil, err
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps, rows.Err()
+}
+
+func (d *sqliteDependencyService) Delete(email string, dep *Dependency) error {
+	result, err := d.storage.db.Exec("DELETE FROM dependencies WHERE email = ? AND item_id = ? AND depends_on = ?", email, dep.ItemID, dep.DependsOn)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+type sqliteFilterService struct {
+	storage *sqliteStorage
+}
+
+// filter columns; should be same order with filterValues() and scanFilter()
+var filterColumns = []string{"id", "name", "query", "created_at", "updated_at"}
+
+var (
+	sqlSelectFilter = "SELECT " + strings.Join(filterColumns, ", ") + " FROM filters"
+	sqlInsertFilter = "INSERT INTO filters(email, " + strings.Join(filterColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(filterColumns)) + ")"
+	sqlUpdateFilter = "UPDATE filters SET " + strings.Join(filterColumns, " = ?, ") + " = ? WHERE email = ? AND id = ?"
+)
+
+func filterValues(filter *Filter) []interface{} {
+	return []interface{}{filter.ID, filter.Name, filter.Query, formatTime(filter.CreatedAt), formatTime(filter.UpdatedAt)}
+}
+
+func scanFilter(row scanner) (*Filter, error) {
+	var filter Filter
+	var createdAt, updatedAt string
+
+	if err := row.Scan(&filter.ID, &filter.Name, &filter.Query, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if filter.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+
+	if filter.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+// Create create filter, user is created if not exists
+func (f *sqliteFilterService) Create(email string, filter *Filter) error {
+	return withTx(f.storage.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO users(email) VALUES(?)", email); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(sqlInsertFilter, append([]interface{}{email}, filterValues(filter)...)...)
+		return err
+	})
+}
+
+func (f *sqliteFilterService) List(email string) ([]Filter, error) {
+	rows, err := f.storage.db.Query(sqlSelectFilter+" WHERE email = ?", email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	filters := []Filter{}
+	for rows.Next() {
+		filter, err := scanFilter(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, *filter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortFilters(filters)
+
+	return filters, nil
+}
+
+func (f *sqliteFilterService) Get(email, filterID string) (*Filter, error) {
+	filter, err := scanFilter(f.storage.db.QueryRow(sqlSelectFilter+" WHERE email = ? AND id = ?", email, filterID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (f *sqliteFilterService) Update(email string, filter *Filter) error {
+	result, err := f.storage.db.Exec(sqlUpdateFilter, append(filterValues(filter), email, filter.ID)...)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+func (f *sqliteFilterService) Delete(email, filterID string) error {
+	result, err := f.storage.db.Exec("DELETE FROM filters WHERE email = ? AND id = ?", email, filterID)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+// sqliteSearchService looks up candidate items by search table of words
+type sqliteSearchService struct {
+	storage *sqliteStorage
+}
+
+// Search select items which have words beginning with every term, then scores them
+func (s *sqliteSearchService) Search(email string, query string, limit int) ([]SearchResult, error) {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	// words beginning with term are in range [term, term + max rune)
+	matches := []string{}
+	args := []interface{}{email}
+	for _, term := range terms {
+		matches = append(matches, "SELECT item_id FROM search WHERE email = ? AND word >= ? AND word < ?")
+		args = append(args, email, term, term+string(utf8.MaxRune))
+	}
+
+	rows, err := s.storage.db.Query(sqlSelectTodo+" WHERE email = ? AND id IN ("+strings.Join(matches, " INTERSECT ")+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []TodoItem{}
+	for rows.Next() {
+		item, err := scanTodoItem(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return Search(items, terms, limit), nil
+}
+
+// Rebuild rebuild search index of all items in one transaction
+func (s *sqliteSearchService) Rebuild() error {
+	return withTx(s.storage.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM search"); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query("SELECT email, " + strings.Join(todoColumns, ", ") + " FROM todos")
+		if err != nil {
+			return err
+		}
+
+		type owned struct {
+			email string
+			item  *TodoItem
+		}
+
+		items := []owned{}
+		for rows.Next() {
+			var email string
+			item, err := scanTodoItem(&emailScanner{rows, &email})
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			items = append(items, owned{email, item})
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, o := range items {
+			if err := indexSearch(tx, o.email, o.item); err != nil {
+				return errors.Wrapf(err, "index %s", o.item.ID)
+			}
+		}
+
+		log.Infof("rebuild search index of %d items", len(items))
+
+		return nil
+	})
+}
+
+// emailScanner scans email column before columns of todo item
+type emailScanner struct {
+	scanner
+	email *string
+}
+
+func (s *emailScanner) Scan(dest ...interface{}) error {
+	return s.scanner.Scan(append([]interface{}{s.email}, dest...)...)
+}
+
+// sqliteAttachmentService stores attachments, which are deleted with their item or user by foreign keys
+type sqliteAttachmentService struct {
+	storage *sqliteStorage
+}
+
+// attachment columns; should be same order with attachmentValues() and scanAttachment()
+var attachmentColumns = []string{"id", "item_id", "name", "content_type", "size", "hash", "created_by", "created_at"}
+
+var (
+	sqlSelectAttachment = "SELECT " + strings.Join(attachmentColumns, ", ") + " FROM attachments"
+	sqlInsertAttachment = "INSERT INTO attachments(email, " + strings.Join(attachmentColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(attachmentColumns)) + ")"
+)
+
+func attachmentValues(attachment *Attachment) []interface{} {
+	return []interface{}{attachment.ID, attachment.ItemID, attachment.Name, attachment.ContentType, attachment.Size,
+		attachment.Hash, attachment.CreatedBy, formatTime(attachment.CreatedAt)}
+}
+
+func scanAttachment(row scanner) (*Attachment, error) {
+	var attachment Attachment
+	var createdAt string
+
+	if err := row.Scan(&attachment.ID, &attachment.ItemID, &attachment.Name, &attachment.ContentType, &attachment.Size,
+		&attachment.Hash, &attachment.CreatedBy, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if attachment.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// Create check item and quota, then insert attachment in one transaction
+func (a *sqliteAttachmentService) Create(email string, attachment *Attachment, quota int64) error {
+	return withTx(a.storage.db, func(tx *sql.Tx) error {
+		if err := checkItem(tx, email, attachment.ItemID); err != nil {
+			return err
+		}
+
+		usage, err := usage(tx, email)
+		if err != nil {
+			return err
+		}
+
+		if err := CheckQuota(usage, attachment.Size, quota); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(sqlInsertAttachment, append([]interface{}{email}, attachmentValues(attachment)...)...)
+		return err
+	})
+}
+
+func (a *sqliteAttachmentService) List(email, itemID string) ([]Attachment, error) {
+	rows, err := a.storage.db.Query(sqlSelectAttachment+" WHERE email = ? AND item_id = ?", email, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, *attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortAttachments(attachments)
+
+	return attachments, nil
+}
+
+func (a *sqliteAttachmentService) Get(email, itemID, attachmentID string) (*Attachment, error) {
+	attachment, err := scanAttachment(a.storage.db.QueryRow(sqlSelectAttachment+" WHERE email = ? AND item_id = ? AND id = ?",
+		email, itemID, attachmentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (a *sqliteAttachmentService) Delete(email, itemID, attachmentID string) error {
+	result, err := a.storage.db.Exec("DELETE FROM attachments WHERE email = ? AND item_id = ? AND id = ?", email, itemID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+func (a *sqliteAttachmentService) Usage(email string) (int64, error) {
+	return usage(a.storage.db, email)
+}
+
+func (a *sqliteAttachmentService) Referenced(hash string) (bool, error) {
+	var referenced bool
+	if err := a.storage.db.QueryRow("SELECT EXISTS(SELECT 1 FROM attachments WHERE hash = ?)", hash).Scan(&referenced); err != nil {
+		return false, err
+	}
+
+	return referenced, nil
+}
+
+// queryer is common interface of sql.DB and sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkItem returns ErrNotFound if user's item not found
+func checkItem(q queryer, email, itemID string) error {
+	var n int
+	if err := q.QueryRow("SELECT COUNT(*) FROM todos WHERE email = ? AND id = ?", email, itemID).Scan(&n); err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// usage returns total size of user's attachments
+func usage(q queryer, email string) (int64, error) {
+	var usage int64
+	if err := q.QueryRow("SELECT COALESCE(SUM(size), 0) FROM attachments WHERE email = ?", email).Scan(&usage); err != nil {
+		return 0, err
+	}
+
+	return usage, nil
+}
+
+// sqliteCommentService stores comments, which are deleted with their item or user by foreign keys
+type sqliteCommentService struct {
+	storage *sqliteStorage
+}
+
+// comment columns; should be same order with commentValues() and scanComment()
+var commentColumns = []string{"id", "item_id", "body", "author", "created_at", "updated_at"}
+
+var (
+	sqlSelectComment = "SELECT " + strings.Join(commentColumns, ", ") + " FROM comments"
+	sqlInsertComment = "INSERT INTO comments(email, " + strings.Join(commentColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(commentColumns)) + ")"
+)
+
+func commentValues(comment *Comment) []interface{} {
+	return []interface{}{comment.ID, comment.ItemID, comment.Body, comment.Author,
+		formatTime(comment.CreatedAt), formatTime(comment.UpdatedAt)}
+}
+
+func scanComment(row scanner) (*Comment, error) {
+	var comment Comment
+	var createdAt, updatedAt string
+
+	if err := row.Scan(&comment.ID, &comment.ItemID, &comment.Body, &comment.Author, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if comment.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if comment.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (c *sqliteCommentService) Create(email string, comment *Comment) error {
+	return withTx(c.storage.db, func(tx *sql.Tx) error {
+		if err := checkItem(tx, email, comment.ItemID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(sqlInsertComment, append([]interface{}{email}, commentValues(comment)...)...)
+		return err
+	})
+}
+
+func (c *sqliteCommentService) List(email, itemID string) ([]Comment, error) {
+	rows, err := c.storage.db.Query(sqlSelectComment+" WHERE email = ? AND item_id = ?", email, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, *comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortComments(comments)
+
+	return comments, nil
+}
+
+func (c *sqliteCommentService) Get(email, itemID, commentID string) (*Comment, error) {
+	comment, err := scanComment(c.storage.db.QueryRow(sqlSelectComment+" WHERE email = ? AND item_id = ? AND id = ?",
+		email, itemID, commentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (c *sqliteCommentService) Update(email string, comment *Comment) error {
+	result, err := c.storage.db.Exec("UPDATE comments SET body = ?, author = ?, created_at = ?, updated_at = ? WHERE email = ? AND item_id = ? AND id = ?",
+		comment.Body, comment.Author, formatTime(comment.CreatedAt), formatTime(comment.UpdatedAt), email, comment.ItemID, comment.ID)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+func (c *sqliteCommentService) Delete(email, itemID, commentID string) error {
+	result, err := c.storage.db.Exec("DELETE FROM comments WHERE email = ? AND item_id = ? AND id = ?", email, itemID, commentID)
+	if err != nil {
+		return err
+	}
+
+	return rowsAffected(result)
+}
+
+// sqliteActivityService stores activities, which are deleted with their item or user by foreign keys
+type sqliteActivityService struct {
+	storage *sqliteStorage
+}
+
+// activity columns; should be same order with activityValues() and scanActivity()
+var activityColumns = []string{"id", "item_id", "actor", "action", "fields", "created_at"}
+
+var (
+	sqlSelectActivity = "SELECT " + strings.Join(activityColumns, ", ") + " FROM activities"
+	sqlInsertActivity = "INSERT INTO activities(email, " + strings.Join(activityColumns, ", ") + ") VALUES(?" + strings.Repeat(", ?", len(activityColumns)) + ")"
+)
+
+// fields are JSON names of item, which have no comma
+func activityValues(activity *Activity) []interface{} {
+	return []interface{}{activity.ID, activity.ItemID, activity.Actor, activity.Action,
+		strings.Join(activity.Fields, ","), formatTime(activity.CreatedAt)}
+}
+
+func scanActivity(row scanner) (*Activity, error) {
+	var activity Activity
+	var fields, createdAt string
+
+	if err := row.Scan(&activity.ID, &activity.ItemID, &activity.Actor, &activity.Action, &fields, &createdAt); err != nil {
+		return nil, err
+	}
+
+	if fields != "" {
+		activity.Fields = strings.Split(fields, ",")
+	}
+
+	var err error
+	if activity.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+
+	return &activity, nil
+}
+
+func (a *sqliteActivityService) Add(email string, activity *Activity) error {
+	return withTx(a.storage.db, func(tx *sql.Tx) error {
+		if err := checkItem(tx, email, activity.ItemID); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(sqlInsertActivity, append([]interface{}{email}, activityValues(activity)...)...)
+		return err
+	})
+}
+
+func (a *sqliteActivityService) List(email, itemID string) ([]Activity, error) {
+	rows, err := a.storage.db.Query(sqlSelectActivity+" WHERE email = ? AND item_id = ?", email, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []Activity{}
+	for rows.Next() {
+		activity, err := scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		activities = append(activities, *activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	SortActivities(activities)
+
+	return activities, nil
+}
@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/whitekid/go-todo/storage/storagetest"
+	. "github.com/whitekid/go-todo/storage/types"
+	"github.com/whitekid/go-utils/fixtures"
+)
+
+func TestSQLite(t *testing.T) {
+	var dir string
+	defer fixtures.TempDir("", "testdb_", func(tempDir string) { dir = tempDir })()
+
+	s, err := New(filepath.Join(dir, "todo"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	todos := s.TodoService()
+	tokens := s.TokenService()
+
+	email := "whitekid@gmail.com"
+
+	require.NoError(t, tokens.Create(email, "refresh-token"))
+	{
+		got, err := tokens.Get("refresh-token")
+		require.NoError(t, err)
+		require.Equal(t, "refresh-token", got)
+
+		user, err := s.UserService().Get(email)
+		require.NoError(t, err)
+		require.Equal(t, email, user.Email)
+	}
+
+	item := TodoItem{
+		ID:      uuid.New().String(),
+		Title:   "title",
+		DueDate: Today(),
+		Rank:    1,
+	}
+	require.NoError(t, todos.Create(email, &item))
+
+	items, _, err := todos.List(email, nil)
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{item}, items)
+
+	got, err := todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, &item, got)
+
+	got.Title = "updated"
+	require.NoError(t, todos.Update(email, got, 0))
+	{
+		updated, err := todos.Get(email, item.ID)
+		require.NoError(t, err)
+		require.Equal(t, got, updated)
+	}
+
+	require.NoError(t, todos.Delete(email, got.ID, 0))
+	if _, err := todos.Get(email, item.ID); err != ErrNotFound {
+		require.Fail(t, "should error", "want = %v, got = %v", ErrNotFound, err)
+	}
+	require.Equal(t, ErrNotFound, todos.Update(email, got, 0))
+	require.Equal(t, ErrNotFound, todos.Delete(email, got.ID, 0))
+
+	{
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(items))
+	}
+}
+
+func TestUserDeleteCascade(t *testing.T) {
+	var dir string
+	defer fixtures.TempDir("", "testdb_", func(tempDir string) { dir = tempDir })()
+
+	s, err := New(filepath.Join(dir, "todo"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.TokenService().Create(email, "refresh-token"))
+
+	item := TodoItem{ID: uuid.New().String(), Title: "title"}
+	require.NoError(t, s.TodoService().Create(email, &item))
+
+	require.NoError(t, s.UserService().Delete(email))
+
+	_, err = s.UserService().Get(email)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.TokenService().Get("refresh-token")
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.TodoService().Get(email, item.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	// todo item requires its owner
+	require.Error(t, s.TodoService().Create(email, &item))
+}
+
+func TestFillSortKeys(t *testing.T) {
+	var dir string
+	defer fixtures.TempDir("", "testdb_", func(tempDir string) { dir = tempDir })()
+
+	name := filepath.Join(dir, "todo")
+	s, err := New(name)
+	require.NoError(t, err)
+
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.TokenService().Create(email, "refresh-token"))
+
+	items := []TodoItem{{ID: uuid.New().String(), Title: "b", Rank: 1}, {ID: uuid.New().String(), Title: "a", Rank: 2}}
+	for i := range items {
+		require.NoError(t, s.TodoService().Create(email, &items[i]))
+	}
+
+	// items written before sort keys have no sort keys
+	_, err = s.(*sqliteStorage).db.Exec("DELETE FROM sort_keys")
+	require.NoError(t, err)
+	s.Close()
+
+	s, err = New(name)
+	require.NoError(t, err)
+	defer s.Close()
+
+	got, next, err := s.TodoService().List(email, &ListOptions{Sort: SortTitle, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{items[1]}, got)
+
+	got, next, err = s.TodoService().List(email, &ListOptions{Sort: SortTitle, Limit: 1, Cursor: next})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{items[0]}, got)
+	require.Equal(t, "", next)
+}
+
+func TestSearchRebuild(t *testing.T) {
+	var dir string
+	defer fixtures.TempDir("", "testdb_", func(tempDir string) { dir = tempDir })()
+
+	s, err := New(filepath.Join(dir, "todo"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	email := "whitekid@gmail.com"
+	require.NoError(t, s.TokenService().Create(email, "refresh-token"))
+
+	item := TodoItem{ID: uuid.New().String(), Title: "Write report"}
+	require.NoError(t, s.TodoService().Create(email, &item))
+
+	// items written before search table are not indexed
+	_, err = s.(*sqliteStorage).db.Exec("DELETE FROM search")
+	require.NoError(t, err)
+
+	results, err := s.SearchService().Search(email, "report", 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	require.NoError(t, s.SearchService().Rebuild())
+
+	results, err = s.SearchService().Search(email, "report", 0)
+	require.NoError(t, err)
+	require.Equal(t, []SearchResult{{Item: item, Score: 2}}, results)
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) Interface {
+		s, err := New(filepath.Join(t.TempDir(), "todo"))
+		require.NoError(t, err)
+		return s
+	})
+}
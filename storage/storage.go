@@ -6,22 +6,111 @@ import (
 	"github.com/pkg/errors"
 	"github.com/whitekid/go-todo/config"
 	"github.com/whitekid/go-todo/storage/badger"
+	"github.com/whitekid/go-todo/storage/memory"
+	"github.com/whitekid/go-todo/storage/sqlite"
 	"github.com/whitekid/go-todo/storage/types"
 )
 
+const (
+	SortRank   = types.SortRank
+	StatusOpen = types.StatusOpen
+
+	OpCreate = types.OpCreate
+	OpUpdate = types.OpUpdate
+	OpDelete = types.OpDelete
+
+	MaxDepth     = types.MaxDepth
+	ChildCascade = types.ChildCascade
+	ChildBlock   = types.ChildBlock
+	ChildOrphan  = types.ChildOrphan
+
+	FreqDaily   = types.FreqDaily
+	FreqWeekly  = types.FreqWeekly
+	FreqMonthly = types.FreqMonthly
+
+	DueToday   = types.DueToday
+	DueOverdue = types.DueOverdue
+
+	PriorityLow    = types.PriorityLow
+	PriorityMedium = types.PriorityMedium
+	PriorityHigh   = types.PriorityHigh
+	PriorityUrgent = types.PriorityUrgent
+
+	MaxNotesSize = types.MaxNotesSize
+
+	ActionCreated   = types.ActionCreated
+	ActionUpdated   = types.ActionUpdated
+	ActionCompleted = types.ActionCompleted
+	ActionReopened  = types.ActionReopened
+	ActionMoved     = types.ActionMoved
+)
+
 var (
 	ErrNotFound         = types.ErrNotFound
 	ErrNotAuthenticated = types.ErrNotAuthenticated
+	ErrInvalidCursor    = types.ErrInvalidCursor
+	ErrConflict         = types.ErrConflict
+	ErrQuotaExceeded    = types.ErrQuotaExceeded
+	ErrAborted          = types.ErrAborted
+	ErrNotEmpty         = types.ErrNotEmpty
+	ErrCycle            = types.ErrCycle
+	ErrTooDeep          = types.ErrTooDeep
 
-	Today = types.Today
+	Today            = types.Today
+	TodayIn          = types.TodayIn
+	DateOf           = types.DateOf
+	ParseListOptions = types.ParseListOptions
+	RankBetween      = types.RankBetween
+	Rebalance        = types.Rebalance
+	SortLists        = types.SortLists
+	NormalizeTag     = types.NormalizeTag
+	NormalizeTags    = types.NormalizeTags
+	ValidChildPolicy = types.ValidChildPolicy
+	NewItemTree      = types.NewItemTree
+	CheckParent      = types.CheckParent
+	Descendants      = types.Descendants
+	BuildTree        = types.BuildTree
+	CheckDependency  = types.CheckDependency
+	SortByDue        = types.SortByDue
+	SearchTerms      = types.SearchTerms
+	ChangedFields    = types.ChangedFields
 )
 
 type (
 	Interface   = types.Interface
 	TodoStorage = types.TodoService
 	User        = types.User
+	UserService = types.UserService
+
+	TodoItem    = types.TodoItem
+	ListOptions = types.ListOptions
+	Operation   = types.Operation
+	Tag         = types.Tag
+	TreeItem    = types.TreeItem
+	ItemTree    = types.ItemTree
+	Date        = types.Date
+	Recurrence  = types.Recurrence
+	Link        = types.Link
+
+	List        = types.List
+	ListService = types.ListService
+
+	Dependency        = types.Dependency
+	DependencyService = types.DependencyService
+
+	Filter        = types.Filter
+	FilterService = types.FilterService
 
-	TodoItem = types.TodoItem
+	SearchResult  = types.SearchResult
+	SearchService = types.SearchService
+
+	Attachment        = types.Attachment
+	AttachmentService = types.AttachmentService
+
+	Comment         = types.Comment
+	CommentService  = types.CommentService
+	Activity        = types.Activity
+	ActivityService = types.ActivityService
 )
 
 // storage factories
@@ -33,6 +122,20 @@ var storages = map[string]factoryFunc{
 		}
 		return intf
 	},
+	memory.Name: func(name string) Interface {
+		intf, err := memory.New(name)
+		if err != nil {
+			panic(err)
+		}
+		return intf
+	},
+	sqlite.Name: func(name string) Interface {
+		intf, err := sqlite.New(name)
+		if err != nil {
+			panic(err)
+		}
+		return intf
+	},
 }
 
 type factoryFunc func(name string) Interface
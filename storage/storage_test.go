@@ -6,7 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// TODO 스토리지 구현체들의 전체 통합 테스트 필요하지요..
+// NOTE storage implementations are tested with storagetest.Run() in each package
 func TestStorage(t *testing.T) {
 	type args struct {
 	}
@@ -21,6 +21,7 @@ func TestStorage(t *testing.T) {
 			stg, err := New("testdb")
 			require.NoError(t, err)
 			require.NotNil(t, stg)
+			defer stg.Close()
 
 			require.NotNil(t, stg.UserService())
 			require.NotNil(t, stg.TokenService())
@@ -0,0 +1,1514 @@
+// Package storagetest provides conformance tests for storage implementations.
+// Every storage backend should pass Run()
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) types.Interface {
+//			s, err := New(...)
+//			require.NoError(t, err)
+//			return s
+//		})
+//	}
+package storagetest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	. "github.com/whitekid/go-todo/storage/types"
+	"github.com/whitekid/go-todo/tokens"
+)
+
+// Factory returns new empty storage for each test.
+// storage is closed by test
+type Factory func(t *testing.T) Interface
+
+// Run run storage conformance tests
+func Run(t *testing.T, factory Factory) {
+	tests := [...]struct {
+		name string
+		test func(t *testing.T, s Interface)
+	}{
+		{"User", testUser},
+		{"UserCreateExisting", testUserCreateExisting},
+		{"Token", testToken},
+		{"Todo", testTodo},
+		{"TodoNotFound", testTodoNotFound},
+		{"TodoList", testTodoList},
+		{"TodoCompleted", testTodoCompleted},
+		{"TodoTags", testTodoTags},
+		{"TodoParent", testTodoParent},
+		{"TodoDeleteParent", testTodoDeleteParent},
+		{"TodoRecurrence", testTodoRecurrence},
+		{"TodoDue", testTodoDue},
+		{"TodoPriority", testTodoPriority},
+		{"TodoSearch", testTodoSearch},
+		{"TodoNotes", testTodoNotes},
+		{"TodoRevision", testTodoRevision},
+		{"TodoBatch", testTodoBatch},
+		{"TodoIsolation", testTodoIsolation},
+		{"List", testList},
+		{"ListDelete", testListDelete},
+		{"ListMember", testListMember},
+		{"Dependency", testDependency},
+		{"Filter", testFilter},
+		{"Attachment", testAttachment},
+		{"Comment", testComment},
+		{"Activity", testActivity},
+		{"UserDeleteCascade", testUserDeleteCascade},
+		{"ConcurrentWriters", testConcurrentWriters},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := factory(t)
+			defer s.Close()
+
+			tt.test(t, s)
+		})
+	}
+}
+
+func newEmail() string { return uuid.New().String()[:8] + "@domain.com" }
+
+// newToken returns refresh token, token should be JWT token
+func newToken(t *testing.T, email string, duration time.Duration) string {
+	token, err := tokens.New(email, duration)
+	require.NoError(t, err)
+	return token
+}
+
+// newUser create user with refresh token
+func newUser(t *testing.T, s Interface) (string, string) {
+	email := newEmail()
+	token := newToken(t, email, time.Hour)
+	require.NoError(t, s.TokenService().Create(email, token))
+	return email, token
+}
+
+func newList(name string) *List {
+	now := time.Now().UTC().Round(0)
+	return &List{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func newFilter(name, query string) *Filter {
+	now := time.Now().UTC().Round(0)
+	return &Filter{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Query:     query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func newAttachment(itemID, name, content string) *Attachment {
+	return &Attachment{
+		ID:          uuid.New().String(),
+		ItemID:      itemID,
+		Name:        name,
+		ContentType: "text/plain",
+		Size:        int64(len(content)),
+		Hash:        content, // hash is opaque to storage
+		CreatedAt:   time.Now().UTC().Round(0),
+	}
+}
+
+func newComment(itemID, body string) *Comment {
+	now := time.Now().UTC().Round(0)
+	return &Comment{
+		ID:        uuid.New().String(),
+		ItemID:    itemID,
+		Body:      body,
+		Author:    "author@example.com",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func newItem(title string) *TodoItem {
+	return &TodoItem{
+		ID:      uuid.New().String(),
+		Title:   title,
+		DueDate: Today(),
+		Rank:    1,
+	}
+}
+
+func testUser(t *testing.T, s Interface) {
+	users := s.UserService()
+	email := newEmail()
+
+	_, err := users.Get(email)
+	require.Equal(t, ErrNotFound, err)
+
+	require.NoError(t, users.Create(&User{Email: email}))
+
+	got, err := users.Get(email)
+	require.NoError(t, err)
+	require.Equal(t, &User{Email: email}, got)
+
+	require.NoError(t, users.Update(&User{Email: email, TimeZone: "Asia/Seoul"}))
+	got, err = users.Get(email)
+	require.NoError(t, err)
+	require.Equal(t, &User{Email: email, TimeZone: "Asia/Seoul"}, got)
+
+	require.Equal(t, ErrNotFound, users.Update(&User{Email: newEmail(), TimeZone: "Asia/Seoul"}))
+
+	require.NoError(t, users.Delete(email))
+	_, err = users.Get(email)
+	require.Equal(t, ErrNotFound, err)
+
+	require.Equal(t, ErrNotFound, users.Delete(email))
+}
+
+// creating existing user updates the user, without deleting the user's data
+func testUserCreateExisting(t *testing.T, s Interface) {
+	email, token := newUser(t, s)
+
+	item := newItem("title")
+	require.NoError(t, s.TodoService().Create(email, item))
+	list := newList("list")
+	require.NoError(t, s.ListService().Create(email, list))
+
+	require.NoError(t, s.UserService().Create(&User{Email: email, TimeZone: "Asia/Seoul"}))
+
+	user, err := s.UserService().Get(email)
+	require.NoError(t, err)
+	require.Equal(t, &User{Email: email, TimeZone: "Asia/Seoul"}, user)
+
+	_, err = s.TokenService().Get(token)
+	require.NoError(t, err)
+
+	got, err := s.TodoService().Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, item, got)
+
+	_, err = s.ListService().Get(email, list.ID)
+	require.NoError(t, err)
+}
+
+func testToken(t *testing.T, s Interface) {
+	tokens := s.TokenService()
+	email := newEmail()
+	token := newToken(t, email, time.Hour)
+	token2 := newToken(t, email, time.Hour*2)
+
+	_, err := tokens.Get(token)
+	require.Equal(t, ErrNotFound, err)
+
+	// user is created with token
+	require.NoError(t, tokens.Create(email, token))
+	user, err := s.UserService().Get(email)
+	require.NoError(t, err)
+	require.Equal(t, email, user.Email)
+
+	got, err := tokens.Get(token)
+	require.NoError(t, err)
+	require.Equal(t, token, got)
+
+	// multiple tokens per user
+	require.NoError(t, tokens.Create(email, token2))
+	{
+		got, err := tokens.Get(token2)
+		require.NoError(t, err)
+		require.Equal(t, token2, got)
+	}
+
+	require.NoError(t, tokens.Delete(token))
+	_, err = tokens.Get(token)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, tokens.Delete(token))
+
+	{
+		got, err := tokens.Get(token2)
+		require.NoError(t, err, "other token should not be deleted")
+		require.Equal(t, token2, got)
+	}
+}
+
+func testTodo(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	{
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.NotNil(t, items)
+		require.Equal(t, 0, len(items))
+	}
+
+	item := newItem("title")
+	require.NoError(t, todos.Create(email, item))
+
+	other := newItem("other title")
+	other.Rank = 2
+	require.NoError(t, todos.Create(email, other))
+
+	{
+		got, err := todos.Get(email, item.ID)
+		require.NoError(t, err)
+		require.Equal(t, item, got)
+	}
+
+	{
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []TodoItem{*item, *other}, items)
+	}
+
+	updated := *item
+	updated.Title = "updated title"
+	updated.Rank = 10
+	updated.DueDate.Time = updated.DueDate.AddDate(0, 0, 1)
+	require.NoError(t, todos.Update(email, &updated, 0))
+	{
+		got, err := todos.Get(email, item.ID)
+		require.NoError(t, err)
+		require.Equal(t, &updated, got)
+
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []TodoItem{updated, *other}, items)
+	}
+
+	require.NoError(t, todos.Delete(email, item.ID, 0))
+	{
+		_, err := todos.Get(email, item.ID)
+		require.Equal(t, ErrNotFound, err)
+
+		items, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.Equal(t, []TodoItem{*other}, items)
+	}
+}
+
+func testTodoNotFound(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+	item := newItem("title")
+
+	_, err := todos.Get(email, item.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, todos.Update(email, item, 0))
+	require.Equal(t, ErrNotFound, todos.Delete(email, item.ID, 0))
+
+	// update should not create item
+	_, err = todos.Get(email, item.ID)
+	require.Equal(t, ErrNotFound, err)
+}
+
+func testTodoList(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	now := time.Now().UTC().Round(0)
+	today := Today()
+	items := []*TodoItem{}
+	for i, title := range []string{"b", "D", "a", "c", "e"} {
+		item := newItem(title)
+		item.Rank = 2 - i
+		item.DueDate.Time = today.AddDate(0, 0, i)
+		item.CreatedAt = now.Add(time.Duration(-i) * time.Second)
+		item.UpdatedAt = now.Add(time.Duration(i) * time.Second)
+		item.Revision = i + 1
+		items = append(items, item)
+	}
+	items[4].DueDate = Date{}
+
+	for _, item := range items {
+		require.NoError(t, todos.Create(email, item))
+	}
+
+	day := func(days int) *Date { return &Date{Time: today.AddDate(0, 0, days)} }
+
+	tests := [...]struct {
+		name string
+		opts ListOptions
+		want []int
+	}{
+		{"default", ListOptions{}, []int{4, 3, 2, 1, 0}},
+		{"rank", ListOptions{Sort: SortRank}, []int{4, 3, 2, 1, 0}},
+		{"due date", ListOptions{Sort: SortDueDate}, []int{0, 1, 2, 3, 4}},
+		{"title", ListOptions{Sort: SortTitle}, []int{2, 0, 3, 1, 4}},
+		{"created", ListOptions{Sort: SortCreated}, []int{4, 3, 2, 1, 0}},
+		{"updated", ListOptions{Sort: SortUpdated}, []int{0, 1, 2, 3, 4}},
+		{"due before", ListOptions{Sort: SortDueDate, DueBefore: day(2)}, []int{0, 1}},
+		{"due after", ListOptions{Sort: SortDueDate, DueAfter: day(1)}, []int{2, 3}},
+		{"due between", ListOptions{Sort: SortDueDate, DueAfter: day(0), DueBefore: day(3)}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []TodoItem{}
+			for _, i := range tt.want {
+				want = append(want, *items[i])
+			}
+
+			got, next, err := todos.List(email, &tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, "", next)
+			require.Equal(t, want, got)
+
+			// walk pages; should returns same items
+			for _, limit := range []int{1, 2, 5} {
+				opts := tt.opts
+				opts.Limit = limit
+				paged := []TodoItem{}
+				for pages := 0; ; pages++ {
+					require.True(t, pages <= len(want), "too many pages")
+
+					page, next, err := todos.List(email, &opts)
+					require.NoError(t, err)
+					require.True(t, len(page) <= limit)
+					paged = append(paged, page...)
+
+					if next == "" {
+						break
+					}
+					opts.Cursor = next
+				}
+				require.Equal(t, want, paged, "limit=%d", limit)
+			}
+		})
+	}
+
+	// deleted item does not break cursor
+	page, next, err := todos.List(email, &ListOptions{Sort: SortDueDate, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{*items[0], *items[1]}, page)
+	require.NoError(t, todos.Delete(email, items[1].ID, 0))
+
+	page, _, err = todos.List(email, &ListOptions{Sort: SortDueDate, Limit: 2, Cursor: next})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{*items[2], *items[3]}, page)
+
+	_, _, err = todos.List(email, &ListOptions{Cursor: "invalid cursor"})
+	require.Equal(t, ErrInvalidCursor, err)
+}
+
+func testTodoCompleted(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	open := newItem("open")
+	require.NoError(t, todos.Create(email, open))
+
+	completed := newItem("completed")
+	completed.Complete(time.Now().UTC().Round(0))
+	require.NoError(t, todos.Create(email, completed))
+
+	got, err := todos.Get(email, completed.ID)
+	require.NoError(t, err)
+	require.Equal(t, completed, got)
+
+	tests := [...]struct {
+		name   string
+		status string
+		want   []TodoItem
+	}{
+		{"default", "", []TodoItem{*open, *completed}},
+		{"all", StatusAll, []TodoItem{*open, *completed}},
+		{"open", StatusOpen, []TodoItem{*open}},
+		{"completed", StatusCompleted, []TodoItem{*completed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, _, err := todos.List(email, &ListOptions{Status: tt.status})
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, items)
+		})
+	}
+
+	// reopen
+	completed.Reopen()
+	require.NoError(t, todos.Update(email, completed, 0))
+	got, err = todos.Get(email, completed.ID)
+	require.NoError(t, err)
+	require.Equal(t, completed, got)
+	require.Nil(t, got.CompletedAt)
+
+	items, _, err := todos.List(email, &ListOptions{Status: StatusCompleted})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+}
+
+func testTodoTags(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	items := map[string]*TodoItem{}
+	for title, tags := range map[string][]string{
+		"work":        {"work"},
+		"urgent work": {"urgent", "work"},
+		"home":        {"home"},
+		"none":        nil,
+	} {
+		item := newItem(title)
+		item.Tags = tags
+		require.NoError(t, todos.Create(email, item))
+		items[title] = item
+	}
+
+	tags, err := todos.Tags(email)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{Name: "home", Count: 1}, {Name: "urgent", Count: 1}, {Name: "work", Count: 2}}, tags)
+
+	tests := [...]struct {
+		name   string
+		filter string
+		want   []string
+	}{
+		{"single", "work", []string{"urgent work", "work"}},
+		{"and", "work,urgent", []string{"urgent work"}},
+		{"or", "urgent|home", []string{"home", "urgent work"}},
+		{"and or", "work,urgent|home", []string{"home", "urgent work"}},
+		{"unknown", "unknown", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := todos.List(email, &ListOptions{Sort: SortTitle, Tags: ParseTagFilter(tt.filter)})
+			require.NoError(t, err)
+
+			titles := []string{}
+			for _, item := range got {
+				titles = append(titles, item.Title)
+			}
+			require.Equal(t, tt.want, titles)
+		})
+	}
+
+	// tags are reindexed on update
+	item := items["urgent work"]
+	item.Tags = []string{"home"}
+	require.NoError(t, todos.Update(email, item, 0))
+
+	got, _, err := todos.List(email, &ListOptions{Tags: ParseTagFilter("urgent")})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	require.NoError(t, todos.Delete(email, items["home"].ID, 0))
+	tags, err = todos.Tags(email)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{{Name: "home", Count: 1}, {Name: "work", Count: 1}}, tags)
+
+	// pagination
+	got, next, err := todos.List(email, &ListOptions{Sort: SortTitle, Limit: 1, Tags: ParseTagFilter("work|home")})
+	require.NoError(t, err)
+	require.Equal(t, "urgent work", got[0].Title)
+	require.NotEqual(t, "", next)
+
+	got, next, err = todos.List(email, &ListOptions{Sort: SortTitle, Limit: 1, Cursor: next, Tags: ParseTagFilter("work|home")})
+	require.NoError(t, err)
+	require.Equal(t, "work", got[0].Title)
+	require.Equal(t, "", next)
+}
+
+func testTodoParent(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	parent := newItem("parent")
+	require.NoError(t, todos.Create(email, parent))
+
+	for _, title := range []string{"b child", "a child"} {
+		item := newItem(title)
+		item.ParentID = parent.ID
+		require.NoError(t, todos.Create(email, item))
+	}
+
+	got, err := todos.Get(email, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, "", got.ParentID)
+
+	children, _, err := todos.List(email, &ListOptions{Sort: SortTitle, ParentID: parent.ID})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(children))
+	require.Equal(t, "a child", children[0].Title)
+	require.Equal(t, parent.ID, children[0].ParentID)
+
+	// parent is reindexed on update
+	child := children[0]
+	child.ParentID = ""
+	require.NoError(t, todos.Update(email, &child, 0))
+
+	children, _, err = todos.List(email, &ListOptions{ParentID: parent.ID})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(children))
+	require.Equal(t, "b child", children[0].Title)
+}
+
+func testTodoDeleteParent(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	lists := s.ListService()
+	email, _ := newUser(t, s)
+
+	// a > b > c, a > d
+	newChild := func(title, parentID string) *TodoItem {
+		item := newItem(title)
+		item.ParentID = parentID
+		require.NoError(t, todos.Create(email, item))
+		return item
+	}
+	a := newChild("a", "")
+	b := newChild("b", a.ID)
+	c := newChild("c", b.ID)
+	d := newChild("d", a.ID)
+
+	errs, err := todos.Batch(email, []Operation{{Op: OpDelete, ItemID: a.ID, Children: ChildBlock}}, true)
+	require.NoError(t, err)
+	require.Equal(t, []error{ErrNotEmpty}, errs)
+	_, err = todos.Get(email, a.ID)
+	require.NoError(t, err)
+
+	// subtasks are detached by default
+	require.NoError(t, todos.Delete(email, b.ID, 0))
+	got, err := todos.Get(email, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, "", got.ParentID)
+	require.Equal(t, c.Revision+1, got.Revision)
+	children, _, err := todos.List(email, &ListOptions{ParentID: b.ID})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(children))
+
+	errs, err = todos.Batch(email, []Operation{{Op: OpDelete, ItemID: a.ID, Children: ChildCascade}}, true)
+	require.NoError(t, err)
+	require.Equal(t, []error{nil}, errs)
+	_, err = todos.Get(email, d.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	items, _, err := todos.List(email, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(items))
+	require.Equal(t, c.ID, items[0].ID)
+
+	// subtask out of deleted list is detached
+	list := newList("work")
+	require.NoError(t, lists.Create(email, list))
+	parent := newItem("parent")
+	parent.ListID = list.ID
+	require.NoError(t, todos.Create(email, parent))
+	child := newChild("child", parent.ID)
+
+	require.NoError(t, lists.Delete(email, list.ID, true))
+	got, err = todos.Get(email, child.ID)
+	require.NoError(t, err)
+	require.Equal(t, "", got.ParentID)
+	children, _, err = todos.List(email, &ListOptions{ParentID: parent.ID})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(children))
+}
+
+func testTodoRecurrence(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	until := Today().AddDays(30)
+	item := newItem("recurring")
+	item.Recurrence = &Recurrence{Freq: FreqWeekly, Interval: 2, Weekdays: []string{"MO", "FR"}, Until: &until}
+	require.NoError(t, todos.Create(email, item))
+
+	got, err := todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, item.Recurrence, got.Recurrence)
+
+	// stored recurrence is not changed by caller
+	item.Recurrence.Weekdays[0] = "TU"
+	got, err = todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"MO", "FR"}, got.Recurrence.Weekdays)
+
+	got.Recurrence = nil
+	require.NoError(t, todos.Update(email, got, 0))
+	got, err = todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Nil(t, got.Recurrence)
+}
+
+func testTodoDue(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	// today differs from UTC for most of the day
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	today := TodayIn(loc)
+
+	items := []*TodoItem{}
+	for _, due := range []struct {
+		days      int
+		time      string
+		completed bool
+	}{
+		{-1, "", false},
+		{-1, "", true},
+		{0, "00:00", false},
+		{0, "", false},
+		{1, "09:00", false},
+	} {
+		item := newItem("due")
+		item.DueDate = today.AddDays(due.days)
+		item.DueTime = due.time
+		if due.completed {
+			item.Complete(time.Now().UTC().Round(0))
+		}
+		require.NoError(t, todos.Create(email, item))
+		items = append(items, item)
+	}
+
+	got, err := todos.Get(email, items[2].ID)
+	require.NoError(t, err)
+	require.Equal(t, "00:00", got.DueTime)
+
+	tests := [...]struct {
+		name string
+		due  string
+		want []int
+	}{
+		{"today", DueToday, []int{3, 2}},
+		{"overdue", DueOverdue, []int{0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := todos.List(email, &ListOptions{Sort: SortDueDate, Due: tt.due, Location: loc})
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, item := range got {
+				ids = append(ids, item.ID)
+			}
+
+			want := []string{}
+			for _, i := range tt.want {
+				want = append(want, items[i].ID)
+			}
+			require.Equal(t, want, ids)
+		})
+	}
+}
+
+func testTodoPriority(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	items := []*TodoItem{}
+	for i, priority := range []string{"", PriorityHigh, PriorityLow, PriorityUrgent, PriorityHigh} {
+		item := newItem(priority)
+		item.Priority = priority
+		item.Rank = -i
+		require.NoError(t, todos.Create(email, item))
+		items = append(items, item)
+	}
+
+	got, err := todos.Get(email, items[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, PriorityHigh, got.Priority)
+
+	want := []string{items[3].ID, items[4].ID, items[1].ID, items[2].ID, items[0].ID}
+	for _, limit := range []int{0, 2} {
+		ids := []string{}
+		opts := &ListOptions{Sort: SortPriority, Limit: limit}
+		for {
+			page, next, err := todos.List(email, opts)
+			require.NoError(t, err)
+			for _, item := range page {
+				ids = append(ids, item.ID)
+			}
+
+			if next == "" {
+				break
+			}
+			opts.Cursor = next
+		}
+		require.Equal(t, want, ids)
+	}
+}
+
+func testTodoSearch(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	search := s.SearchService()
+	email, _ := newUser(t, s)
+
+	report := newItem("Write quarterly report")
+	milk := newItem("Buy milk")
+	reply := newItem("Reply to reporters")
+	require.NoError(t, todos.Create(email, report))
+	require.NoError(t, todos.Create(email, milk))
+	require.NoError(t, todos.Create(email, reply))
+
+	ids := func(query string) []string {
+		results, err := search.Search(email, query, 0)
+		require.NoError(t, err)
+
+		ids := []string{}
+		for _, result := range results {
+			ids = append(ids, result.Item.ID)
+		}
+		return ids
+	}
+
+	require.Equal(t, []string{report.ID, reply.ID}, ids("report"))
+	require.Equal(t, []string{report.ID}, ids("QUART rep"))
+	require.Equal(t, []string{}, ids("report milk"))
+	require.Equal(t, []string{}, ids(""))
+
+	results, err := search.Search(email, "re", 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, *reply, results[0].Item)
+
+	// index is updated with item
+	milk.Title = "Buy oat milk"
+	require.NoError(t, todos.Update(email, milk, 0))
+	require.Equal(t, []string{milk.ID}, ids("oat"))
+
+	report.Title = "Write summary"
+	require.NoError(t, todos.Update(email, report, 0))
+	require.Equal(t, []string{reply.ID}, ids("report"))
+
+	require.NoError(t, todos.Delete(email, reply.ID, 0))
+	require.Equal(t, []string{}, ids("report"))
+
+	// items of other user is not searched
+	other, _ := newUser(t, s)
+	results, err = search.Search(other, "milk", 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+
+	require.NoError(t, search.Rebuild())
+	require.Equal(t, []string{milk.ID}, ids("milk"))
+	require.Equal(t, []string{report.ID}, ids("summary"))
+}
+
+func testTodoNotes(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	item := newItem("read")
+	item.Notes = "see [spec](https://example.com/spec) for *details*"
+	item.Links = []Link{{URL: "https://example.com/spec", Text: "spec"}}
+	require.NoError(t, todos.Create(email, item))
+
+	got, err := todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, item, got)
+
+	// notes are searched
+	results, err := s.SearchService().Search(email, "details", 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(results))
+	require.Equal(t, item.ID, results[0].Item.ID)
+
+	got.Notes, got.Links = "", nil
+	require.NoError(t, todos.Update(email, got, 0))
+	got, err = todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, "", got.Notes)
+	require.Nil(t, got.Links)
+
+	results, err = s.SearchService().Search(email, "details", 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(results))
+}
+
+func testTodoRevision(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	item := newItem("title")
+	item.Revision = 1
+	require.NoError(t, todos.Create(email, item))
+
+	// update with stale revision
+	stale := *item
+	stale.Title = "stale"
+	stale.Revision = 2
+	require.Equal(t, ErrConflict, todos.Update(email, &stale, 2))
+
+	updated := *item
+	updated.Title = "updated"
+	updated.Revision = 2
+	require.NoError(t, todos.Update(email, &updated, 1))
+	require.Equal(t, ErrConflict, todos.Update(email, &stale, 1), "revision already changed")
+
+	got, err := todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, &updated, got)
+
+	require.Equal(t, ErrConflict, todos.Delete(email, item.ID, 1))
+	require.Equal(t, ErrNotFound, todos.Delete(email, uuid.New().String(), 1))
+	require.Equal(t, ErrNotFound, todos.Update(email, newItem("not exists"), 1))
+
+	// concurrent read-modify-write should not lose updates
+	const (
+		writers    = 4
+		increments = 5
+	)
+	var wg sync.WaitGroup
+	errCh := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < increments; {
+				got, err := todos.Get(email, item.ID)
+				if err != nil {
+					errCh <- err
+					return
+				}
+
+				revision := got.Revision
+				got.Revision++
+				switch err := todos.Update(email, got, revision); err {
+				case nil:
+					i++
+				case ErrConflict:
+				default:
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+
+	got, err = todos.Get(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2+writers*increments, got.Revision)
+
+	require.NoError(t, todos.Delete(email, item.ID, got.Revision))
+}
+
+func testTodoBatch(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	newRevision := func(title string) *TodoItem {
+		item := newItem(title)
+		item.Revision = 1
+		return item
+	}
+
+	a, b := newRevision("a"), newRevision("b")
+	require.NoError(t, todos.Create(email, a))
+	require.NoError(t, todos.Create(email, b))
+
+	get := func(id string) *TodoItem {
+		item, err := todos.Get(email, id)
+		if err == ErrNotFound {
+			return nil
+		}
+		require.NoError(t, err)
+		return item
+	}
+
+	// not atomic; failed operations are skipped
+	c := newRevision("c")
+	updatedA := *a
+	updatedA.Title = "updated a"
+	updatedA.Revision = 2
+	staleB := *b
+	staleB.Title = "stale b"
+
+	errs, err := todos.Batch(email, []Operation{
+		{Op: OpCreate, Item: c},
+		{Op: OpUpdate, Item: &updatedA, Revision: 1},
+		{Op: OpUpdate, Item: &staleB, Revision: 5},
+		{Op: OpDelete, ItemID: uuid.New().String()},
+		{Op: "unknown", ItemID: a.ID},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(errs))
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.Equal(t, ErrConflict, errs[2])
+	require.Equal(t, ErrNotFound, errs[3])
+	require.Error(t, errs[4])
+
+	require.Equal(t, c, get(c.ID))
+	require.Equal(t, &updatedA, get(a.ID))
+	require.Equal(t, b, get(b.ID))
+
+	// atomic; nothing applied if any operation fails
+	d := newRevision("d")
+	errs, err = todos.Batch(email, []Operation{
+		{Op: OpCreate, Item: d},
+		{Op: OpDelete, ItemID: a.ID},
+		{Op: OpUpdate, Item: newRevision("not exists")},
+	}, true)
+	require.NoError(t, err)
+	require.Equal(t, []error{ErrAborted, ErrAborted, ErrNotFound}, errs)
+
+	require.Nil(t, get(d.ID))
+	require.Equal(t, &updatedA, get(a.ID))
+
+	// atomic; operations see results of previous operations
+	updatedD := *d
+	updatedD.Title = "updated d"
+	updatedD.Revision = 2
+	errs, err = todos.Batch(email, []Operation{
+		{Op: OpDelete, ItemID: a.ID, Revision: 2},
+		{Op: OpDelete, ItemID: c.ID},
+		{Op: OpCreate, Item: d},
+		{Op: OpUpdate, Item: &updatedD, Revision: 1},
+	}, true)
+	require.NoError(t, err)
+	require.Equal(t, []error{nil, nil, nil, nil}, errs)
+
+	items, _, err := todos.List(email, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []TodoItem{*b, updatedD}, items)
+
+	errs, err = todos.Batch(email, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(errs))
+}
+
+func testTodoIsolation(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	alice, _ := newUser(t, s)
+	bob, _ := newUser(t, s)
+
+	aliceItem := newItem("alice")
+	require.NoError(t, todos.Create(alice, aliceItem))
+	bobItem := newItem("bob")
+	require.NoError(t, todos.Create(bob, bobItem))
+
+	{
+		items, _, err := todos.List(alice, nil)
+		require.NoError(t, err)
+		require.Equal(t, []TodoItem{*aliceItem}, items)
+	}
+
+	{
+		items, _, err := todos.List(bob, nil)
+		require.NoError(t, err)
+		require.Equal(t, []TodoItem{*bobItem}, items)
+	}
+
+	// bob can not access alice's item
+	_, err := todos.Get(bob, aliceItem.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	hijacked := *aliceItem
+	hijacked.Title = "hijacked"
+	require.Equal(t, ErrNotFound, todos.Update(bob, &hijacked, 0))
+	require.Equal(t, ErrNotFound, todos.Delete(bob, aliceItem.ID, 0))
+
+	got, err := todos.Get(alice, aliceItem.ID)
+	require.NoError(t, err)
+	require.Equal(t, aliceItem, got)
+}
+
+func testList(t *testing.T, s Interface) {
+	lists := s.ListService()
+	email, _ := newUser(t, s)
+
+	got, err := lists.List(email)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	work := newList("work")
+	home := newList("Home")
+	require.NoError(t, lists.Create(email, work))
+	require.NoError(t, lists.Create(email, home))
+
+	got, err = lists.List(email)
+	require.NoError(t, err)
+	require.Equal(t, []List{*home, *work}, got)
+
+	work.Name = "office"
+	require.NoError(t, lists.Update(email, work))
+
+	list, err := lists.Get(email, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, work, list)
+
+	// lists of other user is not visible
+	other, _ := newUser(t, s)
+	_, err = lists.Get(other, work.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, lists.Update(other, work))
+	require.Equal(t, ErrNotFound, lists.Delete(other, work.ID, true))
+
+	// items are filtered by list
+	todos := s.TodoService()
+	item := newItem("in work")
+	item.ListID = work.ID
+	require.NoError(t, todos.Create(email, item))
+	require.NoError(t, todos.Create(email, newItem("no list")))
+
+	items, _, err := todos.List(email, &ListOptions{ListID: work.ID})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{*item}, items)
+
+	// move item to other list
+	item.ListID = home.ID
+	require.NoError(t, todos.Update(email, item, 0))
+
+	items, _, err = todos.List(email, &ListOptions{ListID: work.ID})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+
+	items, _, err = todos.List(email, &ListOptions{ListID: home.ID})
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{*item}, items)
+}
+
+func testListDelete(t *testing.T, s Interface) {
+	lists := s.ListService()
+	todos := s.TodoService()
+	email, _ := newUser(t, s)
+
+	list := newList("work")
+	require.NoError(t, lists.Create(email, list))
+	item := newItem("in work")
+	item.ListID = list.ID
+	require.NoError(t, todos.Create(email, item))
+	other := newItem("no list")
+	require.NoError(t, todos.Create(email, other))
+
+	require.Equal(t, ErrNotEmpty, lists.Delete(email, list.ID, false))
+	_, err := lists.Get(email, list.ID)
+	require.NoError(t, err)
+	_, err = todos.Get(email, item.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, lists.Delete(email, list.ID, true))
+	_, err = lists.Get(email, list.ID)
+	require.Equal(t, ErrNotFound, err)
+	_, err = todos.Get(email, item.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	items, _, err := todos.List(email, nil)
+	require.NoError(t, err)
+	require.Equal(t, []TodoItem{*other}, items)
+
+	require.Equal(t, ErrNotFound, lists.Delete(email, list.ID, false))
+
+	// empty list
+	empty := newList("empty")
+	require.NoError(t, lists.Create(email, empty))
+	require.NoError(t, lists.Delete(email, empty.ID, false))
+}
+
+func testListMember(t *testing.T, s Interface) {
+	lists := s.ListService()
+	owner, _ := newUser(t, s)
+	editor, viewer := newEmail(), newEmail()
+
+	list := newList("shared")
+	require.NoError(t, lists.Create(owner, list))
+	other := newList("other")
+	require.NoError(t, lists.Create(owner, other))
+
+	require.Equal(t, ErrNotFound, lists.SetMember(&Member{ListID: list.ID, Owner: editor, Email: viewer, Role: RoleViewer}))
+
+	editorMember := Member{ListID: list.ID, Owner: owner, Email: editor, Role: RoleViewer}
+	require.NoError(t, lists.SetMember(&editorMember))
+	editorMember.Role = RoleEditor
+	require.NoError(t, lists.SetMember(&editorMember)) // change role
+	viewerMember := Member{ListID: list.ID, Owner: owner, Email: viewer, Role: RoleViewer}
+	require.NoError(t, lists.SetMember(&viewerMember))
+	otherMember := Member{ListID: other.ID, Owner: owner, Email: viewer, Role: RoleEditor}
+	require.NoError(t, lists.SetMember(&otherMember))
+
+	want := []Member{editorMember, viewerMember}
+	SortMembers(want)
+	members, err := lists.Members(owner, list.ID)
+	require.NoError(t, err)
+	require.Equal(t, want, members)
+
+	// members are visible to owner only
+	members, err = lists.Members(editor, list.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(members))
+	require.Equal(t, ErrNotFound, lists.DeleteMember(editor, list.ID, viewer))
+
+	members, err = lists.Memberships(editor)
+	require.NoError(t, err)
+	require.Equal(t, []Member{editorMember}, members)
+
+	want = []Member{viewerMember, otherMember}
+	SortMembers(want)
+	members, err = lists.Memberships(viewer)
+	require.NoError(t, err)
+	require.Equal(t, want, members)
+
+	require.NoError(t, lists.DeleteMember(owner, list.ID, viewer))
+	require.Equal(t, ErrNotFound, lists.DeleteMember(owner, list.ID, viewer))
+	members, err = lists.Memberships(viewer)
+	require.NoError(t, err)
+	require.Equal(t, []Member{otherMember}, members)
+
+	// members are deleted with list
+	require.NoError(t, lists.Delete(owner, list.ID, false))
+	members, err = lists.Memberships(editor)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(members))
+
+	// memberships are deleted with member
+	require.NoError(t, s.TokenService().Create(viewer, newToken(t, viewer, time.Hour)))
+	require.NoError(t, s.UserService().Delete(viewer))
+	members, err = lists.Members(owner, other.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(members))
+
+	// memberships are deleted with owner
+	require.NoError(t, lists.SetMember(&Member{ListID: other.ID, Owner: owner, Email: editor, Role: RoleEditor}))
+	require.NoError(t, s.UserService().Delete(owner))
+	members, err = lists.Memberships(editor)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(members))
+}
+
+func testDependency(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	deps := s.DependencyService()
+	email, _ := newUser(t, s)
+
+	items := map[string]*TodoItem{}
+	for _, title := range []string{"a", "b", "c"} {
+		item := newItem(title)
+		require.NoError(t, todos.Create(email, item))
+		items[title] = item
+	}
+
+	ab := &Dependency{ItemID: items["a"].ID, DependsOn: items["b"].ID}
+	bc := &Dependency{ItemID: items["b"].ID, DependsOn: items["c"].ID}
+	require.NoError(t, deps.Add(email, ab))
+	require.NoError(t, deps.Add(email, bc))
+	require.NoError(t, deps.Add(email, ab), "adding existing dependency does nothing")
+
+	require.Equal(t, ErrCycle, deps.Add(email, &Dependency{ItemID: items["c"].ID, DependsOn: items["a"].ID}))
+	require.Equal(t, ErrCycle, deps.Add(email, &Dependency{ItemID: items["a"].ID, DependsOn: items["a"].ID}))
+	require.Equal(t, ErrNotFound, deps.Add(email, &Dependency{ItemID: items["a"].ID, DependsOn: uuid.New().String()}))
+
+	// other user could not see or add dependencies
+	other, _ := newUser(t, s)
+	require.Equal(t, ErrNotFound, deps.Add(other, &Dependency{ItemID: items["c"].ID, DependsOn: items["a"].ID}))
+	got, err := deps.List(other)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	want := []Dependency{*ab, *bc}
+	SortDependencies(want)
+	got, err = deps.List(email)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	require.NoError(t, deps.Delete(email, ab))
+	require.Equal(t, ErrNotFound, deps.Delete(email, ab))
+
+	// dependencies are deleted with items
+	require.NoError(t, todos.Delete(email, items["c"].ID, 0))
+	got, err = deps.List(email)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	// actionable items are resolved by storage helper, see storage.ListOwnerItems
+	require.NoError(t, deps.Add(email, ab))
+	opts := &ListOptions{Sort: SortTitle, Actionable: true, Blocked: Blocked([]Dependency{*ab}, []TodoItem{*items["b"]})}
+	listed, _, err := todos.List(email, opts)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(listed))
+	require.Equal(t, "b", listed[0].Title)
+}
+
+func testFilter(t *testing.T, s Interface) {
+	filters := s.FilterService()
+	email, _ := newUser(t, s)
+
+	got, err := filters.List(email)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	work := newFilter("work", "tag:work")
+	overdue := newFilter("Overdue", "overdue")
+	require.NoError(t, filters.Create(email, work))
+	require.NoError(t, filters.Create(email, overdue))
+
+	got, err = filters.List(email)
+	require.NoError(t, err)
+	require.Equal(t, []Filter{*overdue, *work}, got)
+
+	work.Query = "tag:work and open"
+	require.NoError(t, filters.Update(email, work))
+
+	filter, err := filters.Get(email, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, work, filter)
+
+	// filters of other user is not visible
+	other, _ := newUser(t, s)
+	_, err = filters.Get(other, work.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, filters.Update(other, work))
+	require.Equal(t, ErrNotFound, filters.Delete(other, work.ID))
+
+	require.NoError(t, filters.Delete(email, work.ID))
+	_, err = filters.Get(email, work.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, filters.Delete(email, work.ID))
+}
+
+func testAttachment(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	attachments := s.AttachmentService()
+	email, _ := newUser(t, s)
+
+	item := newItem("item")
+	require.NoError(t, todos.Create(email, item))
+
+	a := newAttachment(item.ID, "a.txt", "aaaa")
+	b := newAttachment(item.ID, "b.txt", "bbbbbb")
+	b.CreatedAt = a.CreatedAt.Add(time.Second)
+	require.NoError(t, attachments.Create(email, a, 10))
+	require.NoError(t, attachments.Create(email, b, 10))
+
+	// quota is checked with total size of user's attachments
+	require.Equal(t, ErrQuotaExceeded, attachments.Create(email, newAttachment(item.ID, "c.txt", "c"), 10))
+	require.Equal(t, ErrNotFound, attachments.Create(email, newAttachment(newItem("").ID, "c.txt", "c"), 0))
+
+	usage, err := attachments.Usage(email)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), usage)
+
+	got, err := attachments.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Attachment{*a, *b}, got)
+
+	attachment, err := attachments.Get(email, item.ID, a.ID)
+	require.NoError(t, err)
+	require.Equal(t, a, attachment)
+
+	referenced, err := attachments.Referenced(a.Hash)
+	require.NoError(t, err)
+	require.True(t, referenced)
+
+	// attachments of other user is not visible
+	other, _ := newUser(t, s)
+	_, err = attachments.Get(other, item.ID, a.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, attachments.Delete(other, item.ID, a.ID))
+
+	require.NoError(t, attachments.Delete(email, item.ID, a.ID))
+	_, err = attachments.Get(email, item.ID, a.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, attachments.Delete(email, item.ID, a.ID))
+
+	referenced, err = attachments.Referenced(a.Hash)
+	require.NoError(t, err)
+	require.False(t, referenced)
+
+	// attachments are deleted with item
+	require.NoError(t, todos.Delete(email, item.ID, 0))
+	got, err = attachments.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	referenced, err = attachments.Referenced(b.Hash)
+	require.NoError(t, err)
+	require.False(t, referenced)
+
+	usage, err = attachments.Usage(email)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), usage)
+}
+
+func testComment(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	comments := s.CommentService()
+	email, _ := newUser(t, s)
+
+	item := newItem("item")
+	require.NoError(t, todos.Create(email, item))
+
+	a := newComment(item.ID, "first")
+	b := newComment(item.ID, "second")
+	b.CreatedAt = a.CreatedAt.Add(time.Second)
+	require.NoError(t, comments.Create(email, b))
+	require.NoError(t, comments.Create(email, a))
+	require.Equal(t, ErrNotFound, comments.Create(email, newComment(newItem("").ID, "none")))
+
+	got, err := comments.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Comment{*a, *b}, got)
+
+	a.Body = "first, edited"
+	a.UpdatedAt = a.UpdatedAt.Add(time.Minute)
+	require.NoError(t, comments.Update(email, a))
+
+	comment, err := comments.Get(email, item.ID, a.ID)
+	require.NoError(t, err)
+	require.Equal(t, a, comment)
+
+	// comments of other user is not visible
+	other, _ := newUser(t, s)
+	_, err = comments.Get(other, item.ID, a.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, comments.Update(other, a))
+	require.Equal(t, ErrNotFound, comments.Delete(other, item.ID, a.ID))
+
+	require.NoError(t, comments.Delete(email, item.ID, a.ID))
+	_, err = comments.Get(email, item.ID, a.ID)
+	require.Equal(t, ErrNotFound, err)
+	require.Equal(t, ErrNotFound, comments.Update(email, a))
+	require.Equal(t, ErrNotFound, comments.Delete(email, item.ID, a.ID))
+
+	// comments are deleted with item
+	require.NoError(t, todos.Delete(email, item.ID, 0))
+	got, err = comments.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+}
+
+func testActivity(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	activities := s.ActivityService()
+	email, _ := newUser(t, s)
+
+	item := newItem("item")
+	require.NoError(t, todos.Create(email, item))
+
+	now := time.Now().UTC().Round(0)
+	created := &Activity{ID: uuid.New().String(), ItemID: item.ID, Actor: email, Action: ActionCreated, CreatedAt: now}
+	updated := &Activity{ID: uuid.New().String(), ItemID: item.ID, Actor: "editor@example.com", Action: ActionUpdated,
+		Fields: []string{"due_date", "title"}, CreatedAt: now.Add(time.Second)}
+	require.NoError(t, activities.Add(email, updated))
+	require.NoError(t, activities.Add(email, created))
+	require.Equal(t, ErrNotFound, activities.Add(email, &Activity{ID: uuid.New().String(), ItemID: newItem("").ID, Action: ActionCreated}))
+
+	got, err := activities.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Activity{*created, *updated}, got)
+
+	// activities of other user is not visible
+	other, _ := newUser(t, s)
+	got, err = activities.List(other, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+
+	// activities are deleted with item
+	require.NoError(t, todos.Delete(email, item.ID, 0))
+	got, err = activities.List(email, item.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(got))
+}
+
+func testUserDeleteCascade(t *testing.T, s Interface) {
+	todos := s.TodoService()
+	alice, aliceToken := newUser(t, s)
+	bob, bobToken := newUser(t, s)
+
+	aliceItem := newItem("alice")
+	require.NoError(t, todos.Create(alice, aliceItem))
+	aliceList := newList("alice")
+	require.NoError(t, s.ListService().Create(alice, aliceList))
+	aliceFilter := newFilter("alice", "tag:alice")
+	require.NoError(t, s.FilterService().Create(alice, aliceFilter))
+	aliceAttachment := newAttachment(aliceItem.ID, "alice.txt", "alice")
+	require.NoError(t, s.AttachmentService().Create(alice, aliceAttachment, 0))
+	require.NoError(t, s.CommentService().Create(alice, newComment(aliceItem.ID, "alice")))
+	bobItem := newItem("bob")
+	require.NoError(t, todos.Create(bob, bobItem))
+
+	require.NoError(t, s.UserService().Delete(alice))
+
+	_, err := s.UserService().Get(alice)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.TokenService().Get(aliceToken)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = todos.Get(alice, aliceItem.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.ListService().Get(alice, aliceList.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = s.FilterService().Get(alice, aliceFilter.ID)
+	require.Equal(t, ErrNotFound, err)
+
+	referenced, err := s.AttachmentService().Referenced(aliceAttachment.Hash)
+	require.NoError(t, err)
+	require.False(t, referenced)
+
+	comments, err := s.CommentService().List(alice, aliceItem.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(comments))
+
+	items, _, err := todos.List(alice, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(items))
+
+	// other user is not affected
+	_, err = s.TokenService().Get(bobToken)
+	require.NoError(t, err)
+
+	got, err := todos.Get(bob, bobItem.ID)
+	require.NoError(t, err)
+	require.Equal(t, bobItem, got)
+}
+
+func testConcurrentWriters(t *testing.T, s Interface) {
+	const (
+		users   = 4
+		writers = 4
+		items   = 10
+	)
+
+	emails := make([]string, users)
+	for i := range emails {
+		emails[i], _ = newUser(t, s)
+	}
+
+	todos := s.TodoService()
+	errCh := make(chan error, users*writers*items)
+
+	var wg sync.WaitGroup
+	for _, email := range emails {
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(email string, w int) {
+				defer wg.Done()
+
+				for i := 0; i < items; i++ {
+					item := newItem(fmt.Sprintf("item %d-%d", w, i))
+					if err := todos.Create(email, item); err != nil {
+						errCh <- err
+						continue
+					}
+
+					item.Title = "updated " + item.Title
+					if err := todos.Update(email, item, 0); err != nil {
+						errCh <- err
+					}
+				}
+			}(email, w)
+		}
+	}
+	wg.Wait()
+	close(errCh)
+
+	for err := range errCh {
+		require.NoError(t, err)
+	}
+
+	for _, email := range emails {
+		got, _, err := todos.List(email, nil)
+		require.NoError(t, err)
+		require.Equal(t, writers*items, len(got))
+	}
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+// Attachment is file attached to todo item, its content is stored in blob store by hash
+type Attachment struct {
+	ID          string    `json:"id" format:"uuid" example:"9a7b6c5d-4e3f-4a2b-8c1d-0e9f8a7b6c5d"`
+	ItemID      string    `json:"item_id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"`
+	Name        string    `json:"name" example:"screenshot.png"` // file name
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"1024"`                                                             // in bytes
+	Hash        string    `json:"hash" example:"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"` // SHA-256 of content
+	CreatedBy   string    `json:"created_by" example:"user@example.com"`                                           // email of uploader
+	CreatedAt   time.Time `json:"created_at" example:"2006-01-02T15:04:05Z"`
+}
+
+// SortAttachments sort attachments by created time and ID
+func SortAttachments(attachments []Attachment) {
+	sort.Slice(attachments, func(i, j int) bool {
+		a, b := &attachments[i], &attachments[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// CheckQuota returns ErrQuotaExceeded if usage with size exceeds quota, quota 0 for unlimited
+func CheckQuota(usage, size, quota int64) error {
+	if quota > 0 && usage+size > quota {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
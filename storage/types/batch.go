@@ -0,0 +1,75 @@
+package types
+
+import "github.com/pkg/errors"
+
+// batch operations
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// ErrAborted is result of operation which is not applied because other operation failed in atomic batch
+var ErrAborted = errors.New("aborted")
+
+// Operation is write operation of batch
+type Operation struct {
+	Op       string
+	Item     *TodoItem // item to create or update
+	ItemID   string    // item ID to delete
+	Revision int       // expected revision of stored item for update and delete, 0 skips the check
+	Children string    // child policy of subtasks of item to delete, subtasks are detached if empty
+}
+
+// ID returns ID of item of the operation
+func (o *Operation) ID() string {
+	if o.Item != nil {
+		return o.Item.ID
+	}
+	return o.ItemID
+}
+
+// Validate validate operation
+func (o *Operation) Validate() error {
+	switch o.Op {
+	case OpCreate, OpUpdate:
+		if o.Item == nil {
+			return errors.Errorf("%s: item required", o.Op)
+		}
+	case OpDelete:
+		if o.ItemID == "" {
+			return errors.Errorf("%s: item ID required", o.Op)
+		}
+		if o.Children != "" && !ValidChildPolicy(o.Children) {
+			return errors.Errorf("%s: invalid child policy: %s", o.Op, o.Children)
+		}
+	default:
+		return errors.Errorf("invalid operation: %s", o.Op)
+	}
+
+	return nil
+}
+
+// AbortOnError returns true if any operation failed, then results of succeeded operations are changed to ErrAborted.
+// it is for atomic batch which should not be applied if any operation fails
+func AbortOnError(errs []error) bool {
+	failed := false
+	for _, err := range errs {
+		if err != nil {
+			failed = true
+			break
+		}
+	}
+
+	if !failed {
+		return false
+	}
+
+	for i, err := range errs {
+		if err == nil {
+			errs[i] = ErrAborted
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+)
+
+// MaxCommentSize is maximum size of comment body in bytes
+const MaxCommentSize = 4 * 1024
+
+// Comment is discussion on todo item
+type Comment struct {
+	ID     string `json:"id" format:"uuid" example:"3c2b1a09-8f7e-4d6c-9b5a-4e3d2c1b0a9f"`
+	ItemID string `json:"item_id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"`
+	Body   string `json:"body" example:"done with the first draft" validate:"required"` // up to MaxCommentSize bytes
+
+	// server managed metadata, client supplied values are ignored
+	Author    string    `json:"author" example:"user@example.com" readonly:"true"` // email of writer
+	CreatedAt time.Time `json:"created_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+	UpdatedAt time.Time `json:"updated_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+}
+
+// Validate validate comment for save
+func (c *Comment) Validate() error {
+	if len(c.Body) > MaxCommentSize {
+		return errors.Errorf("body exceeds %d bytes", MaxCommentSize)
+	}
+
+	return validator.New().Struct(c)
+}
+
+// SortComments sort comments by created time and ID
+func SortComments(comments []Comment) {
+	sort.Slice(comments, func(i, j int) bool {
+		a, b := &comments[i], &comments[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// actions of activity
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionCompleted = "completed"
+	ActionReopened  = "reopened"
+	ActionMoved     = "moved"
+)
+
+// Activity is change of todo item, recorded by server
+type Activity struct {
+	ID        string    `json:"id" format:"uuid" example:"7e6d5c4b-3a29-4180-9f8e-7d6c5b4a3928"`
+	ItemID    string    `json:"item_id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c"`
+	Actor     string    `json:"actor" example:"user@example.com"` // email of user who changed the item
+	Action    string    `json:"action" enums:"created,updated,completed,reopened,moved" example:"updated"`
+	Fields    []string  `json:"fields,omitempty" example:"title,due_date"` // changed fields, in JSON names
+	CreatedAt time.Time `json:"created_at" example:"2006-01-02T15:04:05Z"`
+}
+
+// SortActivities sort activities by created time and ID
+func SortActivities(activities []Activity) {
+	sort.Slice(activities, func(i, j int) bool {
+		a, b := &activities[i], &activities[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// unreportedFields are not reported as changed, they are changed on every update or derived from other fields
+var unreportedFields = map[string]bool{"updated_at": true, "revision": true, "links": true, "notes_html": true}
+
+// ChangedFields returns JSON names of fields which differ between old and item, in name order
+func ChangedFields(old, item *TodoItem) []string {
+	a, b := jsonFields(old), jsonFields(item)
+
+	var fields []string
+	for name, value := range b {
+		if !unreportedFields[name] && string(a[name]) != string(value) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok && !unreportedFields[name] {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+func jsonFields(item *TodoItem) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+
+	return fields
+}
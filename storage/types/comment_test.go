@@ -0,0 +1,54 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFields(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := TodoItem{ID: "1", Title: "title", Tags: []string{"work"}, Notes: "see https://example.com", Revision: 1, CreatedAt: now, UpdatedAt: now}
+
+	tests := [...]struct {
+		name   string
+		change func(item *TodoItem)
+		want   []string
+	}{
+		{"unchanged", func(item *TodoItem) {}, nil},
+		{"metadata", func(item *TodoItem) { item.Revision++; item.UpdatedAt = now.Add(time.Hour) }, nil},
+		{"fields", func(item *TodoItem) { item.Title = "new"; item.Rank = 3 }, []string{"rank", "title"}},
+		{"added", func(item *TodoItem) { item.Priority = PriorityHigh }, []string{"priority"}},
+		{"removed", func(item *TodoItem) { item.Tags = nil }, []string{"tags"}},
+		{"notes", func(item *TodoItem) { item.Notes = ""; item.Links = nil }, []string{"notes"}},
+		{"completed", func(item *TodoItem) { item.Complete(now) }, []string{"completed", "completed_at"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := old
+			tt.change(&item)
+			require.Equal(t, tt.want, ChangedFields(&old, &item))
+		})
+	}
+}
+
+func TestCommentValidate(t *testing.T) {
+	tests := [...]struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"valid", "looks good", false},
+		{"empty", "", true},
+		{"max", strings.Repeat("a", MaxCommentSize), false},
+		{"too long", strings.Repeat("a", MaxCommentSize+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Comment{Body: tt.body}).Validate()
+			require.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Dependency is edge between user's items, the item is blocked by the item it depends on until it is completed
+type Dependency struct {
+	ItemID    string `json:"item_id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c" readonly:"true" validate:"required,uuid"`
+	DependsOn string `json:"depends_on" format:"uuid" example:"1c8f3a5e-0b6d-4c1e-9f62-2b7d8e4a5c31" validate:"required,uuid"` // blocking item
+}
+
+// Validate validate dependency for save
+func (d *Dependency) Validate() error {
+	return validator.New().Struct(d)
+}
+
+// SortDependencies sort dependencies by item ID and blocking item ID
+func SortDependencies(deps []Dependency) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].ItemID != deps[j].ItemID {
+			return deps[i].ItemID < deps[j].ItemID
+		}
+		return deps[i].DependsOn < deps[j].DependsOn
+	})
+}
+
+// CheckDependency returns ErrCycle if dependency makes cycle with existing dependencies,
+// that is the item depends on itself or the blocking item already depends on the item
+func CheckDependency(deps []Dependency, dep *Dependency) error {
+	dependsOn := map[string][]string{}
+	for _, d := range deps {
+		dependsOn[d.ItemID] = append(dependsOn[d.ItemID], d.DependsOn)
+	}
+
+	seen := map[string]bool{}
+	queue := []string{dep.DependsOn}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if id == dep.ItemID {
+			return ErrCycle
+		}
+
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		queue = append(queue, dependsOn[id]...)
+	}
+
+	return nil
+}
+
+// Blocked returns IDs of items which depend on any open item of items
+func Blocked(deps []Dependency, items []TodoItem) map[string]bool {
+	open := map[string]bool{}
+	for _, item := range items {
+		if !item.Completed {
+			open[item.ID] = true
+		}
+	}
+
+	blocked := map[string]bool{}
+	for _, d := range deps {
+		if open[d.DependsOn] {
+			blocked[d.ItemID] = true
+		}
+	}
+
+	return blocked
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDependency(t *testing.T) {
+	// a depends on b, b depends on c
+	deps := []Dependency{{ItemID: "a", DependsOn: "b"}, {ItemID: "b", DependsOn: "c"}}
+
+	tests := [...]struct {
+		name    string
+		dep     Dependency
+		wantErr error
+	}{
+		{"new", Dependency{ItemID: "a", DependsOn: "d"}, nil},
+		{"existing", Dependency{ItemID: "a", DependsOn: "b"}, nil},
+		{"transitive", Dependency{ItemID: "a", DependsOn: "c"}, nil},
+		{"self", Dependency{ItemID: "a", DependsOn: "a"}, ErrCycle},
+		{"direct cycle", Dependency{ItemID: "b", DependsOn: "a"}, ErrCycle},
+		{"indirect cycle", Dependency{ItemID: "c", DependsOn: "a"}, ErrCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, CheckDependency(deps, &tt.dep))
+		})
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	deps := []Dependency{{ItemID: "a", DependsOn: "b"}, {ItemID: "a", DependsOn: "c"}, {ItemID: "d", DependsOn: "c"}}
+
+	tests := [...]struct {
+		name  string
+		items []TodoItem
+		want  map[string]bool
+	}{
+		{"open", []TodoItem{{ID: "b"}, {ID: "c"}}, map[string]bool{"a": true, "d": true}},
+		{"partially completed", []TodoItem{{ID: "b"}, {ID: "c", Completed: true}}, map[string]bool{"a": true}},
+		{"completed", []TodoItem{{ID: "b", Completed: true}, {ID: "c", Completed: true}}, map[string]bool{}},
+		{"deleted", []TodoItem{}, map[string]bool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Blocked(deps, tt.items))
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Filter is user's named query of todo items
+type Filter struct {
+	ID    string `json:"id" format:"uuid" example:"3e5d2c1b-8f4a-4b6e-9d7c-1a2b3c4d5e6f" validate:"required,uuid"`
+	Name  string `json:"name" example:"work this week" validate:"required"`
+	Query string `json:"query" example:"tag:work and due<+7d and open" validate:"required"` // query of package query
+
+	// server managed metadata, client supplied values are ignored
+	CreatedAt time.Time `json:"created_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+	UpdatedAt time.Time `json:"updated_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+}
+
+// Validate validate filter for save
+func (f *Filter) Validate() error {
+	return validator.New().Struct(f)
+}
+
+// SortFilters sort filters by name and ID
+func SortFilters(filters []Filter) {
+	sort.Slice(filters, func(i, j int) bool {
+		a, b := strings.ToLower(filters[i].Name), strings.ToLower(filters[j].Name)
+		if a != b {
+			return a < b
+		}
+		return filters[i].ID < filters[j].ID
+	})
+}
@@ -0,0 +1,390 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sort orders of todo items
+const (
+	SortRank     = "rank"
+	SortDueDate  = "due_date"
+	SortTitle    = "title"
+	SortCreated  = "created"
+	SortUpdated  = "updated"
+	SortPriority = "priority"
+
+	DefaultSort = SortRank
+)
+
+// status filters of todo items
+const (
+	StatusAll       = "all"
+	StatusOpen      = "open"
+	StatusCompleted = "completed"
+)
+
+// due filters of todo items, computed in the user's time zone
+const (
+	DueToday   = "today"
+	DueOverdue = "overdue"
+)
+
+var dues = map[string]bool{DueToday: true, DueOverdue: true}
+
+var statuses = map[string]bool{StatusAll: true, StatusOpen: true, StatusCompleted: true}
+
+var sorts = map[string]bool{SortRank: true, SortDueDate: true, SortTitle: true, SortCreated: true, SortUpdated: true, SortPriority: true}
+
+// Sorts returns available sort orders
+func Sorts() []string {
+	return []string{SortRank, SortDueDate, SortTitle, SortCreated, SortUpdated, SortPriority}
+}
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// ListOptions options for listing todo items
+// items are ordered by sort key and item ID
+type ListOptions struct {
+	Limit     int        // maximum number of items, 0 for unlimited
+	Cursor    string     // opaque cursor of next page, returned by previous List()
+	Sort      string     // sort order: rank, due_date, title, created, updated, priority; default rank
+	DueBefore *Date      // only items due before the date
+	DueAfter  *Date      // only items due after the date
+	Status    string     // status filter: all, open, completed; default all
+	ListID    string     // only items in the list
+	Tags      [][]string // only items which have all tags of any group, see ParseTagFilter
+	ParentID  string     // only children of the item
+
+	Actionable bool            // only items which are not blocked by open dependencies
+	Blocked    map[string]bool // IDs of blocked items, set by storage for actionable filter
+
+	Due      string         // due filter: today, overdue
+	Location *time.Location // time zone of due filter, set by handler from user's time zone; default UTC
+
+	Query     string                    // query of query language, see package query
+	Predicate func(item *TodoItem) bool // compiled query, set by query.Apply
+}
+
+// ParseListOptions parse list options from query parameters
+func ParseListOptions(values url.Values) (*ListOptions, error) {
+	opts := &ListOptions{
+		Cursor:   values.Get("cursor"),
+		Sort:     values.Get("sort"),
+		Status:   values.Get("status"),
+		ListID:   values.Get("list_id"),
+		Tags:     ParseTagFilter(values.Get("tag")),
+		ParentID: values.Get("parent_id"),
+		Due:      values.Get("due"),
+		Query:    values.Get("q"),
+	}
+
+	if s := values.Get("actionable"); s != "" {
+		actionable, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.Errorf("invalid actionable: %s", s)
+		}
+		opts.Actionable = actionable
+	}
+
+	if s := values.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.Errorf("invalid limit: %s", s)
+		}
+		opts.Limit = limit
+	}
+
+	for _, param := range []struct {
+		name string
+		date **Date
+	}{
+		{"due_before", &opts.DueBefore},
+		{"due_after", &opts.DueAfter},
+	} {
+		if s := values.Get(param.name); s != "" {
+			t, err := time.Parse(RFC3339FullDate, s)
+			if err != nil {
+				return nil, errors.Errorf("invalid %s: %s", param.name, s)
+			}
+			*param.date = &Date{t}
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// Values returns list options as query parameters
+func (o *ListOptions) Values() url.Values {
+	values := url.Values{}
+	if o == nil {
+		return values
+	}
+
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		values.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	if o.DueBefore != nil {
+		values.Set("due_before", o.DueBefore.String())
+	}
+	if o.DueAfter != nil {
+		values.Set("due_after", o.DueAfter.String())
+	}
+	if o.Status != "" {
+		values.Set("status", o.Status)
+	}
+	if o.ListID != "" {
+		values.Set("list_id", o.ListID)
+	}
+	if len(o.Tags) > 0 {
+		values.Set("tag", FormatTagFilter(o.Tags))
+	}
+	if o.ParentID != "" {
+		values.Set("parent_id", o.ParentID)
+	}
+	if o.Actionable {
+		values.Set("actionable", "true")
+	}
+	if o.Due != "" {
+		values.Set("due", o.Due)
+	}
+	if o.Query != "" {
+		values.Set("q", o.Query)
+	}
+
+	return values
+}
+
+// Validate validate list options
+func (o *ListOptions) Validate() error {
+	if o.Limit < 0 {
+		return errors.Errorf("invalid limit: %d", o.Limit)
+	}
+
+	if o.Sort != "" && !sorts[o.Sort] {
+		return errors.Errorf("invalid sort: %s, should be one of %s", o.Sort, strings.Join(Sorts(), ", "))
+	}
+
+	if o.Status != "" && !statuses[o.Status] {
+		return errors.Errorf("invalid status: %s, should be one of %s, %s, %s", o.Status, StatusAll, StatusOpen, StatusCompleted)
+	}
+
+	if o.Due != "" && !dues[o.Due] {
+		return errors.Errorf("invalid due: %s, should be one of %s, %s", o.Due, DueToday, DueOverdue)
+	}
+
+	if o.Cursor != "" {
+		if _, err := DecodeCursor(o.Cursor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SortOrder returns sort order, default sort order if not specified
+func (o *ListOptions) SortOrder() string {
+	if o == nil || o.Sort == "" {
+		return DefaultSort
+	}
+	return o.Sort
+}
+
+// Match returns true if item matches to filters
+func (o *ListOptions) Match(item *TodoItem) bool {
+	if o == nil {
+		return true
+	}
+
+	if o.DueBefore != nil && (item.DueDate.IsZero() || !item.DueDate.Before(o.DueBefore.Time)) {
+		return false
+	}
+
+	if o.DueAfter != nil && (item.DueDate.IsZero() || !item.DueDate.After(o.DueAfter.Time)) {
+		return false
+	}
+
+	if o.ListID != "" && item.ListID != o.ListID {
+		return false
+	}
+
+	if o.ParentID != "" && item.ParentID != o.ParentID {
+		return false
+	}
+
+	if o.Actionable && o.Blocked[item.ID] {
+		return false
+	}
+
+	if o.Due != "" && !o.matchDue(item) {
+		return false
+	}
+
+	if o.Predicate != nil && !o.Predicate(item) {
+		return false
+	}
+
+	if len(o.Tags) > 0 {
+		matched := false
+		for _, tags := range o.Tags {
+			if item.HasTags(tags) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	switch o.Status {
+	case StatusOpen:
+		return !item.Completed
+	case StatusCompleted:
+		return item.Completed
+	}
+
+	return true
+}
+
+// matchDue returns true if item matches to due filter at now in the location
+func (o *ListOptions) matchDue(item *TodoItem) bool {
+	loc := o.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	switch o.Due {
+	case DueToday:
+		return !item.DueDate.IsZero() && item.DueDate.Equal(DateOf(now).Time)
+	case DueOverdue:
+		return item.Overdue(now)
+	}
+
+	return true
+}
+
+// SortKey returns key of item for the sort order.
+// keys are compared lexicographically; items are ordered by (sort key, item ID)
+func SortKey(item *TodoItem, order string) string {
+	var key string
+
+	switch order {
+	case SortRank:
+		key = rankKey(item.Rank)
+	case SortDueDate:
+		key = "~" // items without due date comes last
+		if !item.DueDate.IsZero() {
+			key = item.DueDate.Format(RFC3339FullDate)
+			if item.DueTime != "" {
+				key += "T" + item.DueTime // all day items comes first in the day
+			}
+		}
+	case SortTitle:
+		key = strings.ToLower(item.Title)
+	case SortCreated:
+		key = item.CreatedAt.UTC().Format("20060102150405.000000000")
+	case SortUpdated:
+		key = item.UpdatedAt.UTC().Format("20060102150405.000000000")
+	case SortPriority:
+		// highest priority first, then by rank
+		key = priorityKey(item.Priority) + rankKey(item.Rank)
+	}
+
+	return key + "\x00" + item.ID
+}
+
+// rankKey returns sort key of rank
+func rankKey(rank int) string {
+	// flip sign bit to order negative rank first
+	return fmt.Sprintf("%020d", uint64(rank)^(1<<63))
+}
+
+// EncodeCursor returns opaque cursor from sort key
+func EncodeCursor(sortKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(sortKey))
+}
+
+// DecodeCursor returns sort key from cursor
+func DecodeCursor(cursor string) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.Contains(string(key), "\x00") {
+		return "", ErrInvalidCursor
+	}
+
+	return string(key), nil
+}
+
+// Page sort, filter items and returns page of items by list options and next cursor.
+// it is for storage which can not iterate items by sort order
+func Page(items []TodoItem, opts *ListOptions) ([]TodoItem, string, error) {
+	sortOrder := opts.SortOrder()
+
+	var after string
+	if opts != nil && opts.Cursor != "" {
+		key, err := DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = key
+	}
+
+	type keyed struct {
+		key  string
+		item TodoItem
+	}
+
+	matched := []keyed{}
+	for _, item := range items {
+		if !opts.Match(&item) {
+			continue
+		}
+
+		key := SortKey(&item, sortOrder)
+		if after != "" && key <= after {
+			continue
+		}
+
+		matched = append(matched, keyed{key, item})
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].key < matched[j].key })
+
+	limit := len(matched)
+	if opts != nil && opts.Limit > 0 && opts.Limit < limit {
+		limit = opts.Limit
+	}
+
+	page := make([]TodoItem, 0, limit)
+	for _, m := range matched[:limit] {
+		page = append(page, m.item)
+	}
+
+	var next string
+	if limit < len(matched) {
+		next = EncodeCursor(matched[limit-1].key)
+	}
+
+	return page, next, nil
+}
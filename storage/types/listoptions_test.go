@@ -0,0 +1,56 @@
+package types
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseListOptions(t *testing.T) {
+	date := &Date{time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)}
+	cursor := EncodeCursor(SortKey(&TodoItem{ID: "id", Title: "title"}, SortTitle))
+
+	tests := [...]struct {
+		name    string
+		query   string
+		want    *ListOptions
+		wantErr bool
+	}{
+		{"empty", "", &ListOptions{}, false},
+		{"all", "limit=10&sort=title&cursor=" + cursor + "&due_before=2020-12-01&due_after=2020-12-01&status=open",
+			&ListOptions{Limit: 10, Sort: SortTitle, Cursor: cursor, DueBefore: date, DueAfter: date, Status: StatusOpen}, false},
+		{"tag", "tag=Work,urgent|home", &ListOptions{Tags: [][]string{{"urgent", "work"}, {"home"}}}, false},
+		{"empty tag", "tag=,|", &ListOptions{}, false},
+		{"due", "due=overdue", &ListOptions{Due: DueOverdue}, false},
+		{"invalid due", "due=someday", nil, true},
+		{"query", "q=tag%3Awork+due%3Ctoday", &ListOptions{Query: "tag:work due<today"}, false},
+		{"invalid limit", "limit=ten", nil, true},
+		{"negative limit", "limit=-1", nil, true},
+		{"invalid sort", "sort=unknown", nil, true},
+		{"invalid cursor", "cursor=invalid", nil, true},
+		{"invalid status", "status=done", nil, true},
+		{"invalid date", "due_before=2020-13-01", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			got, err := ParseListOptions(values)
+			if (err != nil) != tt.wantErr {
+				require.Failf(t, `ParseListOptions() failed`, `error = %v, wantErr = %v`, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			require.Equal(t, tt.want, got)
+
+			// round trip
+			got, err = ParseListOptions(got.Values())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Role is role of user to list
+type Role string
+
+// roles of list members
+const (
+	RoleOwner  Role = "owner"  // manage list and its members
+	RoleEditor Role = "editor" // create, update and delete items of list
+	RoleViewer Role = "viewer" // read items of list
+)
+
+// CanWrite returns true if role can change items of list
+func (r Role) CanWrite() bool { return r == RoleOwner || r == RoleEditor }
+
+// Member is user whom list is shared with
+type Member struct {
+	ListID string `json:"list_id" format:"uuid" example:"1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e" readonly:"true" validate:"required,uuid"`
+	Owner  string `json:"owner" example:"owner@example.com" readonly:"true" validate:"required,email"` // email of list owner
+	Email  string `json:"email" example:"friend@example.com" validate:"required,email"`
+	Role   Role   `json:"role" enums:"editor,viewer" example:"editor" validate:"required,oneof=editor viewer"`
+}
+
+// Validate validate member for save
+func (m *Member) Validate() error {
+	return validator.New().Struct(m)
+}
+
+// SortMembers sort members by email
+func SortMembers(members []Member) {
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Email != members[j].Email {
+			return members[i].Email < members[j].Email
+		}
+		return members[i].ListID < members[j].ListID
+	})
+}
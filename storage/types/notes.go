@@ -0,0 +1,10 @@
+package types
+
+// MaxNotesSize is maximum size of notes in bytes
+const MaxNotesSize = 16 * 1024
+
+// Link is link in notes of item
+type Link struct {
+	URL  string `json:"url" example:"https://example.com/docs"`
+	Text string `json:"text,omitempty" example:"docs"` // link text, empty for bare URL
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// priorities of todo item; empty is no priority
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+var priorityLevels = map[string]int{"": 0, PriorityLow: 1, PriorityMedium: 2, PriorityHigh: 3, PriorityUrgent: 4}
+
+// PriorityLevel returns level of priority, higher is more important; 0 for no priority
+func PriorityLevel(priority string) int {
+	return priorityLevels[priority]
+}
+
+// priorityKey returns sort key of priority, highest priority first
+func priorityKey(priority string) string {
+	return fmt.Sprintf("%d", len(priorityLevels)-1-PriorityLevel(priority))
+}
+
+// SortByDue sort items by due date, priority from highest, then in order of SortDueDate in the same priority;
+// all day items comes first in the day and items without due date comes last
+func SortByDue(items []TodoItem) {
+	key := func(item *TodoItem) string {
+		date := "~"
+		if !item.DueDate.IsZero() {
+			date = item.DueDate.Format(RFC3339FullDate)
+		}
+
+		return date + "\x00" + priorityKey(item.Priority) + "\x00" + SortKey(item, SortDueDate)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return key(&items[i]) < key(&items[j]) })
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortByDue(t *testing.T) {
+	day := NewDate(2024, 3, 13)
+	items := []TodoItem{
+		{ID: "no due date", Priority: PriorityUrgent},
+		{ID: "next day", DueDate: day.AddDays(1), Priority: PriorityUrgent},
+		{ID: "all day", DueDate: day},
+		{ID: "evening", DueDate: day, DueTime: "18:00"},
+		{ID: "all day 2", DueDate: day, Rank: -1},
+		{ID: "morning", DueDate: day, DueTime: "09:00"},
+		{ID: "high", DueDate: day, Priority: PriorityHigh},
+		{ID: "low", DueDate: day, Priority: PriorityLow, DueTime: "08:00"},
+		{ID: "overdue", DueDate: day.AddDays(-1)},
+	}
+
+	SortByDue(items)
+
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.ID)
+	}
+	require.Equal(t, []string{"overdue", "high", "low", "all day", "all day 2", "morning", "evening", "next day", "no due date"}, got)
+}
+
+func TestSortByDueAgreesSortKey(t *testing.T) {
+	day := NewDate(2024, 3, 13)
+	items := []TodoItem{
+		{ID: "d", DueDate: day, DueTime: "18:00"},
+		{ID: "c", DueDate: day},
+		{ID: "b", DueDate: day, DueTime: "09:00"},
+		{ID: "a", DueDate: day, DueTime: "18:00"},
+		{ID: "e", DueDate: day.AddDays(1)},
+		{ID: "f", DueDate: day.AddDays(-1), DueTime: "23:00"},
+		{ID: "g"},
+	}
+
+	byKey := append([]TodoItem{}, items...)
+	sort.Slice(byKey, func(i, j int) bool { return SortKey(&byKey[i], SortDueDate) < SortKey(&byKey[j], SortDueDate) })
+
+	SortByDue(items)
+	require.Equal(t, byKey, items)
+	require.Equal(t, "c", items[1].ID, "all day items comes first in the day")
+}
+
+func TestPriorityLevel(t *testing.T) {
+	require.Equal(t, 0, PriorityLevel(""))
+	require.True(t, PriorityLevel(PriorityLow) < PriorityLevel(PriorityMedium))
+	require.True(t, PriorityLevel(PriorityMedium) < PriorityLevel(PriorityHigh))
+	require.True(t, PriorityLevel(PriorityHigh) < PriorityLevel(PriorityUrgent))
+}
@@ -0,0 +1,51 @@
+package types
+
+import "math"
+
+// RankGap is gap between ranks of adjacent items after rebalance.
+// items are moved to middle of neighbours, so about log2(RankGap) moves are possible to same place before rebalance
+const RankGap = 1 << 16
+
+// RankBetween returns rank between prev and next item, nil for no item.
+// returns false if there is no room between them, then ranks should be rebalanced
+func RankBetween(prev, next *TodoItem) (int, bool) {
+	switch {
+	case prev == nil && next == nil:
+		return 0, true
+
+	case prev == nil:
+		if next.Rank < math.MinInt64+RankGap {
+			return 0, false
+		}
+		return next.Rank - RankGap, true
+
+	case next == nil:
+		if prev.Rank > math.MaxInt64-RankGap {
+			return 0, false
+		}
+		return prev.Rank + RankGap, true
+	}
+
+	// avoid overflow of next - prev
+	mid := prev.Rank/2 + next.Rank/2 + (prev.Rank%2+next.Rank%2)/2
+	if mid <= prev.Rank || mid >= next.Rank {
+		return 0, false
+	}
+
+	return mid, true
+}
+
+// Rebalance assign ranks with RankGap in order of items.
+// returns indexes of items whose rank is changed
+func Rebalance(items []TodoItem) []int {
+	changed := []int{}
+	for i := range items {
+		rank := (i + 1) * RankGap
+		if items[i].Rank != rank {
+			items[i].Rank = rank
+			changed = append(changed, i)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankBetween(t *testing.T) {
+	item := func(rank int) *TodoItem { return &TodoItem{Rank: rank} }
+
+	type args struct {
+		prev *TodoItem
+		next *TodoItem
+	}
+	tests := [...]struct {
+		name   string
+		args   args
+		want   int
+		wantOK bool
+	}{
+		{"empty", args{nil, nil}, 0, true},
+		{"top", args{nil, item(10)}, 10 - RankGap, true},
+		{"bottom", args{item(10), nil}, 10 + RankGap, true},
+		{"between", args{item(10), item(20)}, 15, true},
+		{"between negative", args{item(-20), item(-10)}, -15, true},
+		{"no room", args{item(10), item(11)}, 0, false},
+		{"same rank", args{item(10), item(10)}, 0, false},
+		{"reversed", args{item(20), item(10)}, 0, false},
+		{"top overflow", args{nil, item(math.MinInt64)}, 0, false},
+		{"bottom overflow", args{item(math.MaxInt64), nil}, 0, false},
+		{"wide", args{item(math.MinInt64), item(math.MaxInt64)}, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RankBetween(tt.args.prev, tt.args.next)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	items := []TodoItem{{Rank: RankGap}, {Rank: 1}, {Rank: 1}, {Rank: 4 * RankGap}}
+	require.Equal(t, []int{1, 2}, Rebalance(items))
+
+	for i, item := range items {
+		require.Equal(t, (i+1)*RankGap, item.Rank)
+	}
+}
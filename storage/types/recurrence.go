@@ -0,0 +1,291 @@
+package types
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// recurrence frequencies
+const (
+	FreqDaily   = "daily"
+	FreqWeekly  = "weekly"
+	FreqMonthly = "monthly"
+)
+
+// LastDay is month day of the last day of month
+const LastDay = -1
+
+// MaxInterval is the largest interval of recurrence
+const MaxInterval = 1000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Recurrence is rule of recurring item; when the item is completed, next occurrence is created
+type Recurrence struct {
+	Freq            string   `json:"freq,omitempty" enums:"daily,weekly,monthly" example:"weekly"`
+	Interval        int      `json:"interval,omitempty" example:"1"`                                  // every interval days, weeks or months; default 1
+	Weekdays        []string `json:"weekdays,omitempty" enums:"MO,TU,WE,TH,FR,SA,SU" example:"MO,WE"` // weekly: days of week, default weekday of due date
+	MonthDay        int      `json:"month_day,omitempty" example:"31"`                                // monthly: day of month or -1 for the last day, default day of due date
+	AfterCompletion bool     `json:"after_completion,omitempty" example:"false"`                      // next due date counts from completion instead of due date
+	Until           *Date    `json:"until,omitempty" swaggertype:"string" example:"2006-12-31"`       // no occurrence after the date
+
+	// RFC 5545 RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY, BYMONTHDAY, UNTIL.
+	// it is parsed into the other fields and not stored
+	RRule string `json:"rrule,omitempty" example:"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"`
+}
+
+// ParseRRule parse RFC 5545 RRULE subset
+func ParseRRule(s string) (*Recurrence, error) {
+	r := &Recurrence{}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid rrule: %s", part)
+		}
+		key, value := strings.ToUpper(kv[0]), kv[1]
+
+		switch key {
+		case "FREQ":
+			r.Freq = strings.ToLower(value)
+
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, errors.Errorf("invalid interval: %s", value)
+			}
+			r.Interval = interval
+
+		case "BYDAY":
+			r.Weekdays = strings.Split(value, ",")
+
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, errors.Errorf("invalid month day: %s", value)
+			}
+			r.MonthDay = day
+
+		case "UNTIL":
+			if len(value) < 8 {
+				return nil, errors.Errorf("invalid until: %s", value)
+			}
+
+			t, err := time.Parse("20060102", value[:8]) // time part is ignored
+			if err != nil {
+				return nil, errors.Errorf("invalid until: %s", value)
+			}
+			r.Until = &Date{t}
+
+		default:
+			return nil, errors.Errorf("unsupported rrule part: %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+
+	return r, nil
+}
+
+// String returns recurrence in RFC 5545 RRULE format
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + strings.ToUpper(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.Weekdays, ","))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Normalize parse RRule, set defaults from due date and validate recurrence
+func (r *Recurrence) Normalize(due Date) error {
+	if r.RRule != "" {
+		parsed, err := ParseRRule(r.RRule)
+		if err != nil {
+			return err
+		}
+		parsed.AfterCompletion = r.AfterCompletion
+		*r = *parsed
+	}
+
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Interval < 0 || r.Interval > MaxInterval {
+		return errors.Errorf("invalid interval: %d, should be between 1 and %d", r.Interval, MaxInterval)
+	}
+
+	if due.IsZero() && !r.AfterCompletion {
+		return errors.New("due date is required for recurrence")
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+	case FreqWeekly:
+		seen := map[string]bool{}
+		days := []string{}
+		for _, day := range r.Weekdays {
+			day = strings.ToUpper(strings.TrimSpace(day))
+			if _, ok := weekdays[day]; !ok {
+				return errors.Errorf("invalid weekday: %s", day)
+			}
+
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+
+		// ordered from monday
+		sort.Slice(days, func(i, j int) bool { return (weekdays[days[i]]+6)%7 < (weekdays[days[j]]+6)%7 })
+		r.Weekdays = days
+	case FreqMonthly:
+		if r.MonthDay == 0 && !due.IsZero() {
+			r.MonthDay = due.Day()
+		}
+		if r.MonthDay < LastDay || r.MonthDay == 0 || r.MonthDay > 31 {
+			return errors.Errorf("invalid month day: %d", r.MonthDay)
+		}
+	default:
+		return errors.Errorf("invalid freq: %s, should be one of %s, %s, %s", r.Freq, FreqDaily, FreqWeekly, FreqMonthly)
+	}
+
+	if r.Freq != FreqWeekly && len(r.Weekdays) > 0 {
+		return errors.New("weekdays are only for weekly recurrence")
+	}
+
+	if r.Freq != FreqMonthly && r.MonthDay != 0 {
+		return errors.New("month day is only for monthly recurrence")
+	}
+
+	r.RRule = ""
+
+	return nil
+}
+
+// Next returns due date of next occurrence, which comes after due date or completion date if AfterCompletion.
+// returns false if there is no more occurrence
+func (r *Recurrence) Next(due, completed Date) (Date, bool) {
+	base := due
+	if r.AfterCompletion || base.IsZero() {
+		base = completed
+	}
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var next Date
+	switch r.Freq {
+	case FreqDaily:
+		next = base.AddDays(interval)
+
+	case FreqWeekly:
+		next = r.nextWeekday(base, interval)
+
+	case FreqMonthly:
+		day := r.MonthDay
+		if day == 0 {
+			day = base.Day()
+		}
+
+		// month of base qualifies if the day is not passed yet
+		for months := 0; ; months += interval {
+			next = NewDate(base.Year(), base.Month()+time.Month(months), 1).MonthDay(day)
+			if next.After(base.Time) {
+				break
+			}
+		}
+
+	default:
+		return Date{}, false
+	}
+
+	if r.Until != nil && next.After(r.Until.Time) {
+		return Date{}, false
+	}
+
+	return next, true
+}
+
+// nextWeekday returns first date after base which is one of weekdays, in every interval weeks from week of base.
+// weeks start on monday
+func (r *Recurrence) nextWeekday(base Date, interval int) Date {
+	if len(r.Weekdays) == 0 {
+		return base.AddDays(7 * interval)
+	}
+
+	days := map[time.Weekday]bool{}
+	for _, day := range r.Weekdays {
+		days[weekdays[day]] = true
+	}
+
+	// rest of the week of base, then the week after interval weeks
+	monday := base.AddDays(-(int(base.Weekday()+6) % 7))
+	for d := base.AddDays(1); d.Before(monday.AddDays(7).Time); d = d.AddDays(1) {
+		if days[d.Weekday()] {
+			return d
+		}
+	}
+
+	next := monday.AddDays(7 * interval)
+	for i := 0; i < 7; i++ {
+		if d := next.AddDays(i); days[d.Weekday()] {
+			return d
+		}
+	}
+
+	return next
+}
+
+// NormalizeRecurrence normalize recurrence of item by its due date, see Recurrence.Normalize
+func (i *TodoItem) NormalizeRecurrence() error {
+	if i.Recurrence == nil {
+		return nil
+	}
+
+	return i.Recurrence.Normalize(i.DueDate)
+}
+
+// NewDate returns date of year, month and day; overflowed month and day are normalized as time.Date
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// AddDays returns date after n days
+func (d Date) AddDays(n int) Date {
+	return Date{d.Time.AddDate(0, 0, n)}
+}
+
+// MonthDay returns the day of month of the date, clamped to the last day of month. LastDay for the last day
+func (d Date) MonthDay(day int) Date {
+	last := NewDate(d.Year(), d.Month()+1, 0).Day()
+	if day == LastDay || day > last {
+		day = last
+	}
+
+	return NewDate(d.Year(), d.Month(), day)
+}
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) Date {
+	t, err := time.Parse(RFC3339FullDate, s)
+	if err != nil {
+		panic(err)
+	}
+	return Date{t}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	until := date("2021-03-31")
+
+	tests := [...]struct {
+		name       string
+		recurrence Recurrence
+		due        string
+		completed  string
+		want       []string // due dates of following occurrences, empty string for no more occurrence
+	}{
+		{"daily", Recurrence{Freq: FreqDaily}, "2020-12-30", "", []string{"2020-12-31", "2021-01-01"}},
+		{"every 3 days", Recurrence{Freq: FreqDaily, Interval: 3}, "2021-02-26", "", []string{"2021-03-01", "2021-03-04"}},
+		{"leap day", Recurrence{Freq: FreqDaily}, "2024-02-28", "", []string{"2024-02-29", "2024-03-01"}},
+		{"weekly", Recurrence{Freq: FreqWeekly}, "2021-01-27", "", []string{"2021-02-03", "2021-02-10"}},
+		{"weekdays", Recurrence{Freq: FreqWeekly, Weekdays: []string{"MO", "WE", "FR"}}, "2021-01-27", "", []string{"2021-01-29", "2021-02-01", "2021-02-03"}},
+		{"every 2 weeks on weekdays", Recurrence{Freq: FreqWeekly, Interval: 2, Weekdays: []string{"MO", "TH"}}, "2021-01-04", "", []string{"2021-01-07", "2021-01-18", "2021-01-21", "2021-02-01"}},
+		{"every 2 weeks from sunday", Recurrence{Freq: FreqWeekly, Interval: 2, Weekdays: []string{"MO", "SU"}}, "2021-01-10", "", []string{"2021-01-18", "2021-01-24", "2021-02-01"}},
+		{"every 1000 weeks on weekdays", Recurrence{Freq: FreqWeekly, Interval: MaxInterval, Weekdays: []string{"MO", "TH"}}, "2021-01-07", "", []string{"2040-03-05", "2040-03-08"}},
+		{"monthly", Recurrence{Freq: FreqMonthly, MonthDay: 15}, "2021-01-15", "", []string{"2021-02-15", "2021-03-15"}},
+		{"monthly before day", Recurrence{Freq: FreqMonthly, MonthDay: 15}, "2021-01-10", "", []string{"2021-01-15", "2021-02-15"}},
+		{"month end", Recurrence{Freq: FreqMonthly, MonthDay: 31}, "2021-01-31", "", []string{"2021-02-28", "2021-03-31", "2021-04-30", "2021-05-31"}},
+		{"month end of leap year", Recurrence{Freq: FreqMonthly, MonthDay: 31}, "2024-01-31", "", []string{"2024-02-29", "2024-03-31"}},
+		{"30th in february", Recurrence{Freq: FreqMonthly, MonthDay: 30}, "2023-01-30", "", []string{"2023-02-28", "2023-03-30"}},
+		{"leap day monthly", Recurrence{Freq: FreqMonthly, MonthDay: 29}, "2023-12-29", "", []string{"2024-01-29", "2024-02-29", "2024-03-29"}},
+		{"last day", Recurrence{Freq: FreqMonthly, MonthDay: LastDay}, "2023-12-31", "", []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}},
+		{"every 12 months from leap day", Recurrence{Freq: FreqMonthly, Interval: 12, MonthDay: 29}, "2024-02-29", "", []string{"2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"}},
+		{"every 3 months", Recurrence{Freq: FreqMonthly, Interval: 3, MonthDay: 31}, "2021-11-30", "", []string{"2022-02-28", "2022-05-31", "2022-08-31"}},
+		{"year end", Recurrence{Freq: FreqMonthly, MonthDay: 31}, "2021-12-31", "", []string{"2022-01-31"}},
+		{"after completion", Recurrence{Freq: FreqDaily, Interval: 10, AfterCompletion: true}, "2021-01-01", "2021-01-05", []string{"2021-01-15"}},
+		{"after completion without due date", Recurrence{Freq: FreqDaily, Interval: 2}, "", "2021-01-05", []string{"2021-01-07"}},
+		{"until", Recurrence{Freq: FreqMonthly, MonthDay: 31, Until: &until}, "2021-01-31", "", []string{"2021-02-28", "2021-03-31", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var due, completed Date
+			if tt.due != "" {
+				due = date(tt.due)
+			}
+			if tt.completed != "" {
+				completed = date(tt.completed)
+			}
+
+			for _, want := range tt.want {
+				next, ok := tt.recurrence.Next(due, completed)
+				if want == "" {
+					require.False(t, ok)
+					break
+				}
+
+				require.True(t, ok)
+				require.Equal(t, want, next.String())
+				due, completed = next, next
+			}
+		})
+	}
+}
+
+func TestRecurrenceNextLargeInterval(t *testing.T) {
+	// next week of the interval is computed without iterating days of the interval
+	r := Recurrence{Freq: FreqWeekly, Interval: 100000000, Weekdays: []string{"MO"}}
+
+	next, ok := r.Next(date("2021-01-04"), Date{})
+	require.True(t, ok)
+	require.Equal(t, date("2021-01-04").AddDays(7*r.Interval), next)
+}
+
+func TestRecurrenceNormalize(t *testing.T) {
+	tests := [...]struct {
+		name       string
+		recurrence Recurrence
+		due        string
+		want       Recurrence
+		wantErr    bool
+	}{
+		{"default interval", Recurrence{Freq: FreqDaily}, "2021-01-01", Recurrence{Freq: FreqDaily, Interval: 1}, false},
+		{"weekdays", Recurrence{Freq: FreqWeekly, Weekdays: []string{"su", "FR", "MO", "FR"}}, "2021-01-01",
+			Recurrence{Freq: FreqWeekly, Interval: 1, Weekdays: []string{"MO", "FR", "SU"}}, false},
+		{"month day from due date", Recurrence{Freq: FreqMonthly}, "2021-01-31", Recurrence{Freq: FreqMonthly, Interval: 1, MonthDay: 31}, false},
+		{"rrule", Recurrence{RRule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,MO", AfterCompletion: true}, "2021-01-01",
+			Recurrence{Freq: FreqWeekly, Interval: 2, Weekdays: []string{"MO", "TU"}, AfterCompletion: true}, false},
+		{"rrule until", Recurrence{RRule: "FREQ=MONTHLY;BYMONTHDAY=-1;UNTIL=20211231T000000Z"}, "2021-01-01",
+			Recurrence{Freq: FreqMonthly, Interval: 1, MonthDay: LastDay, Until: &Date{date("2021-12-31").Time}}, false},
+		{"invalid freq", Recurrence{Freq: "yearly"}, "2021-01-01", Recurrence{}, true},
+		{"invalid weekday", Recurrence{Freq: FreqWeekly, Weekdays: []string{"XX"}}, "2021-01-01", Recurrence{}, true},
+		{"invalid month day", Recurrence{Freq: FreqMonthly, MonthDay: 32}, "2021-01-01", Recurrence{}, true},
+		{"too large interval", Recurrence{Freq: FreqWeekly, Interval: 100000000, Weekdays: []string{"MO"}}, "2021-01-01", Recurrence{}, true},
+		{"too large rrule interval", Recurrence{RRule: "FREQ=WEEKLY;INTERVAL=100000000;BYDAY=MO"}, "2021-01-01", Recurrence{}, true},
+		{"weekdays of monthly", Recurrence{Freq: FreqMonthly, Weekdays: []string{"MO"}}, "2021-01-01", Recurrence{}, true},
+		{"no due date", Recurrence{Freq: FreqDaily}, "", Recurrence{}, true},
+		{"unsupported rrule", Recurrence{RRule: "FREQ=DAILY;COUNT=3"}, "2021-01-01", Recurrence{}, true},
+		{"rrule without freq", Recurrence{RRule: "INTERVAL=2"}, "2021-01-01", Recurrence{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var due Date
+			if tt.due != "" {
+				due = date(tt.due)
+			}
+
+			err := tt.recurrence.Normalize(due)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tt.recurrence)
+
+			// String() is parsed back to same recurrence
+			parsed := Recurrence{RRule: tt.want.String(), AfterCompletion: tt.want.AfterCompletion}
+			require.NoError(t, parsed.Normalize(due))
+			require.Equal(t, tt.want, parsed)
+		})
+	}
+}
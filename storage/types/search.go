@@ -0,0 +1,125 @@
+package types
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchResult is todo item matched to search query
+type SearchResult struct {
+	Item  TodoItem `json:"item"`
+	Score float64  `json:"score" example:"2.5"` // relevance to query, higher is better
+}
+
+// searchField is text of item indexed for search with its weight
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// searchFields returns texts of item indexed for search; title weighs more than others
+func searchFields(item *TodoItem) []searchField {
+	return []searchField{{item.Title, 2}, {item.Notes, 1}}
+}
+
+// Tokenize split text into lower case words of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchTerms returns unique words of search query in order
+func SearchTerms(query string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, term := range Tokenize(query) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// SearchTokens returns sorted unique words of item to be indexed
+func SearchTokens(item *TodoItem) []string {
+	seen := map[string]bool{}
+	tokens := []string{}
+	for _, field := range searchFields(item) {
+		for _, token := range Tokenize(field.text) {
+			if !seen[token] {
+				seen[token] = true
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	sort.Strings(tokens)
+	return tokens
+}
+
+// SearchScore returns relevance of item to search terms, 0 if any term does not match.
+// term matches to words which begin with it; the more of word the term covers, the higher score,
+// so whole word scores more than prefix of it
+func SearchScore(item *TodoItem, terms []string) float64 {
+	fields := searchFields(item)
+	score := 0.0
+
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			for _, token := range Tokenize(field.text) {
+				if !strings.HasPrefix(token, term) {
+					continue
+				}
+
+				if s := field.weight * float64(len(term)) / float64(len(token)); s > best {
+					best = s
+				}
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+
+	return score
+}
+
+// Search score candidate items by search terms and returns matched items ordered by relevance.
+// limit 0 for unlimited
+func Search(items []TodoItem, terms []string, limit int) []SearchResult {
+	results := []SearchResult{}
+	for _, item := range items {
+		if score := SearchScore(&item, terms); score > 0 {
+			results = append(results, SearchResult{Item: item, Score: score})
+		}
+	}
+
+	SortSearchResults(results)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// SortSearchResults sort results by score from highest; open items comes first in same score, then by rank and ID
+func SortSearchResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := &results[i], &results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Item.Completed != b.Item.Completed {
+			return !a.Item.Completed
+		}
+		return SortKey(&a.Item, SortRank) < SortKey(&b.Item, SortRank)
+	})
+}
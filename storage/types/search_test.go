@@ -0,0 +1,71 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := [...]struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Buy milk", []string{"buy", "milk"}},
+		{"  write Q3-report, then e-mail!", []string{"write", "q3", "report", "then", "e", "mail"}},
+		{"Café über 2개", []string{"café", "über", "2개"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if got == nil {
+				got = []string{}
+			}
+			require.Equal(t, tt.want, got)
+			require.Equal(t, got, SearchTerms(tt.text))
+		})
+	}
+
+	require.Equal(t, []string{"buy", "milk"}, SearchTerms("buy milk, BUY"))
+	require.Equal(t, []string{"and", "milk"}, SearchTokens(&TodoItem{Title: "milk and milk"}))
+}
+
+func TestSearch(t *testing.T) {
+	items := []TodoItem{
+		{ID: "1", Title: "Write report", Rank: 3},
+		{ID: "2", Title: "Report expenses", Rank: 2},
+		{ID: "3", Title: "Reply to reporters", Rank: 1},
+		{ID: "4", Title: "Buy milk", Rank: 4},
+		{ID: "5", Title: "Write reports", Rank: 5, Completed: true},
+		{ID: "6", Title: "Call bank", Notes: "about expense report", Rank: 6},
+	}
+
+	tests := [...]struct {
+		query string
+		want  []string
+	}{
+		{"milk", []string{"4"}},
+		{"MILK", []string{"4"}},
+		{"report", []string{"2", "1", "5", "3", "6"}},
+		{"rep", []string{"3", "2", "1", "5", "6"}},
+		{"bank expense", []string{"6"}},
+		{"write report", []string{"1", "5"}},
+		{"wri rep", []string{"1", "5"}},
+		{"reports", []string{"5"}},
+		{"report milk", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := []string{}
+			for _, result := range Search(items, SearchTerms(tt.query), 0) {
+				require.True(t, result.Score > 0)
+				got = append(got, result.Item.ID)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	require.Equal(t, 2, len(Search(items, SearchTerms("report"), 2)))
+}
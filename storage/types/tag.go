@@ -0,0 +1,140 @@
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// separators of tag filter
+const (
+	TagAnd = ","
+	TagOr  = "|"
+)
+
+// Tag is tag with number of items which has the tag
+type Tag struct {
+	Name  string `json:"name" example:"work" validate:"required,max=64,excludesall=0x2C0x7C"`
+	Count int    `json:"count" example:"3" readonly:"true"`
+}
+
+// Validate validate tag
+func (t *Tag) Validate() error {
+	return validator.New().Struct(t)
+}
+
+// NormalizeTag returns tag in canonical form; tags are case insensitive
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags returns sorted, unique tags in canonical form; empty tags are removed
+func NormalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTags returns true if item has all of tags
+func (i *TodoItem) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, t := range i.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RenameTag rename tag of item, tag is merged if item already has the new tag.
+// returns false if item does not have the tag
+func (i *TodoItem) RenameTag(from, to string) bool {
+	if !i.HasTags([]string{from}) {
+		return false
+	}
+
+	tags := make([]string, 0, len(i.Tags))
+	for _, tag := range i.Tags {
+		if tag == from {
+			tag = to
+		}
+		tags = append(tags, tag)
+	}
+	i.Tags = NormalizeTags(tags)
+
+	return true
+}
+
+// ParseTagFilter parse tag filter; tags joined by comma are AND, groups of tags joined by | are OR.
+// e.g. "work,urgent|home" matches items which have both work and urgent, or home
+func ParseTagFilter(s string) [][]string {
+	filter := [][]string{}
+	for _, group := range strings.Split(s, TagOr) {
+		if tags := NormalizeTags(strings.Split(group, TagAnd)); len(tags) > 0 {
+			filter = append(filter, tags)
+		}
+	}
+
+	if len(filter) == 0 {
+		return nil
+	}
+
+	return filter
+}
+
+// FormatTagFilter returns tag filter as string, reverse of ParseTagFilter
+func FormatTagFilter(filter [][]string) string {
+	groups := make([]string, 0, len(filter))
+	for _, tags := range filter {
+		groups = append(groups, strings.Join(tags, TagAnd))
+	}
+
+	return strings.Join(groups, TagOr)
+}
+
+// CountTags returns tags of items with their counts, ordered by name
+func CountTags(items []TodoItem) []Tag {
+	counts := map[string]int{}
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			counts[tag]++
+		}
+	}
+
+	return NewTags(counts)
+}
+
+// NewTags returns tags of tag name to count, ordered by name
+func NewTags(counts map[string]int) []Tag {
+	tags := make([]Tag, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, Tag{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+
+	return tags
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := [...]struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"empty", []string{"", " "}, nil},
+		{"sorted", []string{"b", "a"}, []string{"a", "b"}},
+		{"case insensitive", []string{"Work", " work "}, []string{"work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NormalizeTags(tt.tags))
+		})
+	}
+}
+
+func TestParseTagFilter(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		filter string
+		want   [][]string
+	}{
+		{"empty", "", nil},
+		{"single", "work", [][]string{{"work"}}},
+		{"and", "work,urgent", [][]string{{"urgent", "work"}}},
+		{"or", "work|home", [][]string{{"work"}, {"home"}}},
+		{"and or", "work,urgent|home", [][]string{{"urgent", "work"}, {"home"}}},
+		{"empty groups", "|work||", [][]string{{"work"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTagFilter(tt.filter)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, got, ParseTagFilter(FormatTagFilter(got)))
+		})
+	}
+}
+
+func TestRenameTag(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		tags     []string
+		from, to string
+		want     []string
+		wantOK   bool
+	}{
+		{"rename", []string{"a", "b"}, "a", "c", []string{"b", "c"}, true},
+		{"merge", []string{"a", "b"}, "a", "b", []string{"b"}, true},
+		{"not found", []string{"a"}, "b", "c", []string{"a"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &TodoItem{Tags: tt.tags}
+			require.Equal(t, tt.wantOK, item.RenameTag(tt.from, tt.to))
+			require.Equal(t, tt.want, item.Tags)
+		})
+	}
+}
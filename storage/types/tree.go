@@ -0,0 +1,198 @@
+package types
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MaxDepth is maximum depth of subtasks, item without parent is depth 1
+const MaxDepth = 5
+
+// child policies, applied to children when parent is completed or deleted
+const (
+	ChildCascade = "cascade" // children are completed or deleted together
+	ChildBlock   = "block"   // parent can not be completed while it has open children, or deleted while it has children
+	ChildOrphan  = "orphan"  // children are detached from parent
+)
+
+var childPolicies = map[string]bool{ChildCascade: true, ChildBlock: true, ChildOrphan: true}
+
+// ValidChildPolicy returns true if policy is one of child policies
+func ValidChildPolicy(policy string) bool { return childPolicies[policy] }
+
+var (
+	ErrCycle   = errors.New("cycle detected")
+	ErrTooDeep = errors.New("too deep")
+)
+
+// TreeItem is todo item with its children
+type TreeItem struct {
+	TodoItem
+	Children []TreeItem `json:"children,omitempty"`
+}
+
+// children returns parent ID to children, in order of items
+func children(items []TodoItem) map[string][]TodoItem {
+	children := map[string][]TodoItem{}
+	for _, item := range items {
+		if item.ParentID != "" {
+			children[item.ParentID] = append(children[item.ParentID], item)
+		}
+	}
+	return children
+}
+
+// ItemTree looks up stored items of a user by ID and by parent
+type ItemTree interface {
+	Get(itemID string) (*TodoItem, error)
+	Children(itemID string) ([]TodoItem, error) // direct children of item
+}
+
+// NewItemTree returns tree of user's items stored in todos, children are looked up by parent index
+func NewItemTree(todos TodoService, email string) ItemTree {
+	return &itemTree{todos: todos, email: email}
+}
+
+type itemTree struct {
+	todos TodoService
+	email string
+}
+
+func (t *itemTree) Get(itemID string) (*TodoItem, error) { return t.todos.Get(t.email, itemID) }
+
+func (t *itemTree) Children(itemID string) ([]TodoItem, error) {
+	items, _, err := t.todos.List(t.email, &ListOptions{ParentID: itemID})
+	return items, err
+}
+
+// CheckParent check item could be child of parent, only ancestors of parent and descendants of item are looked up.
+// returns ErrNotFound if parent not exists, ErrCycle if parent is the item or its descendant,
+// ErrTooDeep if subtree of item exceeds MaxDepth under the parent
+func CheckParent(tree ItemTree, itemID, parentID string) error {
+	depth := 0
+	for id := parentID; id != ""; {
+		if id == itemID {
+			return ErrCycle
+		}
+
+		parent, err := tree.Get(id)
+		if err != nil {
+			if err == ErrNotFound && id != parentID {
+				break // stored parent is already deleted
+			}
+			return err
+		}
+
+		depth++
+		if depth >= MaxDepth {
+			return ErrTooDeep // no room for the item, stored items could have cycle
+		}
+		id = parent.ParentID
+	}
+
+	h, err := height(tree, itemID, depth)
+	if err != nil {
+		return err
+	}
+
+	if depth+h > MaxDepth {
+		return ErrTooDeep
+	}
+
+	return nil
+}
+
+// height returns height of subtree of the item, leaf is 1
+func height(tree ItemTree, itemID string, depth int) (int, error) {
+	if depth > MaxDepth {
+		return depth, nil // enough to know it is too deep
+	}
+
+	children, err := tree.Children(itemID)
+	if err != nil {
+		return 0, err
+	}
+
+	max := 0
+	for _, child := range children {
+		h, err := height(tree, child.ID, depth+1)
+		if err != nil {
+			return 0, err
+		}
+
+		if h > max {
+			max = h
+		}
+	}
+
+	return max + 1, nil
+}
+
+// Descendants returns all descendants of item, parents come before their children
+func Descendants(tree ItemTree, itemID string) ([]TodoItem, error) {
+	descendants := []TodoItem{}
+	seen := map[string]bool{itemID: true}
+	queue := []string{itemID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		children, err := tree.Children(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+
+			descendants = append(descendants, child)
+			queue = append(queue, child.ID)
+		}
+	}
+
+	return descendants, nil
+}
+
+// Detach detach item from its deleted parent
+func (item *TodoItem) Detach() {
+	item.ParentID = ""
+	item.UpdatedAt = time.Now().UTC().Round(0)
+	item.Revision++
+}
+
+// BuildTree returns tree of items keeping order of items.
+// items whose parent is not in items are roots
+func BuildTree(items []TodoItem) []TreeItem {
+	ids := map[string]bool{}
+	for _, item := range items {
+		ids[item.ID] = true
+	}
+
+	children := children(items)
+
+	var build func(item TodoItem, depth int) TreeItem
+	build = func(item TodoItem, depth int) TreeItem {
+		node := TreeItem{TodoItem: item}
+		if depth >= len(items) {
+			return node // stored items have cycle
+		}
+
+		for _, child := range children[item.ID] {
+			node.Children = append(node.Children, build(child, depth+1))
+		}
+		return node
+	}
+
+	roots := []TreeItem{}
+	for _, item := range items {
+		if item.ParentID == "" || !ids[item.ParentID] {
+			roots = append(roots, build(item, 0))
+		}
+	}
+
+	return roots
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// treeItems returns items of a > b > c > d > e, f, g > h
+func treeItems() []TodoItem {
+	return []TodoItem{
+		{ID: "a"}, {ID: "b", ParentID: "a"}, {ID: "c", ParentID: "b"}, {ID: "d", ParentID: "c"}, {ID: "e", ParentID: "d"},
+		{ID: "f"},
+		{ID: "g"}, {ID: "h", ParentID: "g"},
+	}
+}
+
+// sliceTree is ItemTree of items
+type sliceTree []TodoItem
+
+func (t sliceTree) Get(itemID string) (*TodoItem, error) {
+	for i := range t {
+		if t[i].ID == itemID {
+			return &t[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (t sliceTree) Children(itemID string) ([]TodoItem, error) { return children(t)[itemID], nil }
+
+func TestCheckParent(t *testing.T) {
+	tests := [...]struct {
+		name     string
+		itemID   string
+		parentID string
+		wantErr  error
+	}{
+		{"no parent", "b", "", nil},
+		{"parent", "f", "a", nil},
+		{"not found", "f", "x", ErrNotFound},
+		{"self", "f", "f", ErrCycle},
+		{"descendant", "a", "c", ErrCycle},
+		{"max depth", "f", "d", nil},
+		{"too deep", "f", "e", ErrTooDeep},
+		{"subtree", "g", "c", nil},
+		{"subtree too deep", "g", "d", ErrTooDeep},
+		{"new item", "new", "e", ErrTooDeep},
+		{"stored cycle", "f", "x1", ErrTooDeep},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, CheckParent(append(sliceTree(treeItems()), TodoItem{ID: "x1", ParentID: "x2"}, TodoItem{ID: "x2", ParentID: "x1"}), tt.itemID, tt.parentID))
+		})
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	tests := [...]struct {
+		name   string
+		itemID string
+		want   []string
+	}{
+		{"chain", "b", []string{"c", "d", "e"}},
+		{"leaf", "e", []string{}},
+		{"unknown", "x", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			descendants, err := Descendants(sliceTree(treeItems()), tt.itemID)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, item := range descendants {
+				ids = append(ids, item.ID)
+			}
+			require.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	items := treeItems()
+
+	tree := BuildTree(items)
+	require.Equal(t, 3, len(tree))
+	require.Equal(t, "a", tree[0].ID)
+	require.Equal(t, "e", tree[0].Children[0].Children[0].Children[0].Children[0].ID)
+	require.Equal(t, "h", tree[2].Children[0].ID)
+
+	// item whose parent is not given is root
+	tree = BuildTree(items[2:5])
+	require.Equal(t, 1, len(tree))
+	require.Equal(t, "c", tree[0].ID)
+}
@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -11,6 +13,8 @@ import (
 var (
 	ErrNotFound         = errors.New("fot found")
 	ErrNotAuthenticated = errors.New("not authenticated")
+	ErrConflict         = errors.New("conflict")
+	ErrNotEmpty         = errors.New("not empty")
 )
 
 const (
@@ -22,6 +26,13 @@ type Interface interface {
 	UserService() UserService
 	TokenService() TokenService
 	TodoService() TodoService
+	ListService() ListService
+	DependencyService() DependencyService
+	FilterService() FilterService
+	SearchService() SearchService
+	AttachmentService() AttachmentService
+	CommentService() CommentService
+	ActivityService() ActivityService
 
 	Close()
 }
@@ -29,6 +40,10 @@ type Interface interface {
 type UserService interface {
 	Get(email string) (*User, error)
 	Create(user *User) error
+
+	// return ErrNotFound if user not found
+	Update(user *User) error
+
 	Delete(email string) error
 }
 
@@ -44,33 +59,272 @@ type TokenService interface {
 type TodoService interface {
 	Create(email string, item *TodoItem) error
 
-	List(email string) ([]TodoItem, error) // list todo items
+	// list todo items by options, returns items and cursor of next page.
+	// cursor is empty if no more items
+	List(email string, opts *ListOptions) ([]TodoItem, string, error)
+
+	// list tags of user's items with number of items, ordered by name
+	Tags(email string) ([]Tag, error)
 
 	// return ErrNotFound if item not found
 	Get(email string, itemID string) (*TodoItem, error)
 
+	// update item if revision of stored item is same to given revision, revision 0 skips the check.
+	// return ErrNotFound if item not found, ErrConflict if revision mismatch
+	Update(email string, item *TodoItem, revision int) error
+
+	// delete item if revision of stored item is same to given revision, revision 0 skips the check.
+	// subtasks of item are detached, use Batch with child policy of operation to cascade or block.
+	// return ErrNotFound if item not found, ErrConflict if revision mismatch, ErrNotEmpty if item has subtasks on block
+	Delete(email string, itemID string, revision int) error
+
+	// apply operations, returns result of each operation.
+	// if atomic, all operations are applied or nothing applied; operations not applied are ErrAborted.
+	// error is returned if batch itself fails
+	Batch(email string, ops []Operation, atomic bool) ([]error, error)
+}
+
+// MatchRevision returns true if item revision is same to revision, revision 0 matches any
+func (i *TodoItem) MatchRevision(revision int) bool {
+	return revision == 0 || i.Revision == revision
+}
+
+// ListService represents todo list storage
+type ListService interface {
+	Create(email string, list *List) error
+
+	// list user's lists, ordered by name
+	List(email string) ([]List, error)
+
+	// return ErrNotFound if list not found
+	Get(email string, listID string) (*List, error)
+
+	// return ErrNotFound if list not found
+	Update(email string, list *List) error
+
+	// delete list with its members. if cascade, items of the list are deleted together,
+	// or returns ErrNotEmpty if list has items.
+	// return ErrNotFound if list not found
+	Delete(email string, listID string, cascade bool) error
+
+	// add member to list of member.Owner or change role of the member.
+	// return ErrNotFound if list not found
+	SetMember(member *Member) error
+
+	// list members of user's list, ordered by email
+	Members(email string, listID string) ([]Member, error)
+
+	// return ErrNotFound if list or member not found
+	DeleteMember(email string, listID string, memberEmail string) error
+
+	// list memberships of user, that is lists shared with the user
+	Memberships(email string) ([]Member, error)
+}
+
+// DependencyService represents dependencies between user's items
+type DependencyService interface {
+	// add dependency, adding existing dependency does nothing.
+	// return ErrNotFound if any item not found, ErrCycle if dependency makes cycle
+	Add(email string, dep *Dependency) error
+
+	// list dependencies of user's items, ordered by item ID and blocking item ID.
+	// dependencies of deleted items are not included
+	List(email string) ([]Dependency, error)
+
+	// return ErrNotFound if dependency not found
+	Delete(email string, dep *Dependency) error
+}
+
+// FilterService represents user's saved filters
+type FilterService interface {
+	Create(email string, filter *Filter) error
+
+	// list user's filters, ordered by name
+	List(email string) ([]Filter, error)
+
+	// return ErrNotFound if filter not found
+	Get(email string, filterID string) (*Filter, error)
+
+	// return ErrNotFound if filter not found
+	Update(email string, filter *Filter) error
+
+	// return ErrNotFound if filter not found
+	Delete(email string, filterID string) error
+}
+
+// SearchService represents full text search index of todo items, which is updated with item writes
+type SearchService interface {
+	// search user's items by words of query, every word matches to a word of item which begins with it.
+	// results are ordered by relevance, limit 0 for unlimited
+	Search(email string, query string, limit int) ([]SearchResult, error)
+
+	// rebuild search index of all items, for items written before the index exists
+	Rebuild() error
+}
+
+// AttachmentService represents files attached to user's items; contents are stored in blob store by hash.
+// attachments are deleted with their item, so contents not referenced by any attachment can be removed
+type AttachmentService interface {
+	// create attachment if total size of user's attachments with it does not exceed quota, quota 0 for unlimited.
+	// return ErrNotFound if item not found, ErrQuotaExceeded if quota is exceeded
+	Create(email string, attachment *Attachment, quota int64) error
+
+	// list attachments of user's item, ordered by created time
+	List(email string, itemID string) ([]Attachment, error)
+
+	// return ErrNotFound if attachment not found
+	Get(email string, itemID string, attachmentID string) (*Attachment, error)
+
+	// return ErrNotFound if attachment not found
+	Delete(email string, itemID string, attachmentID string) error
+
+	// returns total size of user's attachments
+	Usage(email string) (int64, error)
+
+	// returns true if attachment of any user has the content hash
+	Referenced(hash string) (bool, error)
+}
+
+// CommentService represents comments on user's items, comments are deleted with their item
+type CommentService interface {
+	// return ErrNotFound if item not found
+	Create(email string, comment *Comment) error
+
+	// list comments of user's item, ordered by created time
+	List(email string, itemID string) ([]Comment, error)
+
+	// return ErrNotFound if comment not found
+	Get(email string, itemID string, commentID string) (*Comment, error)
+
+	// return ErrNotFound if comment not found
+	Update(email string, comment *Comment) error
+
+	// return ErrNotFound if comment not found
+	Delete(email string, itemID string, commentID string) error
+}
+
+// ActivityService represents activity feed of user's items, activities are deleted with their item
+type ActivityService interface {
 	// return ErrNotFound if item not found
-	Update(email string, item *TodoItem) error
+	Add(email string, activity *Activity) error
 
-	Delete(email string, itemID string) error
+	// list activities of user's item, ordered by created time
+	List(email string, itemID string) ([]Activity, error)
 }
 
 // User user informations
 type User struct {
-	Email string `json:"email" validate:"required,email"`
+	Email    string `json:"email" validate:"required,email"`
+	TimeZone string `json:"time_zone,omitempty" example:"Asia/Seoul" validate:"omitempty,timezone"` // IANA time zone, default UTC
+}
+
+// Validate validate user for save
+func (u *User) Validate() error {
+	return validator.New().Struct(u)
+}
+
+// Location returns location of user's time zone, UTC if time zone is not set or invalid
+func (u *User) Location() *time.Location {
+	if u == nil || u.TimeZone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
 }
 
 // TodoItem todo item
 type TodoItem struct {
-	ID      string `json:"id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c" validate:"required,uuid"`
-	Title   string `json:"title" example:"do something in future" validate:"required"`
-	DueDate Date   `json:"due_date" swaggertype:"string" example:"2006-01-02"`
-	Rank    int    `json:"rank" format:"int" example:"1"` // rank order
+	ID      string   `json:"id" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c" validate:"required,uuid"`
+	Title   string   `json:"title" example:"do something in future" validate:"required"`
+	DueDate Date     `json:"due_date" swaggertype:"string" example:"2006-01-02"`
+	DueTime string   `json:"due_time,omitempty" example:"15:04" validate:"omitempty,len=5,datetime=15:04"`     // time of due date in user's time zone, empty for all day
+	Rank    int      `json:"rank" format:"int" example:"1"`                                                    // rank order
+	ListID  string   `json:"list_id,omitempty" format:"uuid" example:"1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e"`   // list of item, empty for no list
+	Tags    []string `json:"tags,omitempty" example:"work,urgent" validate:"dive,max=64,excludesall=0x2C0x7C"` // case insensitive labels
+
+	Priority string `json:"priority,omitempty" enums:"low,medium,high,urgent" example:"high" validate:"omitempty,oneof=low medium high urgent"`
+
+	ParentID string `json:"parent_id,omitempty" format:"uuid" example:"628b92ab-6d95-4fbe-b7c6-09cf5cd8941c" validate:"omitempty,uuid"` // parent item of subtask
+
+	// repeat rule, next occurrence is created when the item is completed
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+
+	Notes string `json:"notes,omitempty" example:"see [docs](https://example.com/docs)"` // description in Markdown, up to MaxNotesSize bytes
+
+	// derived from notes, client supplied values are ignored
+	Links     []Link `json:"links,omitempty" readonly:"true"`                                         // links in notes
+	NotesHTML string `json:"notes_html,omitempty" example:"<p>see <em>docs</em></p>" readonly:"true"` // sanitized HTML of notes, only for render=html
+
+	// server managed metadata, client supplied values are ignored
+	CreatedAt   time.Time  `json:"created_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+	Revision    int        `json:"revision" example:"1" readonly:"true"`                                  // increased on every update
+	Completed   bool       `json:"completed" example:"false" readonly:"true"`                             // set by complete, reopen
+	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2006-01-02T15:04:05Z" readonly:"true"` // set when completed
+}
+
+// List is named list of todo items
+type List struct {
+	ID   string `json:"id" format:"uuid" example:"1f0d6bc2-3c1a-4ed5-8d5e-3c0b5a1f4c9e" validate:"required,uuid"`
+	Name string `json:"name" example:"shopping" validate:"required"`
+
+	// server managed metadata, client supplied values are ignored
+	Owner     string    `json:"owner,omitempty" example:"owner@example.com" readonly:"true"` // email of list owner, not stored
+	CreatedAt time.Time `json:"created_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+	UpdatedAt time.Time `json:"updated_at" example:"2006-01-02T15:04:05Z" readonly:"true"`
+}
+
+// Validate validate list for save
+func (l *List) Validate() error {
+	return validator.New().Struct(l)
+}
+
+// SortLists sort lists by name and ID
+func SortLists(lists []List) {
+	sort.Slice(lists, func(i, j int) bool {
+		a, b := strings.ToLower(lists[i].Name), strings.ToLower(lists[j].Name)
+		if a != b {
+			return a < b
+		}
+		return lists[i].ID < lists[j].ID
+	})
+}
+
+// Complete mark item as completed at given time; completed time is kept if item already completed
+func (i *TodoItem) Complete(now time.Time) {
+	if i.Completed {
+		return
+	}
+
+	i.Completed = true
+	i.CompletedAt = &now
 }
 
-func Today() (d Date) {
-	d.Time = time.Now().UTC().Truncate(time.Hour * 24)
-	return
+// Reopen mark item as not completed
+func (i *TodoItem) Reopen() {
+	i.Completed = false
+	i.CompletedAt = nil
+}
+
+// Today returns today in UTC
+func Today() Date {
+	return TodayIn(time.UTC)
+}
+
+// TodayIn returns today in the location
+func TodayIn(loc *time.Location) Date {
+	return DateOf(time.Now().In(loc))
+}
+
+// DateOf returns date of the time in its location
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return NewDate(y, m, d)
 }
 
 // Date represents json full-date format, supports marshal json date
@@ -79,7 +333,7 @@ type Date struct {
 }
 
 func (d *Date) String() string {
-	return d.String()
+	return d.Format(RFC3339FullDate)
 }
 
 func (d *Date) UnmarshalJSON(data []byte) error {
@@ -104,5 +358,42 @@ func (d *Date) MarshalJSON() ([]byte, error) {
 
 // Validate validate items for save
 func (i *TodoItem) Validate() error {
+	if i.DueTime != "" && i.DueDate.IsZero() {
+		return errors.New("due_time requires due_date")
+	}
+
+	if len(i.Notes) > MaxNotesSize {
+		return errors.Errorf("notes exceeds %d bytes", MaxNotesSize)
+	}
+
 	return validator.New().Struct(i)
 }
+
+// Due returns time when item is due in the location; end of due date if due time is not set.
+// returns zero time if item has no due date
+func (i *TodoItem) Due(loc *time.Location) time.Time {
+	if i.DueDate.IsZero() {
+		return time.Time{}
+	}
+
+	y, m, d := i.DueDate.Date()
+	if i.DueTime == "" {
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+
+	t, err := time.Parse("15:04", i.DueTime)
+	if err != nil {
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, loc)
+}
+
+// Overdue returns true if item is open and its due time is passed at now
+func (i *TodoItem) Overdue(now time.Time) bool {
+	if i.Completed || i.DueDate.IsZero() {
+		return false
+	}
+
+	return !now.Before(i.Due(now.Location()))
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodoItemOverdue(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	require.NoError(t, err)
+
+	due := NewDate(2024, 3, 10)
+	tests := [...]struct {
+		name      string
+		dueDate   Date
+		dueTime   string
+		completed bool
+		now       time.Time
+		want      bool
+	}{
+		{"no due date", Date{}, "", false, time.Date(2024, 3, 11, 0, 0, 0, 0, seoul), false},
+		{"due day", due, "", false, time.Date(2024, 3, 10, 23, 59, 0, 0, seoul), false},
+		{"next day", due, "", false, time.Date(2024, 3, 11, 0, 0, 0, 0, seoul), true},
+		{"next day in UTC", due, "", false, time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC), false},
+		{"next day in UTC, user zone", due, "", false, time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC).In(seoul), true},
+		{"before due time", due, "15:00", false, time.Date(2024, 3, 10, 14, 59, 0, 0, seoul), false},
+		{"due time", due, "15:00", false, time.Date(2024, 3, 10, 15, 0, 0, 0, seoul), true},
+		{"completed", due, "15:00", true, time.Date(2024, 3, 11, 0, 0, 0, 0, seoul), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &TodoItem{DueDate: tt.dueDate, DueTime: tt.dueTime, Completed: tt.completed}
+			require.Equal(t, tt.want, item.Overdue(tt.now))
+		})
+	}
+}
+
+func TestTodoItemDueTimeJSON(t *testing.T) {
+	// date only payload is still valid
+	var item TodoItem
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"title","due_date":"2024-03-10"}`), &item))
+	require.Equal(t, NewDate(2024, 3, 10), item.DueDate)
+	require.Equal(t, "", item.DueTime)
+
+	data, err := json.Marshal(&item)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "due_time")
+
+	item.DueTime = "15:04"
+	data, err = json.Marshal(&item)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"due_date":"2024-03-10","due_time":"15:04"`)
+}
+
+func TestTodoItemValidateNotes(t *testing.T) {
+	item := TodoItem{ID: "628b92ab-6d95-4fbe-b7c6-09cf5cd8941c", Title: "title", Notes: strings.Repeat("a", MaxNotesSize)}
+	require.NoError(t, item.Validate())
+
+	item.Notes += "a"
+	require.Error(t, item.Validate())
+}
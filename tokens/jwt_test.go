@@ -49,7 +49,7 @@ func TestExpired(t *testing.T) {
 	token, err := New("hello", -time.Minute)
 	require.NoError(t, err)
 	_, err = Parse(token)
-	require.True(t, expired(err))
+	require.True(t, IsExpired(err))
 }
 
 func TestJWT(t *testing.T) {